@@ -38,6 +38,27 @@ const (
 	// ConfigMap/Secret when the content is a template and needs variable substitution
 	// using event/cluster data
 	InstantiateAnnotation = "projectsveltos.io/instantiate"
+
+	// SopsEncryptedAnnotation is the annotation that must be set on a referenced
+	// ConfigMap/Secret when the content is encrypted with SOPS using age. Content is
+	// decrypted, then instantiated, and always written to a generated Secret.
+	// EventTriggers created by tenant admins cannot reference SOPS encrypted content
+	SopsEncryptedAnnotation = "projectsveltos.io/sops-encrypted"
+
+	// TemplateAllowedFunctionsAnnotation can be set by the platform admin on the RoleRequests of a
+	// tenant admin. Value is a comma separated list of template functions EventTriggers created by
+	// the tenant admin can use ("*" for all). It overrides the list configured at manager level
+	TemplateAllowedFunctionsAnnotation = "eventtrigger.lib.projectsveltos.io/template-allowed-functions"
+
+	// ReprocessAnnotation is the annotation that can be set on an EventTrigger to request
+	// all current EventReports to be processed again, regardless of their phase.
+	// Value is either "all" or a comma separated list of clusters (namespace/name).
+	// Once the request is completed, the annotation is removed and completion is reported
+	// in the EventTrigger Status.
+	ReprocessAnnotation = "eventtrigger.lib.projectsveltos.io/reprocess"
+
+	// ReprocessAll is the ReprocessAnnotation value requesting all clusters to be reprocessed
+	ReprocessAll = "all"
 )
 
 type CloudEventAction string
@@ -52,6 +73,32 @@ const (
 	CloudEventActionDelete = CloudEventAction("Delete")
 )
 
+// +kubebuilder:validation:Enum:=CreateAnyway;Wait
+type UnresolvedDestinationPolicy string
+
+const (
+	// UnresolvedDestinationPolicyCreateAnyway indicates that ClusterProfiles are created even if
+	// some of the destination clusters do not exist or are not ready.
+	UnresolvedDestinationPolicyCreateAnyway = UnresolvedDestinationPolicy("CreateAnyway")
+
+	// UnresolvedDestinationPolicyWait indicates that ClusterProfiles are not created till all
+	// destination clusters exist and are ready.
+	UnresolvedDestinationPolicyWait = UnresolvedDestinationPolicy("Wait")
+)
+
+// +kubebuilder:validation:Enum:=DryRun;Hold
+type ApprovalMode string
+
+const (
+	// ApprovalModeDryRun indicates that new or changed ClusterProfiles are created/updated
+	// with SyncMode set to DryRun till approved.
+	ApprovalModeDryRun = ApprovalMode("DryRun")
+
+	// ApprovalModeHold indicates that new ClusterProfiles match no cluster and changes to
+	// existing ClusterProfiles are not applied till approved.
+	ApprovalModeHold = ApprovalMode("Hold")
+)
+
 type GeneratorReference struct {
 	// Namespace of the referenced resource.
 	// Namespace can be left empty. In such a case, namespace will
@@ -75,6 +122,17 @@ type GeneratorReference struct {
 	// +kubebuilder:validation:MinLength=1
 	InstantiatedResourceNameFormat string `json:"nameFormat"`
 
+	// InstantiatedNamespace defines a template used to generate the namespace
+	// of the resource created in the management cluster. The template can reference:
+	// - `.Cluster.metadata.namespace`: namespace of the managed cluster
+	// - `.Cluster.metadata.name`: name of the managed cluster
+	// - `.Cluster.kind`: kind of the managed cluster object
+	// If not set, EventTrigger.Spec.InstantiatedNamespace is used.
+	// The namespace must exist. Same tenant restrictions of EventTrigger.Spec.InstantiatedNamespace
+	// apply.
+	// +optional
+	InstantiatedNamespace string `json:"instantiatedNamespace,omitempty"`
+
 	// Optional indicates that the referenced resource is not mandatory.
 	// If set to true and the resource is not found, the error will be ignored,
 	// and Sveltos will continue processing other generators.
@@ -83,7 +141,81 @@ type GeneratorReference struct {
 	Optional bool `json:"optional,omitempty"`
 }
 
+// ScheduleWindow defines a window during which an EventTrigger is allowed to
+// turn events into ClusterProfile changes.
+type ScheduleWindow struct {
+	// Start is a standard cron expression (minute hour day-of-month month day-of-week)
+	// defining when the window opens. For instance "0 22 * * 1-5" opens the window
+	// every weekday at 22:00.
+	// +kubebuilder:validation:MinLength=1
+	Start string `json:"start"`
+
+	// Duration is how long the window stays open once opened. Must be greater than zero.
+	// +kubebuilder:validation:XValidation:rule="duration(self) > duration('0s')",message="duration must be greater than zero"
+	Duration metav1.Duration `json:"duration"`
+}
+
+// Schedule defines when an EventTrigger is allowed to instantiate ClusterProfiles,
+// ConfigMaps, Secrets and resources.
+type Schedule struct {
+	// Windows lists all allowed windows. Changes are applied only while at least
+	// one window is open.
+	// +kubebuilder:validation:MinItems=1
+	Windows []ScheduleWindow `json:"windows"`
+
+	// TimeZone is the IANA name of the time zone used to evaluate the cron
+	// expressions (for instance "Europe/Rome"). If not set, UTC is used.
+	// +optional
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// ApprovalPolicy requires a manual approval before new or changed ClusterProfiles
+// are deployed with the EventTrigger SyncMode.
+// A ClusterProfile pending approval is annotated with
+// "eventtrigger.lib.projectsveltos.io/pending-approval" set to the hash of the desired
+// ClusterProfile Spec. It is approved by setting the annotation
+// "eventtrigger.lib.projectsveltos.io/approved" on the ClusterProfile to either such hash
+// or "true".
+type ApprovalPolicy struct {
+	// Mode defines what happens to a ClusterProfile while approval is pending.
+	// DryRun: ClusterProfile is created/updated with SyncMode DryRun.
+	// Hold: an existing ClusterProfile is not updated, while a new one is created
+	// matching no cluster.
+	// +kubebuilder:default:=DryRun
+	// +optional
+	Mode ApprovalMode `json:"mode,omitempty"`
+}
+
+// ResourceGeneratorReference references a ConfigMap whose Data section contains
+// templates of Kubernetes resources of any kind (one or more resources per key,
+// separated by "---").
+// Namespaced resources must set metadata.namespace.
+type ResourceGeneratorReference struct {
+	// Namespace of the referenced ConfigMap.
+	// Namespace can be left empty. In such a case, namespace will
+	// be implicit set to cluster's namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the referenced ConfigMap.
+	// Name can be expressed as a template and instantiate using
+	// - `.Cluster.metadata.namespace`: namespace of the managed cluster
+	// - `.Cluster.metadata.name`: name of the managed cluster
+	// - `.Cluster.kind`: kind of the managed cluster object
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Optional indicates that the referenced ConfigMap is not mandatory.
+	// If set to true and the ConfigMap is not found, the error will be ignored,
+	// and Sveltos will continue processing other generators.
+	// +kubebuilder:default:=false
+	// +optional
+	Optional bool `json:"optional,omitempty"`
+}
+
 // EventTriggerSpec defines the desired state of EventTrigger
+// +kubebuilder:validation:XValidation:rule="has(self.eventSourceName) != has(self.eventSource)",message="exactly one of eventSourceName and eventSource must be set"
+// +kubebuilder:validation:XValidation:rule="!has(self.destinationClusters) || size(self.destinationClusters) == 0 || (!has(self.destinationCluster) && (!has(self.destinationClusterSelector) || ((!has(self.destinationClusterSelector.matchLabels) || size(self.destinationClusterSelector.matchLabels) == 0) && (!has(self.destinationClusterSelector.matchExpressions) || size(self.destinationClusterSelector.matchExpressions) == 0))))",message="destinationClusters cannot be set along with destinationClusterSelector or destinationCluster"
 type EventTriggerSpec struct {
 	// SourceClusterSelector identifies clusters to associate to.
 	// This represents the set of clusters where Sveltos will watch for
@@ -100,6 +232,8 @@ type EventTriggerSpec struct {
 	// If DestinationClusterSelector is set though, when an event happens in any of the
 	// cluster identified by SourceClusterSelector, add-ons will be deployed in each of
 	// the cluster indentified by DestinationClusterSelector.
+	// MatchLabels values and MatchExpressions keys and values are templates instantiated
+	// using the event context.
 	// +optional
 	DestinationClusterSelector libsveltosv1beta1.Selector `json:"destinationClusterSelector,omitempty"`
 
@@ -109,6 +243,33 @@ type EventTriggerSpec struct {
 	// +optional
 	DestinationCluster *corev1.ObjectReference `json:"destinationCluster,omitempty"`
 
+	// DestinationClusters identifies the clusters to deploy add-ons to.
+	// This field is a template instantiated using the event context. Once instantiated, it
+	// must be a YAML (or JSON) list of cluster references (apiVersion, kind, namespace and name).
+	// Kind must be either Cluster or SveltosCluster.
+	// The list can be empty, in which case add-ons are not deployed for the event.
+	// Cannot be set if DestinationClusterSelector or DestinationCluster is set.
+	// +optional
+	DestinationClusters string `json:"destinationClusters,omitempty"`
+
+	// IncludeSourceCluster, when DestinationClusters is set, adds the cluster where the
+	// event happened to the clusters add-ons are deployed to.
+	// +optional
+	IncludeSourceCluster bool `json:"includeSourceCluster,omitempty"`
+
+	// DestinationClusterSetRefs identifies the ClusterSets add-ons are deployed to. Each entry is
+	// the name of a ClusterSet and is a template instantiated using the event context.
+	// Add-ons are deployed to the clusters currently selected by the ClusterSets.
+	// +optional
+	DestinationClusterSetRefs []string `json:"destinationClusterSetRefs,omitempty"`
+
+	// UnresolvedDestinationPolicy defines what happens when a cluster set by DestinationCluster or
+	// DestinationClusters, once instantiated, does not exist or is not ready. Unresolved destination
+	// clusters are always reported in Status.
+	// +kubebuilder:default:=CreateAnyway
+	// +optional
+	UnresolvedDestinationPolicy UnresolvedDestinationPolicy `json:"unresolvedDestinationPolicy,omitempty"`
+
 	// Multiple resources in a managed cluster can be a match for referenced
 	// EventSource. OneForEvent indicates whether a ClusterProfile for all
 	// resource (OneForEvent = false) or one per resource (OneForEvent = true)
@@ -117,6 +278,28 @@ type EventTriggerSpec struct {
 	// +optional
 	OneForEvent bool `json:"oneForEvent,omitempty"`
 
+	// DeletionGracePeriod is the amount of time ClusterProfiles, ConfigMaps, Secrets and
+	// resources generated by this EventTrigger are kept after the resources/events they were
+	// generated for stop matching the EventSource.
+	// Stale objects are annotated with the time they were found stale and deleted only if,
+	// by the end of the grace period, the match has not returned. This prevents a transient
+	// flap in a managed cluster from withdrawing add-ons.
+	// If not set, stale objects are removed immediately.
+	// +optional
+	DeletionGracePeriod *metav1.Duration `json:"deletionGracePeriod,omitempty"`
+
+	// Schedule defines the windows during which changes in matching resources/events
+	// are turned into ClusterProfile (and ConfigMap, Secret and resource) changes.
+	// Outside a window, changes are queued and applied when the next window opens.
+	// If not set, changes are always applied immediately.
+	// +optional
+	Schedule *Schedule `json:"schedule,omitempty"`
+
+	// ApprovalPolicy, if set, requires new or changed ClusterProfiles to be approved
+	// before those are deployed with the configured SyncMode.
+	// +optional
+	ApprovalPolicy *ApprovalPolicy `json:"approvalPolicy,omitempty"`
+
 	// EventSourceName is the name of the referenced EventSource.
 	// Resources contained in the referenced ConfigMaps/Secrets and HelmCharts
 	// will be customized using information from resources matching the EventSource
@@ -125,8 +308,18 @@ type EventTriggerSpec struct {
 	// - cluster namespace: .Cluster.metadata.namespace
 	// - cluster name: .Cluster.metadata.name
 	// - cluster type: .Cluster.kind
+	// Exactly one of EventSourceName and EventSource must be set.
 	// +kubebuilder:validation:MinLength=1
-	EventSourceName string `json:"eventSourceName"`
+	// +optional
+	EventSourceName string `json:"eventSourceName,omitempty"`
+
+	// EventSource defines the EventSource inline. It is an alternative to
+	// EventSourceName for EventSources used only by this EventTrigger.
+	// Sveltos creates an EventSource owned by this EventTrigger, named after it,
+	// and removes it when the EventTrigger is deleted.
+	// Exactly one of EventSourceName and EventSource must be set.
+	// +optional
+	EventSource *libsveltosv1beta1.EventSourceSpec `json:"eventSource,omitempty"`
 
 	// The ConfigMapGenerator field references ConfigMaps containing templates.
 	// These referenced ConfigMaps will be dynamically instantiated in the management cluster
@@ -140,6 +333,17 @@ type EventTriggerSpec struct {
 	// +optional
 	SecretGenerator []GeneratorReference `json:"secretGenerator,omitempty"`
 
+	// The ResourceGenerator field references ConfigMaps containing templates of Kubernetes
+	// resources of any kind (for instance ClusterSets, SveltosClusters, Flux GitRepositories
+	// or RoleRequests). Templates are instantiated using event data and the resulting
+	// resources are created/updated in the management cluster.
+	// event-manager is not granted permissions to create/update/delete arbitrary kinds: platform
+	// admins must grant it the permissions needed for the generated kinds.
+	// If the EventTrigger was created by a tenant admin, resources are created/updated only if the
+	// tenant service account is allowed to create and update them.
+	// +optional
+	ResourceGenerator []ResourceGeneratorReference `json:"resourceGenerator,omitempty"`
+
 	// SyncMode specifies how features are synced in a matching workload cluster.
 	// - OneTime means, first time a workload cluster matches the ClusterProfile,
 	// features will be deployed in such cluster. Any subsequent feature configuration
@@ -242,6 +446,22 @@ type EventTriggerSpec struct {
 	// +optional
 	PolicyRefs []configv1beta1.PolicyRef `json:"policyRefs,omitempty"`
 
+	// InstantiatedNamespace defines a template used to generate the namespace of the
+	// ConfigMaps/Secrets created in the management cluster when templates referenced
+	// in PolicyRefs, in HelmCharts/KustomizationRefs ValuesFrom and in ConfigMapGenerator/SecretGenerator
+	// (unless those define their own InstantiatedNamespace) are instantiated.
+	// The template can reference:
+	// - `.Cluster.metadata.namespace`: namespace of the managed cluster
+	// - `.Cluster.metadata.name`: name of the managed cluster
+	// - `.Cluster.kind`: kind of the managed cluster object
+	// If not set, the namespace configured in the event-manager (projectsveltos by default)
+	// is used. The namespace must exist.
+	// If the EventTrigger was created by a tenant admin, ConfigMaps/Secrets are instantiated in
+	// a namespace other than the configured one only if the tenant service account is allowed
+	// to create and update them there.
+	// +optional
+	InstantiatedNamespace string `json:"instantiatedNamespace,omitempty"`
+
 	// Helm charts to be deployed in the matching clusters based on EventSource.
 	// This field will be directly transferred to the ClusterProfile Spec
 	// generated in response to events.
@@ -303,6 +523,183 @@ type EventTriggerSpec struct {
 	ExtraAnnotations map[string]string `json:"extraAnnotations,omitempty"`
 }
 
+// ScheduleStatus reports the status of an EventTrigger Schedule
+type ScheduleStatus struct {
+	// InWindow indicates whether a window is currently open
+	InWindow bool `json:"inWindow"`
+
+	// NextWindow is the time the next window opens
+	// +optional
+	NextWindow *metav1.Time `json:"nextWindow,omitempty"`
+
+	// PendingChanges is the number of EventReports whose changes are queued
+	// waiting for a window to open
+	// +optional
+	PendingChanges int `json:"pendingChanges,omitempty"`
+
+	// FailureMessage provides more information if Schedule cannot be evaluated.
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// PendingApproval represents a ClusterProfile waiting for a manual approval
+type PendingApproval struct {
+	// ClusterProfileName is the name of the ClusterProfile pending approval
+	ClusterProfileName string `json:"clusterProfileName"`
+
+	// ClusterRef references the cluster where the event happened
+	ClusterRef corev1.ObjectReference `json:"clusterRef"`
+
+	// TriggeringResource references the resource whose event caused the change.
+	// Not set when a single ClusterProfile is created for all matching resources.
+	// +optional
+	TriggeringResource *corev1.ObjectReference `json:"triggeringResource,omitempty"`
+
+	// CloudEventSource is the source of the CloudEvent that caused the change.
+	// +optional
+	CloudEventSource string `json:"cloudEventSource,omitempty"`
+
+	// CloudEventSubject is the subject of the CloudEvent that caused the change.
+	// +optional
+	CloudEventSubject string `json:"cloudEventSubject,omitempty"`
+
+	// Hash is the hash of the ClusterProfile Spec pending approval. Set the
+	// ClusterProfile approved annotation to this value to approve it.
+	Hash string `json:"hash"`
+
+	// Diff is the difference between the previous and the pending ClusterProfile Spec
+	// +optional
+	Diff string `json:"diff,omitempty"`
+}
+
+// ReprocessStatus reports the last completed reprocess request
+type ReprocessStatus struct {
+	// Request is the value of the ReprocessAnnotation when the request was made
+	Request string `json:"request"`
+
+	// ProcessedClusters lists the clusters whose EventReports were processed again
+	// +optional
+	ProcessedClusters []corev1.ObjectReference `json:"processedClusters,omitempty"`
+
+	// CompletionTime is the time the request was completed
+	CompletionTime metav1.Time `json:"completionTime"`
+}
+
+// +kubebuilder:validation:Enum:=Provisioning;Provisioned;Failed;Conflict
+type DeploymentStatus string
+
+const (
+	// DeploymentStatusProvisioning indicates add-ons are being deployed
+	DeploymentStatusProvisioning = DeploymentStatus("Provisioning")
+
+	// DeploymentStatusProvisioned indicates all add-ons have been deployed
+	DeploymentStatusProvisioned = DeploymentStatus("Provisioned")
+
+	// DeploymentStatusFailed indicates deploying add-ons failed
+	DeploymentStatusFailed = DeploymentStatus("Failed")
+
+	// DeploymentStatusConflict indicates add-ons are already managed by another profile
+	DeploymentStatusConflict = DeploymentStatus("Conflict")
+)
+
+// DestinationStatus reports the deployment status of a generated ClusterProfile in
+// one destination cluster
+type DestinationStatus struct {
+	// Cluster references the destination cluster
+	Cluster corev1.ObjectReference `json:"cluster"`
+
+	// Status is the deployment status in the destination cluster
+	Status DeploymentStatus `json:"status"`
+
+	// FailureMessage provides more information when Status is Failed or Conflict
+	// +optional
+	FailureMessage *string `json:"failureMessage,omitempty"`
+}
+
+// GeneratedClusterProfileStatus reports the deployment status of a ClusterProfile
+// generated by the EventTrigger
+type GeneratedClusterProfileStatus struct {
+	// ClusterProfileName is the name of the generated ClusterProfile
+	ClusterProfileName string `json:"clusterProfileName"`
+
+	// SourceCluster references the cluster where the event happened
+	SourceCluster corev1.ObjectReference `json:"sourceCluster"`
+
+	// TriggeringResource references the resource whose event caused the ClusterProfile
+	// to be generated. Not set when a single ClusterProfile is created for all matching
+	// resources.
+	// +optional
+	TriggeringResource *corev1.ObjectReference `json:"triggeringResource,omitempty"`
+
+	// DestinationMatchingClusterRefs references the clusters the generated ClusterProfile
+	// currently matches
+	// +optional
+	DestinationMatchingClusterRefs []corev1.ObjectReference `json:"destinationMatchingClusterRefs,omitempty"`
+
+	// Destinations reports the deployment status in each destination cluster
+	// +optional
+	Destinations []DestinationStatus `json:"destinations,omitempty"`
+}
+
+// UnresolvedDestination reports a destination cluster, instantiated for an event, which does not
+// exist or is not ready
+type UnresolvedDestination struct {
+	// Cluster references the destination cluster
+	Cluster corev1.ObjectReference `json:"cluster"`
+
+	// Reason explains why the destination cluster is not resolved
+	Reason string `json:"reason"`
+
+	// SourceCluster references the cluster where the event happened
+	SourceCluster corev1.ObjectReference `json:"sourceCluster"`
+
+	// TriggeringResource references the resource whose event the destination was instantiated for.
+	// Not set when a single ClusterProfile is created for all matching resources.
+	// +optional
+	TriggeringResource *corev1.ObjectReference `json:"triggeringResource,omitempty"`
+
+	// CloudEventSource is the source of the CloudEvent the destination was instantiated for.
+	// +optional
+	CloudEventSource string `json:"cloudEventSource,omitempty"`
+
+	// CloudEventSubject is the subject of the CloudEvent the destination was instantiated for.
+	// +optional
+	CloudEventSubject string `json:"cloudEventSubject,omitempty"`
+}
+
+// InstantiationFailure reports why resources could not be instantiated for the events in a
+// source cluster
+type InstantiationFailure struct {
+	// Cluster references the cluster where the events happened
+	Cluster corev1.ObjectReference `json:"cluster"`
+
+	// Message explains why resources could not be instantiated
+	Message string `json:"message"`
+}
+
+// BlockedEvent reports an event no resource was instantiated for because the tenant admin who
+// created the EventTrigger is not allowed to use the source or a destination cluster
+type BlockedEvent struct {
+	// SourceCluster references the cluster where the event happened
+	SourceCluster corev1.ObjectReference `json:"sourceCluster"`
+
+	// TriggeringResource references the resource whose event was blocked.
+	// Not set when a single ClusterProfile is created for all matching resources.
+	// +optional
+	TriggeringResource *corev1.ObjectReference `json:"triggeringResource,omitempty"`
+
+	// CloudEventSource is the source of the CloudEvent which was blocked.
+	// +optional
+	CloudEventSource string `json:"cloudEventSource,omitempty"`
+
+	// CloudEventSubject is the subject of the CloudEvent which was blocked.
+	// +optional
+	CloudEventSubject string `json:"cloudEventSubject,omitempty"`
+
+	// Reason explains why the event was blocked
+	Reason string `json:"reason"`
+}
+
 // EventTriggerStatus defines the observed state of EventTrigger
 type EventTriggerStatus struct {
 	// MatchingClusterRefs reference all the cluster-api Cluster currently matching
@@ -310,8 +707,8 @@ type EventTriggerStatus struct {
 	// +optional
 	MatchingClusterRefs []corev1.ObjectReference `json:"matchingClusters,omitempty"`
 
-	// DestinationMatchingClusterRefs reference all the cluster-api Cluster currently matching
-	// ClusterProfile DestinationClusterSelector
+	// DestinationMatchingClusterRefs reference all the clusters currently matched by any of the
+	// generated ClusterProfiles. Per event destinations are reported in GeneratedClusterProfiles.
 	// +optional
 	DestinationMatchingClusterRefs []corev1.ObjectReference `json:"destinationMatchingClusterRefs,omitempty"`
 
@@ -319,6 +716,44 @@ type EventTriggerStatus struct {
 	// cluster.
 	// +optional
 	ClusterInfo []libsveltosv1beta1.ClusterInfo `json:"clusterInfo,omitempty"`
+
+	// ScheduleStatus reports the status of the Schedule, if any is defined.
+	// +optional
+	ScheduleStatus *ScheduleStatus `json:"scheduleStatus,omitempty"`
+
+	// PendingApprovals lists the ClusterProfiles waiting for a manual approval
+	// +optional
+	PendingApprovals []PendingApproval `json:"pendingApprovals,omitempty"`
+
+	// Reprocess reports the last completed reprocess request
+	// +optional
+	Reprocess *ReprocessStatus `json:"reprocess,omitempty"`
+
+	// GeneratedClusterProfiles reports, for each generated ClusterProfile, whether add-ons
+	// were deployed in the destination clusters
+	// +optional
+	GeneratedClusterProfiles []GeneratedClusterProfileStatus `json:"generatedClusterProfiles,omitempty"`
+
+	// UnresolvedDestinations lists the destination clusters, instantiated from DestinationCluster or
+	// DestinationClusters, which do not exist or are not ready
+	// +optional
+	UnresolvedDestinations []UnresolvedDestination `json:"unresolvedDestinations,omitempty"`
+
+	// InstantiationFailures lists, per source cluster, events that could not be instantiated
+	// because the tenant admin who created the EventTrigger is not allowed to create/update
+	// the generated resources
+	// +optional
+	InstantiationFailures []InstantiationFailure `json:"instantiationFailures,omitempty"`
+
+	// BlockedEvents lists the events no resource was instantiated for because the tenant admin
+	// who created the EventTrigger is not allowed to use the source or a destination cluster
+	// +optional
+	BlockedEvents []BlockedEvent `json:"blockedEvents,omitempty"`
+
+	// GeneratedResourceKinds lists all kinds of resources ever generated by ResourceGenerators.
+	// Stale generated resources are looked for among those kinds.
+	// +optional
+	GeneratedResourceKinds []metav1.GroupVersionKind `json:"generatedResourceKinds,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -22,13 +22,71 @@ package v1beta1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 
-	apiv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
-	libsveltosapiv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	addon_controllerapiv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	apiv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ApprovalPolicy) DeepCopyInto(out *ApprovalPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ApprovalPolicy.
+func (in *ApprovalPolicy) DeepCopy() *ApprovalPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ApprovalPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BlockedEvent) DeepCopyInto(out *BlockedEvent) {
+	*out = *in
+	out.SourceCluster = in.SourceCluster
+	if in.TriggeringResource != nil {
+		in, out := &in.TriggeringResource, &out.TriggeringResource
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BlockedEvent.
+func (in *BlockedEvent) DeepCopy() *BlockedEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(BlockedEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DestinationStatus) DeepCopyInto(out *DestinationStatus) {
+	*out = *in
+	out.Cluster = in.Cluster
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DestinationStatus.
+func (in *DestinationStatus) DeepCopy() *DestinationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DestinationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventTrigger) DeepCopyInto(out *EventTrigger) {
 	*out = *in
@@ -103,6 +161,31 @@ func (in *EventTriggerSpec) DeepCopyInto(out *EventTriggerSpec) {
 		*out = new(v1.ObjectReference)
 		**out = **in
 	}
+	if in.DestinationClusterSetRefs != nil {
+		in, out := &in.DestinationClusterSetRefs, &out.DestinationClusterSetRefs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DeletionGracePeriod != nil {
+		in, out := &in.DeletionGracePeriod, &out.DeletionGracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.Schedule != nil {
+		in, out := &in.Schedule, &out.Schedule
+		*out = new(Schedule)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ApprovalPolicy != nil {
+		in, out := &in.ApprovalPolicy, &out.ApprovalPolicy
+		*out = new(ApprovalPolicy)
+		**out = **in
+	}
+	if in.EventSource != nil {
+		in, out := &in.EventSource, &out.EventSource
+		*out = new(apiv1beta1.EventSourceSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ConfigMapGenerator != nil {
 		in, out := &in.ConfigMapGenerator, &out.ConfigMapGenerator
 		*out = make([]GeneratorReference, len(*in))
@@ -113,6 +196,11 @@ func (in *EventTriggerSpec) DeepCopyInto(out *EventTriggerSpec) {
 		*out = make([]GeneratorReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ResourceGenerator != nil {
+		in, out := &in.ResourceGenerator, &out.ResourceGenerator
+		*out = make([]ResourceGeneratorReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.MaxUpdate != nil {
 		in, out := &in.MaxUpdate, &out.MaxUpdate
 		*out = new(intstr.IntOrString)
@@ -125,45 +213,45 @@ func (in *EventTriggerSpec) DeepCopyInto(out *EventTriggerSpec) {
 	}
 	if in.TemplateResourceRefs != nil {
 		in, out := &in.TemplateResourceRefs, &out.TemplateResourceRefs
-		*out = make([]apiv1beta1.TemplateResourceRef, len(*in))
+		*out = make([]addon_controllerapiv1beta1.TemplateResourceRef, len(*in))
 		copy(*out, *in)
 	}
 	if in.PolicyRefs != nil {
 		in, out := &in.PolicyRefs, &out.PolicyRefs
-		*out = make([]apiv1beta1.PolicyRef, len(*in))
+		*out = make([]addon_controllerapiv1beta1.PolicyRef, len(*in))
 		copy(*out, *in)
 	}
 	if in.HelmCharts != nil {
 		in, out := &in.HelmCharts, &out.HelmCharts
-		*out = make([]apiv1beta1.HelmChart, len(*in))
+		*out = make([]addon_controllerapiv1beta1.HelmChart, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.KustomizationRefs != nil {
 		in, out := &in.KustomizationRefs, &out.KustomizationRefs
-		*out = make([]apiv1beta1.KustomizationRef, len(*in))
+		*out = make([]addon_controllerapiv1beta1.KustomizationRef, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.ValidateHealths != nil {
 		in, out := &in.ValidateHealths, &out.ValidateHealths
-		*out = make([]libsveltosapiv1beta1.ValidateHealth, len(*in))
+		*out = make([]apiv1beta1.ValidateHealth, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
-		*out = make([]libsveltosapiv1beta1.Patch, len(*in))
+		*out = make([]apiv1beta1.Patch, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 	if in.DriftExclusions != nil {
 		in, out := &in.DriftExclusions, &out.DriftExclusions
-		*out = make([]libsveltosapiv1beta1.DriftExclusion, len(*in))
+		*out = make([]apiv1beta1.DriftExclusion, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -209,11 +297,59 @@ func (in *EventTriggerStatus) DeepCopyInto(out *EventTriggerStatus) {
 	}
 	if in.ClusterInfo != nil {
 		in, out := &in.ClusterInfo, &out.ClusterInfo
-		*out = make([]libsveltosapiv1beta1.ClusterInfo, len(*in))
+		*out = make([]apiv1beta1.ClusterInfo, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ScheduleStatus != nil {
+		in, out := &in.ScheduleStatus, &out.ScheduleStatus
+		*out = new(ScheduleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PendingApprovals != nil {
+		in, out := &in.PendingApprovals, &out.PendingApprovals
+		*out = make([]PendingApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Reprocess != nil {
+		in, out := &in.Reprocess, &out.Reprocess
+		*out = new(ReprocessStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.GeneratedClusterProfiles != nil {
+		in, out := &in.GeneratedClusterProfiles, &out.GeneratedClusterProfiles
+		*out = make([]GeneratedClusterProfileStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnresolvedDestinations != nil {
+		in, out := &in.UnresolvedDestinations, &out.UnresolvedDestinations
+		*out = make([]UnresolvedDestination, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.InstantiationFailures != nil {
+		in, out := &in.InstantiationFailures, &out.InstantiationFailures
+		*out = make([]InstantiationFailure, len(*in))
+		copy(*out, *in)
+	}
+	if in.BlockedEvents != nil {
+		in, out := &in.BlockedEvents, &out.BlockedEvents
+		*out = make([]BlockedEvent, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.GeneratedResourceKinds != nil {
+		in, out := &in.GeneratedResourceKinds, &out.GeneratedResourceKinds
+		*out = make([]metav1.GroupVersionKind, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EventTriggerStatus.
@@ -226,6 +362,39 @@ func (in *EventTriggerStatus) DeepCopy() *EventTriggerStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GeneratedClusterProfileStatus) DeepCopyInto(out *GeneratedClusterProfileStatus) {
+	*out = *in
+	out.SourceCluster = in.SourceCluster
+	if in.TriggeringResource != nil {
+		in, out := &in.TriggeringResource, &out.TriggeringResource
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+	if in.DestinationMatchingClusterRefs != nil {
+		in, out := &in.DestinationMatchingClusterRefs, &out.DestinationMatchingClusterRefs
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.Destinations != nil {
+		in, out := &in.Destinations, &out.Destinations
+		*out = make([]DestinationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GeneratedClusterProfileStatus.
+func (in *GeneratedClusterProfileStatus) DeepCopy() *GeneratedClusterProfileStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GeneratedClusterProfileStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GeneratorReference) DeepCopyInto(out *GeneratorReference) {
 	*out = *in
@@ -240,3 +409,158 @@ func (in *GeneratorReference) DeepCopy() *GeneratorReference {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstantiationFailure) DeepCopyInto(out *InstantiationFailure) {
+	*out = *in
+	out.Cluster = in.Cluster
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstantiationFailure.
+func (in *InstantiationFailure) DeepCopy() *InstantiationFailure {
+	if in == nil {
+		return nil
+	}
+	out := new(InstantiationFailure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PendingApproval) DeepCopyInto(out *PendingApproval) {
+	*out = *in
+	out.ClusterRef = in.ClusterRef
+	if in.TriggeringResource != nil {
+		in, out := &in.TriggeringResource, &out.TriggeringResource
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PendingApproval.
+func (in *PendingApproval) DeepCopy() *PendingApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PendingApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReprocessStatus) DeepCopyInto(out *ReprocessStatus) {
+	*out = *in
+	if in.ProcessedClusters != nil {
+		in, out := &in.ProcessedClusters, &out.ProcessedClusters
+		*out = make([]v1.ObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	in.CompletionTime.DeepCopyInto(&out.CompletionTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReprocessStatus.
+func (in *ReprocessStatus) DeepCopy() *ReprocessStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReprocessStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceGeneratorReference) DeepCopyInto(out *ResourceGeneratorReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceGeneratorReference.
+func (in *ResourceGeneratorReference) DeepCopy() *ResourceGeneratorReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceGeneratorReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Schedule) DeepCopyInto(out *Schedule) {
+	*out = *in
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]ScheduleWindow, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Schedule.
+func (in *Schedule) DeepCopy() *Schedule {
+	if in == nil {
+		return nil
+	}
+	out := new(Schedule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleStatus) DeepCopyInto(out *ScheduleStatus) {
+	*out = *in
+	if in.NextWindow != nil {
+		in, out := &in.NextWindow, &out.NextWindow
+		*out = (*in).DeepCopy()
+	}
+	if in.FailureMessage != nil {
+		in, out := &in.FailureMessage, &out.FailureMessage
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleStatus.
+func (in *ScheduleStatus) DeepCopy() *ScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScheduleWindow) DeepCopyInto(out *ScheduleWindow) {
+	*out = *in
+	out.Duration = in.Duration
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScheduleWindow.
+func (in *ScheduleWindow) DeepCopy() *ScheduleWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ScheduleWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnresolvedDestination) DeepCopyInto(out *UnresolvedDestination) {
+	*out = *in
+	out.Cluster = in.Cluster
+	out.SourceCluster = in.SourceCluster
+	if in.TriggeringResource != nil {
+		in, out := &in.TriggeringResource, &out.TriggeringResource
+		*out = new(v1.ObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnresolvedDestination.
+func (in *UnresolvedDestination) DeepCopy() *UnresolvedDestination {
+	if in == nil {
+		return nil
+	}
+	out := new(UnresolvedDestination)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -21,6 +21,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -74,6 +75,23 @@ var (
 	syncPeriod            time.Duration
 	healthAddr            string
 	capiOnboardAnnotation string
+	instantiatedNamespace string
+	orphanSweepInterval   time.Duration
+	orphanSweepDryRun     bool
+	auditHistorySize      int
+	remoteClientIdleTime  time.Duration
+	leaderElect           bool
+	leaderElectLease      time.Duration
+	leaderElectRenew      time.Duration
+	leaderElectRetry      time.Duration
+	collectionDeadline    time.Duration
+	triggerSharding       bool
+	replicaID             string
+	podNamespace          string
+	sopsAgeKeySecret      string
+	templateFunctions     []string
+	templateTimeout       time.Duration
+	templateMaxOutput     int
 )
 
 const (
@@ -85,6 +103,10 @@ const (
 //+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
 //+kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 
+// Add RBAC for leader election.
+//+kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
 func main() {
 	scheme, err := controllers.InitScheme()
 	if err != nil {
@@ -100,6 +122,28 @@ func main() {
 
 	ctrl.SetLogger(klog.Background())
 
+	if triggerSharding && leaderElect {
+		setupLog.Error(nil, "--trigger-sharding and --leader-elect are mutually exclusive")
+		os.Exit(1)
+	}
+	var sopsAgeKeySecretNamespace, sopsAgeKeySecretName string
+	if sopsAgeKeySecret != "" {
+		var ok bool
+		sopsAgeKeySecretNamespace, sopsAgeKeySecretName, ok = strings.Cut(sopsAgeKeySecret, "/")
+		if !ok || sopsAgeKeySecretNamespace == "" || sopsAgeKeySecretName == "" {
+			setupLog.Error(nil, "--sops-age-key-secret must be in the form namespace/name")
+			os.Exit(1)
+		}
+	}
+	if triggerSharding && replicaID == "" {
+		// Pod hostname is the pod name
+		replicaID, err = os.Hostname()
+		if err != nil {
+			setupLog.Error(err, "unable to get replica identity")
+			os.Exit(1)
+		}
+	}
+
 	ctrlOptions := ctrl.Options{
 		Scheme:                 scheme,
 		Metrics:                getDiagnosticsOptions(),
@@ -111,6 +155,14 @@ func main() {
 		Cache: cache.Options{
 			SyncPeriod: &syncPeriod,
 		},
+		LeaderElection:   leaderElect,
+		LeaderElectionID: getLeaderElectionID(),
+		LeaseDuration:    &leaderElectLease,
+		RenewDeadline:    &leaderElectRenew,
+		RetryPeriod:      &leaderElectRetry,
+		// Step down as soon as the manager is stopped so another replica takes over fast.
+		// Safe since the program terminates right after the manager stops.
+		LeaderElectionReleaseOnCancel: true,
 	}
 
 	restConfig := ctrl.GetConfigOrDie()
@@ -134,6 +186,18 @@ func main() {
 		ctrl.GetConfigOrDie())
 
 	controllers.SetVersion(version)
+	controllers.SetInstantiatedNamespace(instantiatedNamespace)
+	controllers.SetAuditHistorySize(auditHistorySize)
+	controllers.SetAuditReader(mgr.GetAPIReader())
+	controllers.SetRemoteClientIdleTimeout(remoteClientIdleTime)
+	controllers.SetCollectionDeadline(collectionDeadline)
+	controllers.SetTemplatePolicy(templateFunctions, templateTimeout, templateMaxOutput)
+	if sopsAgeKeySecret != "" {
+		controllers.SetSopsAgeKeySecret(sopsAgeKeySecretNamespace, sopsAgeKeySecretName)
+	}
+	if triggerSharding {
+		controllers.SetTriggerSharding(replicaID, podNamespace, leaderElectLease)
+	}
 
 	d := deployer.GetClient(ctx, ctrl.Log.WithName("deployer"), mgr.GetClient(), workers)
 	controllers.RegisterFeatures(d, setupLog)
@@ -164,6 +228,18 @@ func main() {
 	}
 	//+kubebuilder:scaffold:builder
 
+	// Each deployment sweeps orphans generated for the clusters in its shard.
+	// The orphan sweeper runs on the leader only.
+	err = mgr.Add(manager.RunnableFunc(func(ctx context.Context) error {
+		controllers.StartOrphanSweeper(ctx, mgr.GetClient(), shardKey, orphanSweepInterval, orphanSweepDryRun,
+			ctrl.Log.WithName("orphan-sweeper"))
+		return nil
+	}))
+	if err != nil {
+		setupLog.Error(err, "unable to add orphan sweeper")
+		os.Exit(1)
+	}
+
 	setupChecks(mgr)
 
 	go capiWatchers(ctx, mgr,
@@ -197,6 +273,28 @@ func initFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&version, "version", "", "current sveltos version")
 
+	fs.StringVar(&instantiatedNamespace, "instantiated-namespace", controllers.ReportNamespace,
+		"Namespace where ConfigMaps/Secrets instantiated by EventTriggers are created when EventTrigger does not specify one")
+
+	fs.StringVar(&sopsAgeKeySecret, "sops-age-key-secret", "",
+		"Secret (namespace/name) in the management cluster containing the age private keys used to decrypt "+
+			"referenced ConfigMaps/Secrets with the projectsveltos.io/sops-encrypted annotation")
+
+	fs.StringSliceVar(&templateFunctions, "template-allowed-functions", nil,
+		"Comma separated list of template functions EventTrigger templates can use. Empty (or \"*\") allows all "+
+			"functions. Can be overridden per tenant admin by the platform admin annotating the tenant RoleRequests")
+
+	const defaultTemplateTimeout = 5
+	fs.DurationVar(&templateTimeout, "template-render-timeout", defaultTemplateTimeout*time.Second,
+		fmt.Sprintf("Maximum time an EventTrigger template can take to render. When set, loop functions (until, "+
+			"untilStep, seq) and repeat are also bounded. Set to 0 to disable. Default: %d seconds",
+			defaultTemplateTimeout))
+
+	const defaultTemplateMaxOutput = 1024 * 1024
+	fs.IntVar(&templateMaxOutput, "template-max-output-size", defaultTemplateMaxOutput,
+		fmt.Sprintf("Maximum size, in bytes, of a rendered EventTrigger template. Set to 0 to disable. Default: %d",
+			defaultTemplateMaxOutput))
+
 	fs.IntVar(&workers, "worker-number", defaultWorkers,
 		"Number of worker. Workers are used to verify health checks in managed clusters")
 
@@ -224,6 +322,75 @@ func initFlags(fs *pflag.FlagSet) {
 
 	fs.StringVar(&healthAddr, "health-addr", ":9440",
 		"The address the health endpoint binds to.")
+
+	const defaultOrphanSweepInterval = 10
+	fs.DurationVar(&orphanSweepInterval, "orphan-sweep-interval", defaultOrphanSweepInterval*time.Minute,
+		fmt.Sprintf("The interval at which objects generated by EventTriggers are checked for orphans (e.g. 15m). "+
+			"Set to 0 to disable. Default: %d minutes", defaultOrphanSweepInterval))
+
+	fs.BoolVar(&orphanSweepDryRun, "orphan-sweep-dry-run", false,
+		"When set, orphans found by the orphan sweeper are only reported and never deleted")
+
+	const defaultAuditHistorySize = 500
+	fs.IntVar(&auditHistorySize, "audit-history-size", defaultAuditHistorySize,
+		"Number of history entries (actions taken and the events causing them) retained per EventTrigger. "+
+			"Set to 0 to disable history")
+
+	const defaultRemoteClientIdleTime = 10
+	fs.DurationVar(&remoteClientIdleTime, "remote-client-idle-timeout", defaultRemoteClientIdleTime*time.Minute,
+		fmt.Sprintf("How long a cached client to a managed cluster can go unused before being evicted (e.g. 15m). "+
+			"Set to 0 to disable caching. Default: %d minutes", defaultRemoteClientIdleTime))
+
+	fs.BoolVar(&leaderElect, "leader-elect", false,
+		"Enable leader election. Required when running more than one replica: only the leader "+
+			"reconciles EventTriggers and collects EventReports")
+
+	fs.BoolVar(&triggerSharding, "trigger-sharding", false,
+		"Distribute EventTriggers across replicas. EventTriggers consuming the same EventSource are owned by "+
+			"the same replica, which reconciles them and processes EventReports for them. Replicas join and leave using leases (see --leader-elect-lease-duration). "+
+			"Cannot be used along with --leader-elect")
+
+	fs.StringVar(&replicaID, "replica-id", os.Getenv("POD_NAME"),
+		"Identity of this replica when --trigger-sharding is set. Must be unique. "+
+			"Default: POD_NAME env variable or hostname")
+
+	fs.StringVar(&podNamespace, "pod-namespace", getEnvOrDefault("POD_NAMESPACE", "projectsveltos"),
+		"Namespace where replica membership leases are created when --trigger-sharding is set. "+
+			"Default: POD_NAMESPACE env variable or projectsveltos")
+
+	const defaultLeaderElectLease = 15
+	fs.DurationVar(&leaderElectLease, "leader-elect-lease-duration", defaultLeaderElectLease*time.Second,
+		"Interval at which non-leader candidates will wait to force acquire leadership (duration string)")
+
+	const defaultLeaderElectRenew = 10
+	fs.DurationVar(&leaderElectRenew, "leader-elect-renew-deadline", defaultLeaderElectRenew*time.Second,
+		"Duration that the leading controller manager will retry refreshing leadership before giving up (duration string)")
+
+	const defaultLeaderElectRetry = 2
+	fs.DurationVar(&leaderElectRetry, "leader-elect-retry-period", defaultLeaderElectRetry*time.Second,
+		"Duration the LeaderElector clients should wait between tries of actions (duration string)")
+
+	const defaultCollectionDeadline = 5
+	fs.DurationVar(&collectionDeadline, "collection-deadline", defaultCollectionDeadline*time.Minute,
+		fmt.Sprintf("Readiness check fails if the EventReport collection loop has not completed "+
+			"an iteration within this deadline. Set to 0 to disable. Default: %d minutes", defaultCollectionDeadline))
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+// getLeaderElectionID returns the name of the Lease used for leader election.
+// Each shard elects its own leader.
+func getLeaderElectionID() string {
+	const leaderElectionID = "event-manager.projectsveltos.io"
+	if shardKey == "" {
+		return leaderElectionID
+	}
+	return fmt.Sprintf("%s-%s", shardKey, leaderElectionID)
 }
 
 func setupChecks(mgr ctrl.Manager) {
@@ -235,6 +402,12 @@ func setupChecks(mgr ctrl.Manager) {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	// A slow collection (many clusters, unreachable clusters) must not get the pod restarted,
+	// so this is a readiness check only
+	if err := mgr.AddReadyzCheck("eventreport-collection", controllers.CheckEventReportCollection); err != nil {
+		setupLog.Error(err, "unable to set up EventReport collection ready check")
+		os.Exit(1)
+	}
 }
 
 // capiCRDHandler restarts process if a CAPI CRD is updated
@@ -0,0 +1,382 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/go-logr/logr"
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// pendingApprovalAnnotation is set on ClusterProfiles waiting for a manual approval.
+	// Value is the hash of the ClusterProfile Spec pending approval.
+	pendingApprovalAnnotation = "eventtrigger.lib.projectsveltos.io/pending-approval"
+
+	// pendingApprovalDetailsAnnotation contains the PendingApproval (JSON) reported in
+	// the EventTrigger Status.
+	pendingApprovalDetailsAnnotation = "eventtrigger.lib.projectsveltos.io/pending-approval-details"
+
+	// approvedAnnotation is set by an user to approve a ClusterProfile. Value is either
+	// the hash of the approved ClusterProfile Spec or approvedValue.
+	approvedAnnotation = "eventtrigger.lib.projectsveltos.io/approved"
+
+	// approvedValue approves the ClusterProfile Spec currently pending approval
+	approvedValue = "true"
+)
+
+// applyClusterProfile creates/updates clusterProfile honoring the EventTrigger ApprovalPolicy.
+// cause describes what caused the change. It is recorded in the EventTrigger history and, if
+// an approval is needed, reported in the EventTrigger Status.
+// Returns the ClusterProfile the EventTrigger is currently managing.
+func applyClusterProfile(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	clusterProfile *configv1beta1.ClusterProfile, cause *eventCause, logger logr.Logger,
+) (*configv1beta1.ClusterProfile, error) {
+
+	current := &configv1beta1.ClusterProfile{}
+	err := c.Get(ctx, types.NamespacedName{Name: clusterProfile.Name}, current)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, err
+		}
+		current = nil
+	}
+
+	if eventTrigger.Spec.ApprovalPolicy == nil {
+		if err := deployClusterProfile(ctx, clusterProfile, logger); err != nil {
+			return nil, err
+		}
+		recordClusterProfileChange(ctx, c, eventTrigger.Name, current, clusterProfile, cause, logger)
+		return clusterProfile, nil
+	}
+
+	hash, err := getClusterProfileSpecHash(&clusterProfile.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if isClusterProfileApproved(current, hash) {
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("ClusterProfile %s is approved", clusterProfile.Name))
+		err = removeAnnotations(ctx, c, current, pendingApprovalAnnotation, pendingApprovalDetailsAnnotation)
+		if err != nil {
+			return nil, err
+		}
+		// Record the approved hash so any later change requires a new approval
+		setAnnotation(clusterProfile, approvedAnnotation, hash)
+		if err := deployClusterProfile(ctx, clusterProfile, logger); err != nil {
+			return nil, err
+		}
+		recordClusterProfileChange(ctx, c, eventTrigger.Name, current, clusterProfile, cause, logger)
+		return clusterProfile, nil
+	}
+
+	if current != nil && current.Annotations[approvedAnnotation] == approvedValue {
+		// Approval was given for a Spec which has since changed. A new approval is needed
+		err = removeAnnotations(ctx, c, current, approvedAnnotation)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	logger.V(logs.LogInfo).Info(fmt.Sprintf("ClusterProfile %s is pending approval", clusterProfile.Name))
+	details, err := getPendingApprovalDetails(eventTrigger, current, clusterProfile, hash, getPendingApproval(cause))
+	if err != nil {
+		return nil, err
+	}
+
+	isNewPendingApproval := current == nil || current.Annotations[pendingApprovalAnnotation] != hash
+
+	if eventTrigger.Spec.ApprovalPolicy.Mode == v1beta1.ApprovalModeHold && current != nil {
+		// Existing ClusterProfile is left untouched. Only annotations are updated
+		if !isNewPendingApproval {
+			return current, nil
+		}
+		patch := client.MergeFrom(current.DeepCopy())
+		setAnnotation(current, pendingApprovalAnnotation, hash)
+		setAnnotation(current, pendingApprovalDetailsAnnotation, details)
+		if err := c.Patch(ctx, current, patch); err != nil {
+			return nil, err
+		}
+		recordAuditEntry(ctx, c, eventTrigger.Name, auditActionPendingApproval, cause,
+			getClusterProfileAuditObjects(current), logger)
+		return current, nil
+	}
+
+	if eventTrigger.Spec.ApprovalPolicy.Mode == v1beta1.ApprovalModeHold {
+		// New ClusterProfile is created matching no cluster
+		clusterProfile.Spec.ClusterRefs = nil
+		clusterProfile.Spec.ClusterSelector = libsveltosv1beta1.Selector{}
+		clusterProfile.Spec.SetRefs = nil
+	} else {
+		clusterProfile.Spec.SyncMode = configv1beta1.SyncModeDryRun
+	}
+
+	setAnnotation(clusterProfile, pendingApprovalAnnotation, hash)
+	setAnnotation(clusterProfile, pendingApprovalDetailsAnnotation, details)
+	if err := deployClusterProfile(ctx, clusterProfile, logger); err != nil {
+		return nil, err
+	}
+	if isNewPendingApproval {
+		recordAuditEntry(ctx, c, eventTrigger.Name, auditActionPendingApproval, cause,
+			getClusterProfileAuditObjects(clusterProfile), logger)
+	}
+	return clusterProfile, nil
+}
+
+// recordClusterProfileChange records in the EventTrigger history the creation of clusterProfile
+// or any change to its Spec
+func recordClusterProfileChange(ctx context.Context, c client.Client, eventTriggerName string,
+	current, clusterProfile *configv1beta1.ClusterProfile, cause *eventCause, logger logr.Logger) {
+
+	action := auditActionCreated
+	if current != nil {
+		if current.Generation == clusterProfile.Generation {
+			return
+		}
+		action = auditActionUpdated
+	}
+
+	recordAuditEntry(ctx, c, eventTriggerName, action, cause, getClusterProfileAuditObjects(clusterProfile), logger)
+}
+
+// deployClusterProfile server side applies clusterProfile and sets its UID
+func deployClusterProfile(ctx context.Context, clusterProfile *configv1beta1.ClusterProfile,
+	logger logr.Logger) error {
+
+	addTypeInformationToObject(mgmtClusterSchema, clusterProfile)
+
+	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig,
+		clusterProfile.GetObjectKind().GroupVersionKind(), clusterProfile.GetNamespace())
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get dynamic client: %v", err))
+		return err
+	}
+
+	u, err := applyResource(ctx, dr, clusterProfile, logger)
+	if err != nil {
+		return err
+	}
+	// UID is needed to have instantiated ConfigMaps/Secrets owned by this ClusterProfile
+	clusterProfile.UID = u.GetUID()
+	clusterProfile.Generation = u.GetGeneration()
+
+	return nil
+}
+
+// isClusterProfileApproved returns true if current ClusterProfile has been approved
+// for the Spec with the given hash
+func isClusterProfileApproved(current *configv1beta1.ClusterProfile, hash string) bool {
+	if current == nil {
+		return false
+	}
+
+	approved := current.Annotations[approvedAnnotation]
+	if approved == hash {
+		return true
+	}
+
+	return approved == approvedValue && current.Annotations[pendingApprovalAnnotation] == hash
+}
+
+func getClusterProfileSpecHash(spec *configv1beta1.Spec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
+
+// getPendingApprovalDetails returns the JSON representation of the PendingApproval.
+// Diff is computed against the current ClusterProfile Spec. If current is itself pending
+// approval for the same hash, previously computed details are preserved.
+func getPendingApprovalDetails(eventTrigger *v1beta1.EventTrigger, current, desired *configv1beta1.ClusterProfile,
+	hash string, pendingApproval *v1beta1.PendingApproval) (string, error) {
+
+	if current != nil && current.Annotations[pendingApprovalAnnotation] == hash {
+		if details, ok := current.Annotations[pendingApprovalDetailsAnnotation]; ok {
+			return details, nil
+		}
+	}
+
+	var previous *configv1beta1.Spec
+	if current != nil {
+		previous = current.Spec.DeepCopy()
+		if _, ok := current.Annotations[pendingApprovalAnnotation]; ok &&
+			eventTrigger.Spec.ApprovalPolicy.Mode != v1beta1.ApprovalModeHold {
+			// SyncMode was forced to DryRun only because approval was pending
+			previous.SyncMode = eventTrigger.Spec.SyncMode
+		}
+	}
+
+	diff, err := getSpecDiff(previous, &desired.Spec)
+	if err != nil {
+		return "", err
+	}
+
+	details := v1beta1.PendingApproval{}
+	if pendingApproval != nil {
+		details = *pendingApproval
+	}
+	details.ClusterProfileName = desired.Name
+	details.Hash = hash
+	details.Diff = diff
+
+	data, err := json.Marshal(details)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// getSpecDiff returns the unified diff between previous and desired ClusterProfile Spec
+func getSpecDiff(previous, desired *configv1beta1.Spec) (string, error) {
+	previousData := ""
+	if previous != nil {
+		data, err := yaml.Marshal(previous)
+		if err != nil {
+			return "", err
+		}
+		previousData = string(data)
+	}
+
+	desiredData, err := yaml.Marshal(desired)
+	if err != nil {
+		return "", err
+	}
+
+	edits := myers.ComputeEdits(span.URIFromPath("previous"), previousData, string(desiredData))
+	return fmt.Sprint(gotextdiff.ToUnified("previous", "desired", previousData, edits)), nil
+}
+
+// getPendingApproval returns the PendingApproval describing what caused a ClusterProfile change
+func getPendingApproval(cause *eventCause) *v1beta1.PendingApproval {
+	pendingApproval := &v1beta1.PendingApproval{}
+	if cause == nil {
+		return pendingApproval
+	}
+
+	pendingApproval.ClusterRef = cause.Cluster
+	pendingApproval.TriggeringResource = cause.TriggeringResource
+	if cause.CloudEvent != nil {
+		pendingApproval.CloudEventSource = cause.CloudEvent.Source
+		pendingApproval.CloudEventSubject = cause.CloudEvent.Subject
+	}
+
+	return pendingApproval
+}
+
+// removeAnnotations removes the given annotations from clusterProfile, if present
+func removeAnnotations(ctx context.Context, c client.Client, clusterProfile *configv1beta1.ClusterProfile,
+	keys ...string) error {
+
+	if clusterProfile == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(clusterProfile.DeepCopy())
+	modified := false
+	for _, key := range keys {
+		if _, ok := clusterProfile.Annotations[key]; ok {
+			delete(clusterProfile.Annotations, key)
+			modified = true
+		}
+	}
+	if !modified {
+		return nil
+	}
+
+	return c.Patch(ctx, clusterProfile, patch)
+}
+
+func setAnnotation(object metav1.Object, key, value string) {
+	annotations := object.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[key] = value
+	object.SetAnnotations(annotations)
+}
+
+// updateApprovalStatuses reports, in each EventTrigger Status, all ClusterProfiles pending approval
+func updateApprovalStatuses(ctx context.Context, c client.Client, eventTriggers *v1beta1.EventTriggerList,
+	logger logr.Logger) {
+
+	clusterProfiles := &configv1beta1.ClusterProfileList{}
+	err := c.List(ctx, clusterProfiles, client.HasLabels{eventTriggerNameLabel})
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to list ClusterProfiles: %v", err))
+		return
+	}
+
+	pendingApprovals := make(map[string][]v1beta1.PendingApproval)
+	for i := range clusterProfiles.Items {
+		cp := &clusterProfiles.Items[i]
+		if _, ok := cp.Annotations[pendingApprovalAnnotation]; !ok {
+			continue
+		}
+
+		pendingApproval := v1beta1.PendingApproval{}
+		if err := json.Unmarshal([]byte(cp.Annotations[pendingApprovalDetailsAnnotation]), &pendingApproval); err != nil {
+			logger.V(logs.LogDebug).Info(fmt.Sprintf("failed to parse %s annotation on ClusterProfile %s: %v",
+				pendingApprovalDetailsAnnotation, cp.Name, err))
+		}
+		pendingApproval.ClusterProfileName = cp.Name
+		pendingApproval.Hash = cp.Annotations[pendingApprovalAnnotation]
+
+		eventTriggerName := cp.Labels[eventTriggerNameLabel]
+		pendingApprovals[eventTriggerName] = append(pendingApprovals[eventTriggerName], pendingApproval)
+	}
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+
+		current := pendingApprovals[et.Name]
+		sort.Slice(current, func(i, j int) bool {
+			return current[i].ClusterProfileName < current[j].ClusterProfileName
+		})
+
+		if reflect.DeepEqual(et.Status.PendingApprovals, current) {
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.PendingApprovals = current
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventTrigger %s pending approvals: %v",
+				et.Name, err))
+		}
+	}
+}
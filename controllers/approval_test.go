@@ -0,0 +1,184 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Approval", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("isClusterProfileApproved accepts approved hash or true for pending hash", func() {
+		hash := randomString()
+
+		Expect(controllers.IsClusterProfileApproved(nil, hash)).To(BeFalse())
+
+		clusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+		Expect(controllers.IsClusterProfileApproved(clusterProfile, hash)).To(BeFalse())
+
+		clusterProfile.Annotations = map[string]string{controllers.ApprovedAnnotation: hash}
+		Expect(controllers.IsClusterProfileApproved(clusterProfile, hash)).To(BeTrue())
+		Expect(controllers.IsClusterProfileApproved(clusterProfile, randomString())).To(BeFalse())
+
+		clusterProfile.Annotations = map[string]string{
+			controllers.ApprovedAnnotation:        "true",
+			controllers.PendingApprovalAnnotation: hash,
+		}
+		Expect(controllers.IsClusterProfileApproved(clusterProfile, hash)).To(BeTrue())
+		// Spec changed after approval was given
+		Expect(controllers.IsClusterProfileApproved(clusterProfile, randomString())).To(BeFalse())
+	})
+
+	It("applyClusterProfile in Hold mode does not change an existing ClusterProfile", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				ApprovalPolicy: &v1beta1.ApprovalPolicy{
+					Mode: v1beta1.ApprovalModeHold,
+				},
+				SyncMode: configv1beta1.SyncModeContinuous,
+			},
+		}
+
+		current := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.Spec{
+				SyncMode: configv1beta1.SyncModeContinuous,
+				PolicyRefs: []configv1beta1.PolicyRef{
+					{Namespace: randomString(), Name: randomString(), Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind)},
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(current).Build()
+
+		desired := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: current.Name,
+			},
+			Spec: configv1beta1.Spec{
+				SyncMode: configv1beta1.SyncModeContinuous,
+			},
+		}
+
+		triggeringResource := corev1.ObjectReference{
+			Kind: "Service", APIVersion: "v1", Namespace: randomString(), Name: randomString(),
+		}
+		cause := &controllers.EventCause{
+			Cluster:            corev1.ObjectReference{Namespace: randomString(), Name: randomString()},
+			TriggeringResource: &triggeringResource,
+		}
+
+		result, err := controllers.ApplyClusterProfile(context.TODO(), c, eventTrigger, desired,
+			cause, logger)
+		Expect(err).To(BeNil())
+		Expect(result).ToNot(BeNil())
+
+		currentClusterProfile := &configv1beta1.ClusterProfile{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: current.Name}, currentClusterProfile)).To(Succeed())
+		Expect(currentClusterProfile.Spec.PolicyRefs).To(Equal(current.Spec.PolicyRefs))
+
+		hash, err := controllers.GetClusterProfileSpecHash(&desired.Spec)
+		Expect(err).To(BeNil())
+		Expect(currentClusterProfile.Annotations[controllers.PendingApprovalAnnotation]).To(Equal(hash))
+
+		details := &v1beta1.PendingApproval{}
+		Expect(json.Unmarshal([]byte(currentClusterProfile.Annotations[controllers.PendingApprovalDetailsAnnotation]),
+			details)).To(Succeed())
+		Expect(details.ClusterProfileName).To(Equal(current.Name))
+		Expect(details.Hash).To(Equal(hash))
+		Expect(details.TriggeringResource).ToNot(BeNil())
+		Expect(*details.TriggeringResource).To(Equal(triggeringResource))
+		Expect(details.Diff).To(ContainSubstring("policyRefs"))
+	})
+
+	It("updateApprovalStatuses reports ClusterProfiles pending approval", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		details := v1beta1.PendingApproval{
+			ClusterRef: corev1.ObjectReference{Namespace: randomString(), Name: randomString()},
+			Diff:       randomString(),
+		}
+		data, err := json.Marshal(details)
+		Expect(err).To(BeNil())
+
+		hash := randomString()
+		pending := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(details.ClusterRef.Namespace, details.ClusterRef.Name,
+					eventTrigger.Name, nil, libsveltosv1beta1.ClusterTypeSveltos),
+				Annotations: map[string]string{
+					controllers.PendingApprovalAnnotation:        hash,
+					controllers.PendingApprovalDetailsAnnotation: string(data),
+				},
+			},
+		}
+
+		approved := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(details.ClusterRef.Namespace, details.ClusterRef.Name,
+					eventTrigger.Name, nil, libsveltosv1beta1.ClusterTypeSveltos),
+			},
+		}
+
+		initObjects := []client.Object{eventTrigger, pending, approved}
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(eventTrigger).
+			WithObjects(initObjects...).Build()
+
+		eventTriggers := &v1beta1.EventTriggerList{}
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+		controllers.UpdateApprovalStatuses(context.TODO(), c, eventTriggers, logger)
+
+		currentEventTrigger := &v1beta1.EventTrigger{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(len(currentEventTrigger.Status.PendingApprovals)).To(Equal(1))
+		Expect(currentEventTrigger.Status.PendingApprovals[0].ClusterProfileName).To(Equal(pending.Name))
+		Expect(currentEventTrigger.Status.PendingApprovals[0].Hash).To(Equal(hash))
+		Expect(currentEventTrigger.Status.PendingApprovals[0].Diff).To(Equal(details.Diff))
+	})
+})
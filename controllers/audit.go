@@ -0,0 +1,355 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// CloudEvents are dropped once processed. In order to reconstruct which event caused which
+// ClusterProfile change, every action taken by an EventTrigger is recorded in an append-only
+// history. History is stored in ConfigMaps (in the ReportNamespace) labeled with the EventTrigger
+// name. Each ConfigMap contains at most auditEntriesPerConfigMap entries. When full, a new ConfigMap
+// is created and, if the retention is exceeded, the oldest ConfigMaps are deleted.
+// ConfigMap names are derived from the EventTrigger name and a sequence number, and the last ConfigMap
+// is found reading from the apiserver (not the cache). So concurrent writers either conflict on update
+// or on create and retry, instead of creating multiple ConfigMaps with the same sequence.
+// History is kept even after the EventTrigger is deleted.
+
+const (
+	// auditHistoryLabel is set on ConfigMaps containing the history of an EventTrigger.
+	// Value is the EventTrigger name.
+	auditHistoryLabel = "eventtrigger.lib.projectsveltos.io/history"
+
+	// auditHistorySequenceAnnotation contains the sequence number of a history ConfigMap
+	auditHistorySequenceAnnotation = "eventtrigger.lib.projectsveltos.io/history-sequence"
+
+	auditEntriesPerConfigMap = 50
+)
+
+var (
+	// auditHistorySize is the number of history entries retained per EventTrigger.
+	// Zero disables history.
+	auditHistorySize = 0
+
+	// auditReader reads history ConfigMaps bypassing the cache. When not set, the client
+	// passed in is used.
+	auditReader client.Reader
+)
+
+type auditAction string
+
+const (
+	auditActionCreated         = auditAction("Created")
+	auditActionUpdated         = auditAction("Updated")
+	auditActionDeleted         = auditAction("Deleted")
+	auditActionPendingApproval = auditAction("PendingApproval")
+	auditActionBlocked         = auditAction("Blocked")
+)
+
+// auditCloudEvent identifies a CloudEvent
+type auditCloudEvent struct {
+	ID      string `json:"id,omitempty"`
+	Source  string `json:"source,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// eventCause describes what caused an EventTrigger action
+type eventCause struct {
+	// Cluster is the cluster where the event happened
+	Cluster corev1.ObjectReference `json:"cluster"`
+
+	// EventReport is the name of the EventReport and EventReportGeneration its generation
+	EventReport           string `json:"eventReport,omitempty"`
+	EventReportGeneration int64  `json:"eventReportGeneration,omitempty"`
+
+	// TriggeringResource is the resource matching the EventSource. Not set when a single
+	// ClusterProfile is instantiated for all matching resources.
+	TriggeringResource *corev1.ObjectReference `json:"triggeringResource,omitempty"`
+
+	// CloudEvent is set when action was caused by a CloudEvent
+	CloudEvent *auditCloudEvent `json:"cloudEvent,omitempty"`
+}
+
+// auditEntry is a single entry in the EventTrigger history
+type auditEntry struct {
+	eventCause `json:",inline"`
+
+	Time         metav1.Time `json:"time"`
+	EventTrigger string      `json:"eventTrigger"`
+	Action       auditAction `json:"action"`
+
+	// Objects are the objects resulting from the action
+	Objects []corev1.ObjectReference `json:"objects,omitempty"`
+
+	// Reason explains why the action was taken. Set for Blocked actions.
+	Reason string `json:"reason,omitempty"`
+}
+
+func SetAuditHistorySize(size int) {
+	auditHistorySize = size
+}
+
+// SetAuditReader sets the reader, bypassing the cache, used to read history ConfigMaps
+func SetAuditReader(reader client.Reader) {
+	auditReader = reader
+}
+
+func getAuditReader(c client.Client) client.Reader {
+	if auditReader != nil {
+		return auditReader
+	}
+	return c
+}
+
+// getEventCause returns the eventCause for an action taken because of the EventReport er.
+// object is nil when action is taken for all resources matching the EventSource.
+func getEventCause(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
+	er *libsveltosv1beta1.EventReport, object *currentObject) *eventCause {
+
+	cause := &eventCause{
+		Cluster: *getClusterRef(clusterNamespace, clusterName, clusterType),
+	}
+
+	if er != nil {
+		cause.EventReport = er.Name
+		cause.EventReportGeneration = er.Generation
+	}
+
+	if object == nil {
+		return cause
+	}
+
+	if object.CloudEvent != nil {
+		cause.CloudEvent = &auditCloudEvent{
+			ID:      getCEID(object.CloudEvent),
+			Source:  getCESource(object.CloudEvent),
+			Subject: getCESubject(object.CloudEvent),
+		}
+	} else {
+		triggeringResource := object.MatchingResource
+		cause.TriggeringResource = &triggeringResource
+	}
+
+	return cause
+}
+
+func getCEID(cloudEvent map[string]interface{}) string {
+	v, ok := cloudEvent["id"]
+	if !ok {
+		return ""
+	}
+
+	s, ok := v.(string)
+	if !ok {
+		return ""
+	}
+
+	return s
+}
+
+// getClusterProfileAuditObjects returns the ClusterProfile and the ConfigMaps/Secrets it references
+func getClusterProfileAuditObjects(clusterProfile *configv1beta1.ClusterProfile) []corev1.ObjectReference {
+	objects := []corev1.ObjectReference{
+		{
+			APIVersion: configv1beta1.GroupVersion.String(),
+			Kind:       configv1beta1.ClusterProfileKind,
+			Name:       clusterProfile.Name,
+		},
+	}
+
+	for i := range clusterProfile.Spec.PolicyRefs {
+		ref := &clusterProfile.Spec.PolicyRefs[i]
+		objects = append(objects, corev1.ObjectReference{
+			APIVersion: "v1",
+			Kind:       ref.Kind,
+			Namespace:  ref.Namespace,
+			Name:       ref.Name,
+		})
+	}
+
+	return objects
+}
+
+// recordAuditEntry appends an entry to the EventTrigger history. Failures are only logged:
+// history must never block EventTrigger processing.
+func recordAuditEntry(ctx context.Context, c client.Client, eventTriggerName string, action auditAction,
+	cause *eventCause, objects []corev1.ObjectReference, logger logr.Logger) {
+
+	entry := &auditEntry{
+		Time:         metav1.Now(),
+		EventTrigger: eventTriggerName,
+		Action:       action,
+		Objects:      objects,
+	}
+	if cause != nil {
+		entry.eventCause = *cause
+	}
+
+	recordAudit(ctx, c, entry, logger)
+}
+
+// recordAudit appends entry to the EventTrigger history
+func recordAudit(ctx context.Context, c client.Client, entry *auditEntry, logger logr.Logger) {
+	if auditHistorySize == 0 {
+		return
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to marshal history entry: %v", err))
+		return
+	}
+
+	// Another writer updated the last ConfigMap or created the next one
+	isRetriable := func(err error) bool {
+		return apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err)
+	}
+	err = retry.OnError(retry.DefaultRetry, isRetriable, func() error {
+		return appendAuditEntry(ctx, c, entry.EventTrigger, string(data), logger)
+	})
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to record history entry: %v", err))
+	}
+}
+
+func appendAuditEntry(ctx context.Context, c client.Client, eventTriggerName, entry string,
+	logger logr.Logger) error {
+
+	configMaps, err := getAuditConfigMaps(ctx, getAuditReader(c), eventTriggerName)
+	if err != nil {
+		return err
+	}
+
+	if len(configMaps) != 0 {
+		current := configMaps[len(configMaps)-1]
+		if len(current.Data) < auditEntriesPerConfigMap {
+			if current.Data == nil {
+				current.Data = make(map[string]string)
+			}
+			current.Data[getAuditEntryKey(len(current.Data))] = entry
+			return c.Update(ctx, current)
+		}
+	}
+
+	sequence := 0
+	if len(configMaps) != 0 {
+		sequence = getAuditSequence(configMaps[len(configMaps)-1]) + 1
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ReportNamespace,
+			Name:      getAuditConfigMapName(eventTriggerName, sequence),
+			Labels: map[string]string{
+				auditHistoryLabel: eventTriggerName,
+			},
+			Annotations: map[string]string{
+				auditHistorySequenceAnnotation: strconv.Itoa(sequence),
+			},
+		},
+		Data: map[string]string{
+			getAuditEntryKey(0): entry,
+		},
+	}
+	if err := c.Create(ctx, configMap); err != nil {
+		return err
+	}
+
+	return pruneAuditConfigMaps(ctx, c, append(configMaps, configMap), logger)
+}
+
+// pruneAuditConfigMaps deletes the oldest history ConfigMaps exceeding the retention.
+// configMaps must be sorted by sequence.
+func pruneAuditConfigMaps(ctx context.Context, c client.Client, configMaps []*corev1.ConfigMap,
+	logger logr.Logger) error {
+
+	maxConfigMaps := (auditHistorySize + auditEntriesPerConfigMap - 1) / auditEntriesPerConfigMap
+	for i := 0; i < len(configMaps)-maxConfigMaps; i++ {
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("deleting history ConfigMap %s", configMaps[i].Name))
+		if err := c.Delete(ctx, configMaps[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getAuditConfigMaps returns the history ConfigMaps of an EventTrigger sorted by sequence
+func getAuditConfigMaps(ctx context.Context, c client.Reader, eventTriggerName string,
+) ([]*corev1.ConfigMap, error) {
+
+	configMapList := &corev1.ConfigMapList{}
+	err := c.List(ctx, configMapList, client.InNamespace(ReportNamespace),
+		client.MatchingLabels{auditHistoryLabel: eventTriggerName})
+	if err != nil {
+		return nil, err
+	}
+
+	configMaps := make([]*corev1.ConfigMap, len(configMapList.Items))
+	for i := range configMapList.Items {
+		configMaps[i] = &configMapList.Items[i]
+	}
+	sort.Slice(configMaps, func(i, j int) bool {
+		return getAuditSequence(configMaps[i]) < getAuditSequence(configMaps[j])
+	})
+
+	return configMaps, nil
+}
+
+// getAuditConfigMapName returns the name of the history ConfigMap with the given sequence number.
+// EventTrigger name is hashed when the resulting name would be too long.
+func getAuditConfigMapName(eventTriggerName string, sequence int) string {
+	name := fmt.Sprintf("eventtrigger-history-%s-%d", eventTriggerName, sequence)
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+
+	h := sha256.Sum256([]byte(eventTriggerName))
+	return fmt.Sprintf("eventtrigger-history-%x-%d", h, sequence)
+}
+
+func getAuditSequence(configMap *corev1.ConfigMap) int {
+	sequence, err := strconv.Atoi(configMap.Annotations[auditHistorySequenceAnnotation])
+	if err != nil {
+		return 0
+	}
+	return sequence
+}
+
+// getAuditEntryKey returns the ConfigMap key for the entry at the given index.
+// Keys are zero padded so entries are listed in order.
+func getAuditEntryKey(index int) string {
+	return fmt.Sprintf("%03d", index)
+}
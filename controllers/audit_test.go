@@ -0,0 +1,81 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("Audit history", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	AfterEach(func() {
+		controllers.SetAuditHistorySize(0)
+	})
+
+	It("recordAuditEntry appends entries and rotates ConfigMaps", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		eventTriggerName := randomString()
+		cause := &controllers.EventCause{
+			Cluster:               corev1.ObjectReference{Namespace: randomString(), Name: randomString()},
+			EventReport:           randomString(),
+			EventReportGeneration: 3,
+		}
+		objects := []corev1.ObjectReference{{Kind: "ClusterProfile", Name: randomString()}}
+
+		// History disabled
+		controllers.RecordAuditEntry(context.TODO(), c, eventTriggerName, "Created", cause, objects, logger)
+		configMaps, err := controllers.GetAuditConfigMaps(context.TODO(), c, eventTriggerName)
+		Expect(err).To(BeNil())
+		Expect(len(configMaps)).To(BeZero())
+
+		// Retain two ConfigMaps worth of entries
+		controllers.SetAuditHistorySize(2 * controllers.AuditEntriesPerConfigMap)
+		extra := 5
+		for i := 0; i < 2*controllers.AuditEntriesPerConfigMap+extra; i++ {
+			controllers.RecordAuditEntry(context.TODO(), c, eventTriggerName, "Created", cause, objects, logger)
+		}
+
+		configMaps, err = controllers.GetAuditConfigMaps(context.TODO(), c, eventTriggerName)
+		Expect(err).To(BeNil())
+		Expect(len(configMaps)).To(Equal(2))
+		Expect(len(configMaps[0].Data)).To(Equal(controllers.AuditEntriesPerConfigMap))
+		Expect(len(configMaps[1].Data)).To(Equal(extra))
+		// Oldest ConfigMap has been pruned
+		Expect(configMaps[0].Name).To(Equal(controllers.GetAuditConfigMapName(eventTriggerName, 1)))
+		Expect(configMaps[1].Name).To(Equal(controllers.GetAuditConfigMapName(eventTriggerName, 2)))
+
+		for _, v := range configMaps[1].Data {
+			entry := map[string]interface{}{}
+			Expect(json.Unmarshal([]byte(v), &entry)).To(Succeed())
+			Expect(entry["eventTrigger"]).To(Equal(eventTriggerName))
+			Expect(entry["eventReport"]).To(Equal(cause.EventReport))
+			Expect(entry["action"]).To(Equal("Created"))
+		}
+	})
+})
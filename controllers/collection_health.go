@@ -0,0 +1,93 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	// collectionDeadline is the maximum time allowed between two completed iterations of
+	// the EventReport collection loop before the readiness check fails. Zero disables the check.
+	collectionDeadline time.Duration
+
+	// collectionDrainTimeout is the time given, on shutdown, to the cluster being processed
+	// by the EventReport collection loop to complete. Must be lower than the pod termination
+	// grace period.
+	collectionDrainTimeout = 5 * time.Second
+)
+
+// collectionState tracks the EventReport collection loop
+type collectionState struct {
+	mux sync.Mutex
+
+	// running is true while the collection loop runs. The loop runs only on the leader.
+	running bool
+	// lastIteration is the time last iteration completed (or the loop started)
+	lastIteration time.Time
+}
+
+var collection = &collectionState{}
+
+func SetCollectionDeadline(deadline time.Duration) {
+	collectionDeadline = deadline
+}
+
+func setCollectionRunning(running bool) {
+	collection.mux.Lock()
+	defer collection.mux.Unlock()
+
+	collection.running = running
+	collection.lastIteration = time.Now()
+}
+
+func setCollectionIterationCompleted() {
+	collection.mux.Lock()
+	defer collection.mux.Unlock()
+
+	collection.lastIteration = time.Now()
+}
+
+// CheckEventReportCollection is a readiness healthz.Checker failing if the EventReport collection loop
+// has not completed an iteration within the configured deadline
+func CheckEventReportCollection(_ *http.Request) error {
+	return checkEventReportCollection(time.Now())
+}
+
+func checkEventReportCollection(now time.Time) error {
+	if collectionDeadline == 0 {
+		return nil
+	}
+
+	collection.mux.Lock()
+	defer collection.mux.Unlock()
+
+	if !collection.running {
+		// Collection loop is not running on this replica (not the leader or EventReports
+		// are sent by sveltos-agent)
+		return nil
+	}
+
+	if elapsed := now.Sub(collection.lastIteration); elapsed > collectionDeadline {
+		return fmt.Errorf("EventReport collection has not completed an iteration in %s", elapsed.Round(time.Second))
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("EventReport collection health", func() {
+	AfterEach(func() {
+		controllers.SetCollectionDeadline(0)
+		controllers.SetCollectionRunning(false)
+	})
+
+	It("checkEventReportCollection fails only when the loop is running and the deadline is exceeded", func() {
+		deadline := time.Minute
+		controllers.SetCollectionDeadline(deadline)
+
+		// Loop not running (not leader)
+		Expect(controllers.CheckEventReportCollectionAt(time.Now().Add(time.Hour))).To(Succeed())
+
+		controllers.SetCollectionRunning(true)
+		Expect(controllers.CheckEventReportCollectionAt(time.Now())).To(Succeed())
+		Expect(controllers.CheckEventReportCollectionAt(time.Now().Add(2 * deadline))).ToNot(Succeed())
+
+		// Zero disables the check
+		controllers.SetCollectionDeadline(0)
+		Expect(controllers.CheckEventReportCollectionAt(time.Now().Add(2 * deadline))).To(Succeed())
+	})
+
+	It("getDrainContext is cancelled only once timeout expires after parent is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.TODO())
+
+		timeout := 200 * time.Millisecond
+		drainCtx, drainCancel := controllers.GetDrainContext(ctx, timeout)
+		defer drainCancel()
+
+		cancel()
+		Consistently(drainCtx.Done(), timeout/2).ShouldNot(BeClosed())
+		Eventually(drainCtx.Done(), 2*timeout).Should(BeClosed())
+	})
+})
@@ -0,0 +1,117 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+const (
+	// pendingRemovalAnnotation is added to objects generated by an EventTrigger which are not
+	// needed anymore but whose removal is delayed because of EventTrigger.Spec.DeletionGracePeriod.
+	// Value is the time (RFC3339) the object was found stale.
+	pendingRemovalAnnotation = "eventtrigger.lib.projectsveltos.io/pending-removal"
+)
+
+// canBeRemoved returns true if stale object, generated by eventTrigger, can be deleted now.
+// If EventTrigger.Spec.DeletionGracePeriod is set, the first time a stale object is found,
+// it is annotated with current time. Object is removed only once grace period is elapsed.
+// Grace period is ignored when either EventTrigger or EventReport are being deleted.
+func canBeRemoved(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	er *libsveltosv1beta1.EventReport, object client.Object, logger logr.Logger) (bool, error) {
+
+	if !isResourceExist(eventTrigger) || !isResourceExist(er) {
+		return true, nil
+	}
+
+	return isGracePeriodElapsed(ctx, c, eventTrigger, object, logger)
+}
+
+// isGracePeriodElapsed returns true if EventTrigger.Spec.DeletionGracePeriod is not set or has
+// elapsed since stale object was first found. Otherwise, the first time it is called, object is
+// annotated with current time.
+func isGracePeriodElapsed(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	object client.Object, logger logr.Logger) (bool, error) {
+
+	if eventTrigger.Spec.DeletionGracePeriod == nil || eventTrigger.Spec.DeletionGracePeriod.Duration <= 0 {
+		return true, nil
+	}
+
+	annotations := object.GetAnnotations()
+	if v, ok := annotations[pendingRemovalAnnotation]; ok {
+		staleSince, err := time.Parse(time.RFC3339, v)
+		if err == nil {
+			return time.Now().After(staleSince.Add(eventTrigger.Spec.DeletionGracePeriod.Duration)), nil
+		}
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("failed to parse %s annotation: %v",
+			pendingRemovalAnnotation, err))
+	}
+
+	logger.V(logs.LogDebug).Info(fmt.Sprintf("marking %s %s/%s as pending removal",
+		object.GetObjectKind().GroupVersionKind().Kind, object.GetNamespace(), object.GetName()))
+
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[pendingRemovalAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	object.SetAnnotations(annotations)
+
+	return false, c.Patch(ctx, object, patch)
+}
+
+// clearPendingRemoval removes the pending removal annotation, if present, from object.
+// This happens when resources generating the object match again before grace period expires.
+func clearPendingRemoval(ctx context.Context, c client.Client, object client.Object,
+	logger logr.Logger) error {
+
+	annotations := object.GetAnnotations()
+	if _, ok := annotations[pendingRemovalAnnotation]; !ok {
+		return nil
+	}
+
+	logger.V(logs.LogDebug).Info(fmt.Sprintf("%s %s/%s is not pending removal anymore",
+		object.GetObjectKind().GroupVersionKind().Kind, object.GetNamespace(), object.GetName()))
+
+	patch := client.MergeFrom(object.DeepCopyObject().(client.Object))
+	delete(annotations, pendingRemovalAnnotation)
+	object.SetAnnotations(annotations)
+
+	return c.Patch(ctx, object, patch)
+}
+
+// hasPendingRemovals returns true if any of the generated objects is pending removal. Instantiation
+// is never skipped while this is the case, so the EventReport collection evaluates pending removals
+// on every iteration and removes objects once the grace period elapses.
+func hasPendingRemovals(objects []client.Object) bool {
+	for i := range objects {
+		if _, ok := objects[i].GetAnnotations()[pendingRemovalAnnotation]; ok {
+			return true
+		}
+	}
+
+	return false
+}
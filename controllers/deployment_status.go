@@ -0,0 +1,203 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/lib/clusterops"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// Add-ons are deployed by the addon-controller, which creates one ClusterSummary per generated
+// ClusterProfile and destination cluster. EventTrigger Status aggregates, per generated ClusterProfile
+// (so per source cluster and triggering resource), the deployment status of those ClusterSummaries.
+
+// getGeneratedClusterProfileStatuses returns the deployment status of all ClusterProfiles
+// generated by the EventTrigger
+func getGeneratedClusterProfileStatuses(ctx context.Context, c client.Client, eventTriggerName string,
+) ([]v1beta1.GeneratedClusterProfileStatus, error) {
+
+	clusterProfiles := &configv1beta1.ClusterProfileList{}
+	err := c.List(ctx, clusterProfiles, client.MatchingLabels{eventTriggerNameLabel: eventTriggerName})
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]v1beta1.GeneratedClusterProfileStatus, 0, len(clusterProfiles.Items))
+	for i := range clusterProfiles.Items {
+		cp := &clusterProfiles.Items[i]
+		if !cp.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		status, err := getGeneratedClusterProfileStatus(ctx, c, cp)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, *status)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].ClusterProfileName < statuses[j].ClusterProfileName
+	})
+
+	return statuses, nil
+}
+
+func getGeneratedClusterProfileStatus(ctx context.Context, c client.Client,
+	clusterProfile *configv1beta1.ClusterProfile) (*v1beta1.GeneratedClusterProfileStatus, error) {
+
+	lbls := clusterProfile.Labels
+	status := &v1beta1.GeneratedClusterProfileStatus{
+		ClusterProfileName: clusterProfile.Name,
+		SourceCluster: *getClusterRef(lbls[clusterNamespaceLabel], lbls[clusterNameLabel],
+			libsveltosv1beta1.ClusterType(lbls[clusterTypeLabel])),
+	}
+
+	if resourceName, ok := lbls[resourceNameLabel]; ok {
+		status.TriggeringResource = &corev1.ObjectReference{
+			Namespace: lbls[resourceNamespaceLabel],
+			Name:      resourceName,
+		}
+	}
+
+	if len(clusterProfile.Status.MatchingClusterRefs) != 0 {
+		status.DestinationMatchingClusterRefs = make([]corev1.ObjectReference,
+			len(clusterProfile.Status.MatchingClusterRefs))
+		copy(status.DestinationMatchingClusterRefs, clusterProfile.Status.MatchingClusterRefs)
+		sortClusterRefs(status.DestinationMatchingClusterRefs)
+	}
+
+	clusterSummaries := &configv1beta1.ClusterSummaryList{}
+	err := c.List(ctx, clusterSummaries,
+		client.MatchingLabels{clusterops.ClusterProfileLabelName: clusterProfile.Name})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range clusterSummaries.Items {
+		status.Destinations = append(status.Destinations, getDestinationStatus(&clusterSummaries.Items[i]))
+	}
+
+	sort.Slice(status.Destinations, func(i, j int) bool {
+		return lessClusterRef(&status.Destinations[i].Cluster, &status.Destinations[j].Cluster)
+	})
+
+	return status, nil
+}
+
+// getDestinationMatchingClusterRefs returns all the clusters currently matched by any of the
+// generated ClusterProfiles
+func getDestinationMatchingClusterRefs(statuses []v1beta1.GeneratedClusterProfileStatus,
+) []corev1.ObjectReference {
+
+	destinations := make([]corev1.ObjectReference, 0)
+	for i := range statuses {
+		destinations = append(destinations, statuses[i].DestinationMatchingClusterRefs...)
+	}
+
+	if len(destinations) == 0 {
+		return nil
+	}
+
+	destinations = removeDuplicates(destinations)
+	sortClusterRefs(destinations)
+	return destinations
+}
+
+func sortClusterRefs(clusters []corev1.ObjectReference) {
+	sort.Slice(clusters, func(i, j int) bool {
+		return lessClusterRef(&clusters[i], &clusters[j])
+	})
+}
+
+func lessClusterRef(ci, cj *corev1.ObjectReference) bool {
+	if ci.Namespace != cj.Namespace {
+		return ci.Namespace < cj.Namespace
+	}
+	if ci.Name != cj.Name {
+		return ci.Name < cj.Name
+	}
+	return ci.Kind < cj.Kind
+}
+
+// getDestinationStatus returns the deployment status reported by a ClusterSummary.
+// Conflicts take precedence over failures; add-ons are Provisioned only when all
+// features are provisioned.
+func getDestinationStatus(clusterSummary *configv1beta1.ClusterSummary) v1beta1.DestinationStatus {
+	destinationStatus := v1beta1.DestinationStatus{
+		Cluster: *getClusterRef(clusterSummary.Spec.ClusterNamespace, clusterSummary.Spec.ClusterName,
+			clusterSummary.Spec.ClusterType),
+		Status: v1beta1.DeploymentStatusProvisioning,
+	}
+
+	conflicts := make([]string, 0)
+	for i := range clusterSummary.Status.HelmReleaseSummaries {
+		hrs := &clusterSummary.Status.HelmReleaseSummaries[i]
+		if hrs.Status == configv1beta1.HelmChartStatusConflict {
+			conflicts = append(conflicts, fmt.Sprintf("%s/%s: %s", hrs.ReleaseNamespace, hrs.ReleaseName,
+				hrs.ConflictMessage))
+		}
+	}
+	if len(conflicts) != 0 {
+		msg := strings.Join(conflicts, "; ")
+		destinationStatus.Status = v1beta1.DeploymentStatusConflict
+		destinationStatus.FailureMessage = &msg
+		return destinationStatus
+	}
+
+	failures := make([]string, 0)
+	if clusterSummary.Status.FailureMessage != nil {
+		failures = append(failures, *clusterSummary.Status.FailureMessage)
+	}
+
+	provisioned := len(clusterSummary.Status.FeatureSummaries) != 0
+	for i := range clusterSummary.Status.FeatureSummaries {
+		fs := &clusterSummary.Status.FeatureSummaries[i]
+		switch fs.Status {
+		case libsveltosv1beta1.FeatureStatusFailed, libsveltosv1beta1.FeatureStatusFailedNonRetriable:
+			msg := string(fs.Status)
+			if fs.FailureMessage != nil {
+				msg = *fs.FailureMessage
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", fs.FeatureID, msg))
+			provisioned = false
+		case libsveltosv1beta1.FeatureStatusProvisioned:
+		default:
+			provisioned = false
+		}
+	}
+
+	if len(failures) != 0 {
+		msg := strings.Join(failures, "; ")
+		destinationStatus.Status = v1beta1.DeploymentStatusFailed
+		destinationStatus.FailureMessage = &msg
+	} else if provisioned {
+		destinationStatus.Status = v1beta1.DeploymentStatusProvisioned
+	}
+
+	return destinationStatus
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/lib/clusterops"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Deployment status", func() {
+	getClusterSummary := func() *configv1beta1.ClusterSummary {
+		return &configv1beta1.ClusterSummary{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+			Spec: configv1beta1.ClusterSummarySpec{
+				ClusterNamespace: randomString(),
+				ClusterName:      randomString(),
+				ClusterType:      libsveltosv1beta1.ClusterTypeSveltos,
+			},
+		}
+	}
+
+	It("getDestinationStatus aggregates ClusterSummary feature and helm statuses", func() {
+		clusterSummary := getClusterSummary()
+
+		status := controllers.GetDestinationStatus(clusterSummary)
+		Expect(status.Status).To(Equal(v1beta1.DeploymentStatusProvisioning))
+		Expect(status.Cluster.Namespace).To(Equal(clusterSummary.Spec.ClusterNamespace))
+		Expect(status.Cluster.Name).To(Equal(clusterSummary.Spec.ClusterName))
+		Expect(status.Cluster.Kind).To(Equal(libsveltosv1beta1.SveltosClusterKind))
+
+		clusterSummary.Status.FeatureSummaries = []configv1beta1.FeatureSummary{
+			{FeatureID: libsveltosv1beta1.FeatureResources, Status: libsveltosv1beta1.FeatureStatusProvisioned},
+			{FeatureID: libsveltosv1beta1.FeatureHelm, Status: libsveltosv1beta1.FeatureStatusProvisioning},
+		}
+		Expect(controllers.GetDestinationStatus(clusterSummary).Status).To(Equal(v1beta1.DeploymentStatusProvisioning))
+
+		clusterSummary.Status.FeatureSummaries[1].Status = libsveltosv1beta1.FeatureStatusProvisioned
+		status = controllers.GetDestinationStatus(clusterSummary)
+		Expect(status.Status).To(Equal(v1beta1.DeploymentStatusProvisioned))
+		Expect(status.FailureMessage).To(BeNil())
+
+		failureMessage := randomString()
+		clusterSummary.Status.FeatureSummaries[1].Status = libsveltosv1beta1.FeatureStatusFailed
+		clusterSummary.Status.FeatureSummaries[1].FailureMessage = &failureMessage
+		status = controllers.GetDestinationStatus(clusterSummary)
+		Expect(status.Status).To(Equal(v1beta1.DeploymentStatusFailed))
+		Expect(status.FailureMessage).ToNot(BeNil())
+		Expect(*status.FailureMessage).To(ContainSubstring(failureMessage))
+
+		conflictMessage := randomString()
+		clusterSummary.Status.HelmReleaseSummaries = []configv1beta1.HelmChartSummary{
+			{
+				ReleaseNamespace: randomString(),
+				ReleaseName:      randomString(),
+				Status:           configv1beta1.HelmChartStatusConflict,
+				ConflictMessage:  conflictMessage,
+			},
+		}
+		status = controllers.GetDestinationStatus(clusterSummary)
+		Expect(status.Status).To(Equal(v1beta1.DeploymentStatusConflict))
+		Expect(status.FailureMessage).ToNot(BeNil())
+		Expect(*status.FailureMessage).To(ContainSubstring(conflictMessage))
+	})
+
+	It("getGeneratedClusterProfileStatuses reports generated ClusterProfiles and their destinations", func() {
+		eventTriggerName := randomString()
+		sourceClusterNamespace := randomString()
+		sourceClusterName := randomString()
+		resourceName := randomString()
+
+		clusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					"eventtrigger.lib.projectsveltos.io/eventtriggername":  eventTriggerName,
+					"eventtrigger.lib.projectsveltos.io/clusterNamespace":  sourceClusterNamespace,
+					"eventtrigger.lib.projectsveltos.io/clustername":       sourceClusterName,
+					"eventtrigger.lib.projectsveltos.io/clustertype":       string(libsveltosv1beta1.ClusterTypeSveltos),
+					"eventtrigger.lib.projectsveltos.io/resourcename":      resourceName,
+					"eventtrigger.lib.projectsveltos.io/resourcenamespace": "default",
+				},
+			},
+		}
+
+		destinationCluster := corev1.ObjectReference{
+			Namespace:  randomString(),
+			Name:       randomString(),
+			Kind:       libsveltosv1beta1.SveltosClusterKind,
+			APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+		clusterProfile.Status.MatchingClusterRefs = []corev1.ObjectReference{destinationCluster}
+
+		// ClusterProfile generated by another EventTrigger
+		otherClusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					"eventtrigger.lib.projectsveltos.io/eventtriggername": randomString(),
+				},
+			},
+		}
+
+		clusterSummary := getClusterSummary()
+		clusterSummary.Labels = map[string]string{
+			clusterops.ClusterProfileLabelName: clusterProfile.Name,
+		}
+		clusterSummary.Status.FailureMessage = ptr.To(randomString())
+
+		c := fake.NewClientBuilder().WithScheme(scheme).
+			WithObjects(clusterProfile, otherClusterProfile, clusterSummary).Build()
+
+		statuses, err := controllers.GetGeneratedClusterProfileStatuses(context.TODO(), c, eventTriggerName)
+		Expect(err).To(BeNil())
+		Expect(len(statuses)).To(Equal(1))
+		Expect(statuses[0].ClusterProfileName).To(Equal(clusterProfile.Name))
+		Expect(statuses[0].SourceCluster.Namespace).To(Equal(sourceClusterNamespace))
+		Expect(statuses[0].SourceCluster.Name).To(Equal(sourceClusterName))
+		Expect(statuses[0].TriggeringResource).ToNot(BeNil())
+		Expect(statuses[0].TriggeringResource.Namespace).To(Equal("default"))
+		Expect(statuses[0].TriggeringResource.Name).To(Equal(resourceName))
+		Expect(len(statuses[0].Destinations)).To(Equal(1))
+		Expect(statuses[0].Destinations[0].Status).To(Equal(v1beta1.DeploymentStatusFailed))
+		Expect(*statuses[0].Destinations[0].FailureMessage).To(Equal(*clusterSummary.Status.FailureMessage))
+	})
+
+	It("getDestinationMatchingClusterRefs returns clusters matched by any generated ClusterProfile", func() {
+		cluster1 := corev1.ObjectReference{
+			Namespace: randomString(), Name: "a" + randomString(), Kind: libsveltosv1beta1.SveltosClusterKind,
+		}
+		cluster2 := corev1.ObjectReference{
+			Namespace: cluster1.Namespace, Name: "b" + randomString(), Kind: libsveltosv1beta1.SveltosClusterKind,
+		}
+
+		statuses := []v1beta1.GeneratedClusterProfileStatus{
+			{ClusterProfileName: randomString(), DestinationMatchingClusterRefs: []corev1.ObjectReference{cluster2}},
+			{ClusterProfileName: randomString(), DestinationMatchingClusterRefs: []corev1.ObjectReference{cluster1, cluster2}},
+			{ClusterProfileName: randomString()},
+		}
+
+		destinations := controllers.GetDestinationMatchingClusterRefs(statuses)
+		Expect(destinations).To(Equal([]corev1.ObjectReference{cluster1, cluster2}))
+
+		Expect(controllers.GetDestinationMatchingClusterRefs(nil)).To(BeNil())
+	})
+})
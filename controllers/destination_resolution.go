@@ -0,0 +1,462 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// DestinationCluster and DestinationClusters are templates. Once instantiated for an event, destination
+// clusters are resolved against SveltosCluster/CAPI Cluster instances. Destination clusters which do not
+// exist or are not ready are kept in memory per event and reported in the EventTrigger Status.
+// Depending on EventTrigger UnresolvedDestinationPolicy, the ClusterProfile is either created anyway or
+// not created till all destination clusters are resolved.
+// Resolution of those destination clusters is part of the instantiation fingerprint, so events are
+// instantiated again once a destination cluster appears or becomes ready.
+// While waiting, nothing is instantiated for the event: previously generated ClusterProfile,
+// ConfigMaps, Secrets and resources are left untouched.
+// Unresolved destinations are not persisted. After a restart (or when another replica takes over the
+// EventTrigger) no destination is recorded, so the instantiation fingerprint differs from the one
+// stored on the EventReport and events are instantiated again. This resolves destinations again and
+// rebuilds the state before the Status is next updated by the EventReport collection loop.
+
+// unresolvedDestinationError is returned when, with UnresolvedDestinationPolicy set to Wait, one or
+// more destination clusters do not exist or are not ready
+type unresolvedDestinationError struct {
+	clusters []string
+}
+
+func (e *unresolvedDestinationError) Error() string {
+	return fmt.Sprintf("destination clusters %s do not exist or are not ready", strings.Join(e.clusters, ", "))
+}
+
+func isUnresolvedDestination(err error) bool {
+	var unresolvedErr *unresolvedDestinationError
+	return errors.As(err, &unresolvedErr)
+}
+
+// isWaitingForDestinations returns true if UnresolvedDestinationPolicy is Wait and destination clusters
+// were not resolved when the event described by cause was last instantiated
+func isWaitingForDestinations(eventTrigger *v1beta1.EventTrigger, cause *eventCause) bool {
+	if eventTrigger.Spec.UnresolvedDestinationPolicy != v1beta1.UnresolvedDestinationPolicyWait {
+		return false
+	}
+
+	unresolvedDestinationsLock.RLock()
+	defer unresolvedDestinationsLock.RUnlock()
+
+	_, ok := unresolvedDestinations[eventTrigger.Name][getEventKey(cause)]
+	return ok
+}
+
+// unresolvedDestinationRecord contains the destination clusters, instantiated for an event, which were
+// not resolved when the event was last instantiated
+type unresolvedDestinationRecord struct {
+	cause    eventCause
+	clusters []corev1.ObjectReference
+}
+
+var (
+	// unresolvedDestinations contains, per EventTrigger name, the unresolved destinations per event
+	unresolvedDestinations     = make(map[string]map[string]*unresolvedDestinationRecord)
+	unresolvedDestinationsLock = &sync.RWMutex{}
+)
+
+func setUnresolvedDestinations(eventTriggerName string, cause *eventCause, clusters []corev1.ObjectReference) {
+	unresolvedDestinationsLock.Lock()
+	defer unresolvedDestinationsLock.Unlock()
+
+	key := getEventKey(cause)
+	if len(clusters) == 0 {
+		if records, ok := unresolvedDestinations[eventTriggerName]; ok {
+			delete(records, key)
+			if len(records) == 0 {
+				delete(unresolvedDestinations, eventTriggerName)
+			}
+		}
+		return
+	}
+
+	if _, ok := unresolvedDestinations[eventTriggerName]; !ok {
+		unresolvedDestinations[eventTriggerName] = make(map[string]*unresolvedDestinationRecord)
+	}
+	unresolvedDestinations[eventTriggerName][key] = &unresolvedDestinationRecord{
+		cause:    *cause,
+		clusters: clusters,
+	}
+}
+
+// clearUnresolvedDestinations removes the unresolved destinations recorded for EventTrigger events
+// coming from EventReport eventReportName in cluster. Events are then recorded again while being
+// instantiated.
+func clearUnresolvedDestinations(eventTriggerName string, cluster *corev1.ObjectReference, eventReportName string) {
+	unresolvedDestinationsLock.Lock()
+	defer unresolvedDestinationsLock.Unlock()
+
+	records := unresolvedDestinations[eventTriggerName]
+	clusterKey := getClusterKey(cluster)
+	for k := range records {
+		if getClusterKey(&records[k].cause.Cluster) == clusterKey && records[k].cause.EventReport == eventReportName {
+			delete(records, k)
+		}
+	}
+	if len(records) == 0 {
+		delete(unresolvedDestinations, eventTriggerName)
+	}
+}
+
+// removeUnresolvedDestinations removes all unresolved destinations recorded for an EventTrigger
+func removeUnresolvedDestinations(eventTriggerName string) {
+	unresolvedDestinationsLock.Lock()
+	defer unresolvedDestinationsLock.Unlock()
+
+	delete(unresolvedDestinations, eventTriggerName)
+}
+
+// getUnresolvedDestinationRecords returns the unresolved destinations recorded for an EventTrigger.
+// If sourceCluster is set, only records for events in that cluster are returned.
+func getUnresolvedDestinationRecords(eventTriggerName string, sourceCluster *corev1.ObjectReference,
+) []unresolvedDestinationRecord {
+
+	unresolvedDestinationsLock.RLock()
+	defer unresolvedDestinationsLock.RUnlock()
+
+	records := make([]unresolvedDestinationRecord, 0, len(unresolvedDestinations[eventTriggerName]))
+	for _, record := range unresolvedDestinations[eventTriggerName] {
+		if sourceCluster != nil && getClusterKey(&record.cause.Cluster) != getClusterKey(sourceCluster) {
+			continue
+		}
+		records = append(records, *record)
+	}
+
+	return records
+}
+
+// getEventTriggersWaitingForCluster returns the names of the EventTriggers with cluster as an
+// unresolved destination
+func getEventTriggersWaitingForCluster(cluster *corev1.ObjectReference) []string {
+	unresolvedDestinationsLock.RLock()
+	defer unresolvedDestinationsLock.RUnlock()
+
+	clusterKey := getClusterKey(cluster)
+	names := make([]string, 0)
+	for eventTriggerName, records := range unresolvedDestinations {
+		if isClusterInRecords(records, clusterKey) {
+			names = append(names, eventTriggerName)
+		}
+	}
+
+	return names
+}
+
+func isClusterInRecords(records map[string]*unresolvedDestinationRecord, clusterKey string) bool {
+	for _, record := range records {
+		for i := range record.clusters {
+			if getClusterKey(&record.clusters[i]) == clusterKey {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasTemplatedDestinations returns true if destination clusters are instantiated from DestinationCluster
+// or DestinationClusters
+func hasTemplatedDestinations(eventTrigger *v1beta1.EventTrigger) bool {
+	return eventTrigger.Spec.DestinationCluster != nil || eventTrigger.Spec.DestinationClusters != ""
+}
+
+// getDestinationResolution returns why the cluster cannot be used as destination. An empty string is
+// returned if cluster exists and is ready.
+func getDestinationResolution(ctx context.Context, c client.Client, cluster *corev1.ObjectReference,
+	logger logr.Logger) (string, error) {
+
+	clusterObject, err := clusterproxy.GetCluster(ctx, c, cluster.Namespace, cluster.Name,
+		clusterproxy.GetClusterType(cluster))
+	if err != nil {
+		if apierrors.IsNotFound(err) || meta.IsNoMatchError(err) {
+			return "cluster does not exist", nil
+		}
+		return "", err
+	}
+
+	if !clusterObject.GetDeletionTimestamp().IsZero() {
+		return "cluster is being deleted", nil
+	}
+
+	ready, err := clusterproxy.IsClusterReadyToBeConfigured(ctx, c, cluster, logger)
+	if err != nil {
+		return "", err
+	}
+	if !ready {
+		return "cluster is not ready", nil
+	}
+
+	return "", nil
+}
+
+// resolveDestinationClusters verifies the destination clusters instantiated from DestinationCluster or
+// DestinationClusters exist and are ready. Unresolved destinations are recorded for the event.
+// If EventTrigger UnresolvedDestinationPolicy is Wait and any destination is not resolved, an
+// unresolvedDestinationError is returned.
+func resolveDestinationClusters(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	cause *eventCause, clusterProfileSpec *configv1beta1.Spec, logger logr.Logger) error {
+
+	if !hasTemplatedDestinations(eventTrigger) {
+		setUnresolvedDestinations(eventTrigger.Name, cause, nil)
+		return nil
+	}
+
+	unresolved := make([]corev1.ObjectReference, 0)
+	unresolvedKeys := make([]string, 0)
+	for i := range clusterProfileSpec.ClusterRefs {
+		cluster := &clusterProfileSpec.ClusterRefs[i]
+		reason, err := getDestinationResolution(ctx, c, cluster, logger)
+		if err != nil {
+			return err
+		}
+		if reason != "" {
+			logger.V(logs.LogDebug).Info(fmt.Sprintf("destination cluster %s: %s", getClusterKey(cluster), reason))
+			unresolved = append(unresolved, *cluster)
+			unresolvedKeys = append(unresolvedKeys, getClusterKey(cluster))
+		}
+	}
+
+	setUnresolvedDestinations(eventTrigger.Name, cause, unresolved)
+
+	if len(unresolved) != 0 &&
+		eventTrigger.Spec.UnresolvedDestinationPolicy == v1beta1.UnresolvedDestinationPolicyWait {
+
+		return &unresolvedDestinationError{clusters: unresolvedKeys}
+	}
+
+	return nil
+}
+
+// writeUnresolvedDestinations writes, for all the unresolved destinations recorded for events in
+// sourceCluster, the current resolution
+func writeUnresolvedDestinations(ctx context.Context, c client.Client, h hash.Hash, eventTriggerName string,
+	sourceCluster *corev1.ObjectReference, logger logr.Logger) error {
+
+	records := getUnresolvedDestinationRecords(eventTriggerName, sourceCluster)
+
+	resolutions := make([]string, 0)
+	for i := range records {
+		for j := range records[i].clusters {
+			cluster := &records[i].clusters[j]
+			reason, err := getDestinationResolution(ctx, c, cluster, logger)
+			if err != nil {
+				return err
+			}
+			resolutions = append(resolutions, fmt.Sprintf("%s=%s", getClusterKey(cluster), reason))
+		}
+	}
+
+	sort.Strings(resolutions)
+	for i := range resolutions {
+		h.Write([]byte(resolutions[i]))
+	}
+
+	return nil
+}
+
+// getUnresolvedDestinations returns the destination clusters, instantiated for events in clusters
+// currently matching the EventTrigger, which still do not exist or are not ready
+func getUnresolvedDestinations(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	logger logr.Logger) ([]v1beta1.UnresolvedDestination, error) {
+
+	matchingClusters := make(map[string]bool)
+	for i := range eventTrigger.Status.MatchingClusterRefs {
+		matchingClusters[getClusterKey(&eventTrigger.Status.MatchingClusterRefs[i])] = true
+	}
+
+	result := make([]v1beta1.UnresolvedDestination, 0)
+	records := getUnresolvedDestinationRecords(eventTrigger.Name, nil)
+	for i := range records {
+		record := &records[i]
+		if !matchingClusters[getClusterKey(&record.cause.Cluster)] {
+			continue
+		}
+
+		for j := range record.clusters {
+			reason, err := getDestinationResolution(ctx, c, &record.clusters[j], logger)
+			if err != nil {
+				return nil, err
+			}
+			if reason == "" {
+				continue
+			}
+
+			unresolved := v1beta1.UnresolvedDestination{
+				Cluster:            record.clusters[j],
+				Reason:             reason,
+				SourceCluster:      record.cause.Cluster,
+				TriggeringResource: record.cause.TriggeringResource,
+			}
+			if record.cause.CloudEvent != nil {
+				unresolved.CloudEventSource = record.cause.CloudEvent.Source
+				unresolved.CloudEventSubject = record.cause.CloudEvent.Subject
+			}
+			result = append(result, unresolved)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return getUnresolvedDestinationSortKey(&result[i]) < getUnresolvedDestinationSortKey(&result[j])
+	})
+
+	return result, nil
+}
+
+func getUnresolvedDestinationSortKey(unresolved *v1beta1.UnresolvedDestination) string {
+	key := getClusterKey(&unresolved.SourceCluster)
+	if unresolved.TriggeringResource != nil {
+		key += fmt.Sprintf(":%s/%s", unresolved.TriggeringResource.Namespace, unresolved.TriggeringResource.Name)
+	}
+	key += fmt.Sprintf(":%s/%s", unresolved.CloudEventSource, unresolved.CloudEventSubject)
+	return key + ":" + getClusterKey(&unresolved.Cluster)
+}
+
+// updateUnresolvedDestinationStatuses reports, in each EventTrigger Status, the destination clusters
+// which do not exist or are not ready
+func updateUnresolvedDestinationStatuses(ctx context.Context, c client.Client,
+	eventTriggers *v1beta1.EventTriggerList, logger logr.Logger) {
+
+	existing := make(map[string]bool, len(eventTriggers.Items))
+	for i := range eventTriggers.Items {
+		existing[eventTriggers.Items[i].Name] = true
+	}
+
+	// Forget about deleted EventTriggers
+	unresolvedDestinationsLock.Lock()
+	for eventTriggerName := range unresolvedDestinations {
+		if !existing[eventTriggerName] {
+			delete(unresolvedDestinations, eventTriggerName)
+		}
+	}
+	unresolvedDestinationsLock.Unlock()
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+
+		current, err := getUnresolvedDestinations(ctx, c, et, logger)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get EventTrigger %s unresolved destinations: %v",
+				et.Name, err))
+			continue
+		}
+
+		if reflect.DeepEqual(et.Status.UnresolvedDestinations, current) {
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.UnresolvedDestinations = current
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventTrigger %s unresolved destinations: %v",
+				et.Name, err))
+		}
+	}
+}
+
+// getExistingClusterProfile returns the ClusterProfile with the given name if it exists. While waiting
+// for destination clusters, a ClusterProfile previously generated for the event is left untouched.
+func getExistingClusterProfile(ctx context.Context, c client.Client, clusterProfileName string,
+) (*configv1beta1.ClusterProfile, error) {
+
+	clusterProfile := &configv1beta1.ClusterProfile{}
+	err := c.Get(ctx, types.NamespacedName{Name: clusterProfileName}, clusterProfile)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return clusterProfile, nil
+}
+
+// getExistingFromGenerators returns the ConfigMaps and Secrets, with the given labels, previously
+// instantiated from ConfigMapGenerator and SecretGenerator. While waiting for destination clusters,
+// those are left untouched.
+func getExistingFromGenerators(ctx context.Context, c client.Client, labels map[string]string,
+) ([]libsveltosv1beta1.PolicyRef, error) {
+
+	result := make([]libsveltosv1beta1.PolicyRef, 0)
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.List(ctx, configMaps, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		result = append(result, *getPolicyRef(&configMaps.Items[i]))
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		result = append(result, *getPolicyRef(&secrets.Items[i]))
+	}
+
+	return result, nil
+}
+
+// getExistingFromResourceGenerators returns the resources, with the given labels, previously instantiated
+// from ResourceGenerator. While waiting for destination clusters, those are left untouched.
+func getExistingFromResourceGenerators(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	labels map[string]string, logger logr.Logger) ([]corev1.ObjectReference, error) {
+
+	resources, err := listGeneratedResources(ctx, c, getGeneratedResourceKinds(eventTrigger), labels, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]corev1.ObjectReference, len(resources))
+	for i := range resources {
+		apiVersion, kind := resources[i].GroupVersionKind().ToAPIVersionAndKind()
+		result[i] = corev1.ObjectReference{APIVersion: apiVersion, Kind: kind,
+			Namespace: resources[i].GetNamespace(), Name: resources[i].GetName()}
+	}
+
+	return result, nil
+}
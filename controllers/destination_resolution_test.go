@@ -0,0 +1,150 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Destination resolution", func() {
+	It("resolveDestinationClusters reports destination clusters which do not exist or are not ready", func() {
+		sourceCluster := &libsveltosv1beta1.SveltosCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+			Status: libsveltosv1beta1.SveltosClusterStatus{
+				Ready: true,
+			},
+		}
+
+		missingCluster := corev1.ObjectReference{
+			Namespace:  randomString(),
+			Name:       randomString(),
+			Kind:       libsveltosv1beta1.SveltosClusterKind,
+			APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(sourceCluster).Build()
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		sourceClusterRef := corev1.ObjectReference{
+			Namespace:  sourceCluster.Namespace,
+			Name:       sourceCluster.Name,
+			Kind:       libsveltosv1beta1.SveltosClusterKind,
+			APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DestinationClusters:  "{{ .Cluster.metadata.name }}",
+				IncludeSourceCluster: true,
+			},
+			Status: v1beta1.EventTriggerStatus{
+				MatchingClusterRefs: []corev1.ObjectReference{sourceClusterRef},
+			},
+		}
+		defer controllers.RemoveUnresolvedDestinations(eventTrigger.Name)
+
+		resource := corev1.ObjectReference{Namespace: randomString(), Name: randomString()}
+		cause := controllers.GetEventCause(sourceCluster.Namespace, sourceCluster.Name,
+			libsveltosv1beta1.ClusterTypeSveltos, nil, &controllers.CurrentObject{MatchingResource: resource})
+
+		spec := &configv1beta1.Spec{ClusterRefs: []corev1.ObjectReference{sourceClusterRef, missingCluster}}
+
+		// ClusterProfile is created anyway by default
+		Expect(controllers.ResolveDestinationClusters(context.TODO(), c, eventTrigger, cause, spec,
+			logger)).To(Succeed())
+
+		unresolved, err := controllers.GetUnresolvedDestinations(context.TODO(), c, eventTrigger, logger)
+		Expect(err).To(BeNil())
+		Expect(len(unresolved)).To(Equal(1))
+		Expect(unresolved[0].Cluster).To(Equal(missingCluster))
+		Expect(unresolved[0].SourceCluster.Name).To(Equal(sourceCluster.Name))
+		Expect(unresolved[0].TriggeringResource).ToNot(BeNil())
+		Expect(*unresolved[0].TriggeringResource).To(Equal(resource))
+
+		Expect(controllers.GetEventTriggersWaitingForCluster(&missingCluster)).To(ContainElement(eventTrigger.Name))
+		Expect(controllers.GetEventTriggersWaitingForCluster(&sourceClusterRef)).ToNot(ContainElement(eventTrigger.Name))
+		Expect(controllers.IsWaitingForDestinations(eventTrigger, cause)).To(BeFalse())
+
+		// Wait for destination clusters
+		eventTrigger.Spec.UnresolvedDestinationPolicy = v1beta1.UnresolvedDestinationPolicyWait
+		err = controllers.ResolveDestinationClusters(context.TODO(), c, eventTrigger, cause, spec, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsUnresolvedDestination(err)).To(BeTrue())
+		// Nothing is instantiated for the event: previously generated ConfigMaps are kept
+		Expect(controllers.IsWaitingForDestinations(eventTrigger, cause)).To(BeTrue())
+		labels := map[string]string{randomString(): randomString()}
+		generated := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+				Labels:    labels,
+			},
+		}
+		Expect(c.Create(context.TODO(), generated)).To(Succeed())
+		existing, err := controllers.GetExistingFromGenerators(context.TODO(), c, labels)
+		Expect(err).To(BeNil())
+		Expect(len(existing)).To(Equal(1))
+		Expect(existing[0].Name).To(Equal(generated.Name))
+		Expect(existing[0].Kind).To(Equal(string(libsveltosv1beta1.ConfigMapReferencedResourceKind)))
+
+		// Destination cluster exists but it is not ready
+		destination := &libsveltosv1beta1.SveltosCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: missingCluster.Namespace,
+				Name:      missingCluster.Name,
+			},
+		}
+		Expect(c.Create(context.TODO(), destination)).To(Succeed())
+
+		unresolved, err = controllers.GetUnresolvedDestinations(context.TODO(), c, eventTrigger, logger)
+		Expect(err).To(BeNil())
+		Expect(len(unresolved)).To(Equal(1))
+		Expect(unresolved[0].Reason).To(ContainSubstring("not ready"))
+
+		// Destination cluster is ready
+		destination.Status.Ready = true
+		Expect(c.Update(context.TODO(), destination)).To(Succeed())
+
+		unresolved, err = controllers.GetUnresolvedDestinations(context.TODO(), c, eventTrigger, logger)
+		Expect(err).To(BeNil())
+		Expect(unresolved).To(BeEmpty())
+
+		Expect(controllers.ResolveDestinationClusters(context.TODO(), c, eventTrigger, cause, spec,
+			logger)).To(Succeed())
+		Expect(controllers.GetEventTriggersWaitingForCluster(&missingCluster)).ToNot(ContainElement(eventTrigger.Name))
+		Expect(controllers.IsWaitingForDestinations(eventTrigger, cause)).To(BeFalse())
+	})
+})
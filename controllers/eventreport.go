@@ -59,8 +59,31 @@ func getEventReportClient(ctx context.Context, clusterNamespace, clusterName str
 	// ResourceSummary is a Sveltos resource created in managed clusters.
 	// Sveltos resources are always created using cluster-admin so that admin does not need to be
 	// given such permissions.
-	return clusterproxy.GetKubernetesClient(ctx, getManagementClusterClient(),
-		clusterNamespace, clusterName, "", "", clusterType, logger)
+	return getRemoteClient(ctx, getManagementClusterClient(), clusterNamespace, clusterName,
+		clusterType, logger)
+}
+
+// getEventReportReader returns the reader to use to list EventReports for a cluster. Same as
+// getEventReportClient, but EventReports in managed clusters are read from an informer cache.
+func getEventReportReader(ctx context.Context, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) (client.Reader, error) {
+
+	if getAgentInMgmtCluster() {
+		return getManagementClusterClient(), nil
+	}
+
+	isPullMode, err := clusterproxy.IsClusterInPullMode(ctx, getManagementClusterClient(), clusterNamespace, clusterName,
+		clusterType, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if isPullMode {
+		return getManagementClusterClient(), nil
+	}
+
+	return getRemoteEventReportReader(ctx, getManagementClusterClient(), clusterNamespace, clusterName,
+		clusterType, logger)
 }
 
 // When sveltos-agent is running in the management cluster, EventSources are not copied to the managed cluster.
@@ -50,6 +50,9 @@ var (
 	mgmtClusterConfig *rest.Config
 )
 
+// errClusterNotReady is returned when EventReports are not collected because cluster is not ready
+var errClusterNotReady = errors.New("cluster is not ready to be configured")
+
 // removeEventReports deletes all EventReport corresponding to EventSource instance
 func removeEventReports(ctx context.Context, c client.Client, eventSourceName string,
 	logger logr.Logger) error {
@@ -125,7 +128,7 @@ func buildEventTriggersForEventSourceMap(ctx context.Context, cluster *corev1.Ob
 		et := &eventTriggers.Items[i]
 
 		eventSourceName, err := libsveltostemplate.GetReferenceResourceName(ctx, getManagementClusterClient(),
-			cluster.Namespace, cluster.Name, et.Spec.EventSourceName, clusterType)
+			cluster.Namespace, cluster.Name, getEventSourceName(et), clusterType)
 		if err != nil {
 			return nil, err
 		}
@@ -175,8 +178,37 @@ func buildEventTriggersForClusterMap(eventTriggers *v1beta1.EventTriggerList,
 	return eventTriggerMap
 }
 
-// Periodically collects EventReports from each cluster (excluding pull mode clusters)
-func collectEventReports(config *rest.Config, c client.Client, s *runtime.Scheme,
+// eventReportCollector runs the EventReport collection loop. It is added to the manager as a
+// Runnable which requires leader election, so when running multiple replicas only the leader
+// collects and processes EventReports.
+// When EventTriggers are sharded across replicas, leader election is disabled. Every replica
+// collects EventReports and processes those for the EventTriggers it owns.
+type eventReportCollector struct {
+	config                *rest.Config
+	client                client.Client
+	scheme                *runtime.Scheme
+	shardKey              string
+	capiOnboardAnnotation string
+	version               string
+	logger                logr.Logger
+}
+
+// Start implements manager.Runnable
+func (e *eventReportCollector) Start(ctx context.Context) error {
+	collectEventReports(ctx, e.config, e.client, e.scheme, e.shardKey, e.capiOnboardAnnotation,
+		e.version, e.logger)
+	return nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (e *eventReportCollector) NeedLeaderElection() bool {
+	return true
+}
+
+// Periodically collects EventReports from each cluster (excluding pull mode clusters).
+// Returns when ctx is cancelled. Cluster being processed at that time is given
+// collectionDrainTimeout to complete.
+func collectEventReports(ctx context.Context, config *rest.Config, c client.Client, s *runtime.Scheme,
 	shardKey, capiOnboardAnnotation, version string, logger logr.Logger) {
 
 	interval := 10 * time.Second
@@ -188,56 +220,140 @@ func collectEventReports(config *rest.Config, c client.Client, s *runtime.Scheme
 	mgmtClusterSchema = s
 	mgmtClusterConfig = config
 
-	ctx := context.TODO()
+	setCollectionRunning(true)
+	defer setCollectionRunning(false)
+
 	for {
-		logger.V(logs.LogDebug).Info("collecting EventTriggers")
-		// get all EventTriggers
-		eventTriggers := &v1beta1.EventTriggerList{}
-		err := c.List(ctx, eventTriggers)
+		err := collectEventReportsOnce(ctx, c, shardKey, capiOnboardAnnotation, version, interval, logger)
+		if err == nil {
+			setCollectionIterationCompleted()
+		}
+
+		if !waitForNextCollection(ctx, interval) {
+			logger.V(logs.LogInfo).Info("stopping EventReport collection")
+			return
+		}
+	}
+}
+
+// collectEventReportsOnce runs one iteration of the collection loop
+func collectEventReportsOnce(ctx context.Context, c client.Client, shardKey, capiOnboardAnnotation, version string,
+	interval time.Duration, logger logr.Logger) error {
+
+	logger.V(logs.LogDebug).Info("collecting EventTriggers")
+	// get all EventTriggers
+	eventTriggers := &v1beta1.EventTriggerList{}
+	err := c.List(ctx, eventTriggers)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get eventTriggers: %v", err))
+		return err
+	}
+
+	// When EventTriggers are sharded across replicas, EventReports routed to this replica are
+	// processed for all consuming EventTriggers, owned or not. Reprocess requests and status are
+	// instead handled by the EventTrigger owner only.
+	ownedEventTriggers := getOwnedEventTriggers(eventTriggers)
+
+	// build a map eventTrigger: matching clusters
+	eventTriggerMap := buildEventTriggersForClusterMap(eventTriggers)
+
+	reprocessRequests := getReprocessRequests(ownedEventTriggers)
+
+	// Resources referenced by EventTriggers not depending on the cluster are fetched once per iteration
+	independent := make(map[string]*clusterIndependentResources)
+
+	logger.V(logs.LogDebug).Info("collecting managed clusters")
+	clusterList, err := clusterproxy.GetListOfClustersForShardKey(ctx, c, "", capiOnboardAnnotation,
+		shardKey, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get clusters: %v", err))
+		return err
+	}
+
+	// On shutdown no new cluster is processed, while the cluster being processed is given
+	// some time to complete
+	processCtx, cancel := getDrainContext(ctx, collectionDrainTimeout)
+	defer cancel()
+
+	for i := range clusterList {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		cluster := &clusterList[i]
+
+		// Build a map of EventTrigger consuming an EventSource. This is built once per cluster
+		// as EventSourceName in EventTrigger.Spec can be expressed as a template and instantiated
+		// using cluster namespace, name and type.
+		eventSourceMap, err := buildEventTriggersForEventSourceMap(processCtx, cluster, eventTriggers)
 		if err != nil {
-			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get eventTriggers: %v", err))
-			time.Sleep(interval)
+			waitForNextCollection(ctx, interval)
 			continue
 		}
 
-		// build a map eventTrigger: matching clusters
-		eventTriggerMap := buildEventTriggersForClusterMap(eventTriggers)
-
-		logger.V(logs.LogDebug).Info("collecting managed clusters")
-		clusterList, err := clusterproxy.GetListOfClustersForShardKey(ctx, c, "", capiOnboardAnnotation,
-			shardKey, logger)
+		err = collectAndProcessEventReportsFromCluster(processCtx, c, cluster, eventSourceMap, eventTriggerMap,
+			reprocessRequests, independent, version, logger)
+		if errors.Is(err, errClusterNotReady) {
+			// Nothing was processed. Reprocess requests for this cluster stay pending.
+			logger.V(logs.LogDebug).Info(fmt.Sprintf("cluster %s/%s is not ready yet",
+				cluster.Namespace, cluster.Name))
+			continue
+		}
 		if err != nil {
-			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get clusters: %v", err))
-			time.Sleep(interval)
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to collect EventReports from cluster: %s/%s %v",
+				cluster.Namespace, cluster.Name, err))
 			continue
 		}
+		setClusterReprocessed(reprocessRequests, cluster)
+	}
 
-		for i := range clusterList {
-			cluster := &clusterList[i]
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
 
-			// Build a map of EventTrigger consuming an EventSource. This is built once per cluster
-			// as EventSourceName in EventTrigger.Spec can be expressed as a template and instantiated
-			// using cluster namespace, name and type.
-			eventSourceMap, err := buildEventTriggersForEventSourceMap(ctx, cluster, eventTriggers)
-			if err != nil {
-				time.Sleep(interval)
-				continue
-			}
+	completeReprocessRequests(ctx, c, ownedEventTriggers, reprocessRequests, eventTriggerMap, clusterList, logger)
+	updateScheduleStatuses(ctx, c, ownedEventTriggers, logger)
+	updateApprovalStatuses(ctx, c, ownedEventTriggers, logger)
+	updateUnresolvedDestinationStatuses(ctx, c, ownedEventTriggers, logger)
+	updateInstantiationFailureStatuses(ctx, c, ownedEventTriggers, logger)
+	updateBlockedEventStatuses(ctx, c, ownedEventTriggers, logger)
 
-			err = collectAndProcessEventReportsFromCluster(ctx, c, cluster, eventSourceMap, eventTriggerMap,
-				version, logger)
-			if err != nil {
-				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to collect EventReports from cluster: %s/%s %v",
-					cluster.Namespace, cluster.Name, err))
-			}
+	// Digests of objects not used during a whole iteration (deleted or not referenced anymore)
+	getDigestCache().evictUnused()
+
+	return nil
+}
+
+// waitForNextCollection waits for interval. Returns false if ctx is cancelled meanwhile.
+func waitForNextCollection(ctx context.Context, interval time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-time.After(interval):
+		return true
+	}
+}
+
+// getDrainContext returns a context which is cancelled timeout after ctx is cancelled
+func getDrainContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	drainCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(ctx, func() {
+		select {
+		case <-drainCtx.Done():
+		case <-time.After(timeout):
+			cancel()
 		}
+	})
 
-		time.Sleep(interval)
+	return drainCtx, func() {
+		stop()
+		cancel()
 	}
 }
 
 func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Client, cluster *corev1.ObjectReference,
 	eventSourceMap map[string][]*v1beta1.EventTrigger, eventTriggerMap map[string]libsveltosset.Set,
+	reprocessRequests map[string]*reprocessRequest, independent map[string]*clusterIndependentResources,
 	version string, logger logr.Logger) error {
 
 	logger = logger.WithValues("cluster", fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name))
@@ -254,7 +370,7 @@ func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Clie
 	}
 
 	if !ready {
-		return nil
+		return errClusterNotReady
 	}
 
 	isPullMode, err := clusterproxy.IsClusterInPullMode(ctx, c, cluster.Namespace, cluster.Name,
@@ -297,8 +413,17 @@ func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Clie
 		)
 	}
 
+	// EventReports are read through an informer cache, while updates go through clusterClient
+	eventReportReader, err := getEventReportReader(ctx, cluster.Namespace, cluster.Name,
+		clusterproxy.GetClusterType(clusterRef), logger)
+	if err != nil {
+		return err
+	}
+
+	listCtx, cancel := context.WithTimeout(ctx, eventReportSyncTimeout)
+	defer cancel()
 	eventReportList := libsveltosv1beta1.EventReportList{}
-	err = clusterClient.List(ctx, &eventReportList, listOptions...)
+	err = eventReportReader.List(listCtx, &eventReportList, listOptions...)
 	if err != nil {
 		return err
 	}
@@ -311,6 +436,16 @@ func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Clie
 		}
 
 		l := logger.WithValues("eventReport", er.Name)
+		// EventTriggers with a pending reprocess request for this cluster force EventReport
+		// to be processed regardless of its phase
+		eventTriggers := eventSourceMap[er.Labels[libsveltosv1beta1.EventSourceNameLabel]]
+		if isTriggerShardingEnabled() &&
+			!isEventReportRouted(er.Labels[libsveltosv1beta1.EventSourceNameLabel], eventTriggers) {
+			// EventReport is processed by another replica
+			continue
+		}
+		reprocess := isReprocessRequested(reprocessRequests, eventTriggers, cluster)
+
 		// First update/delete eventReports in managemnent cluster
 		var mgmtClusterEventReport *libsveltosv1beta1.EventReport
 		if !er.DeletionTimestamp.IsZero() {
@@ -318,13 +453,15 @@ func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Clie
 			err = deleteEventReport(ctx, c, cluster, er, l)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to delete EventReport in management cluster. Err: %v", err))
+				setClusterReprocessFailed(reprocessRequests, eventTriggers, cluster)
 				continue
 			}
-		} else if shouldReprocess(er) {
+		} else if reprocess || shouldReprocess(er) {
 			logger.V(logs.LogDebug).Info("updating in management cluster")
 			mgmtClusterEventReport, err = updateEventReport(ctx, c, cluster, er, isPullMode, l)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventReport in management cluster. Err: %v", err))
+				setClusterReprocessFailed(reprocessRequests, eventTriggers, cluster)
 				continue
 			}
 		}
@@ -338,9 +475,12 @@ func collectAndProcessEventReportsFromCluster(ctx context.Context, c client.Clie
 			}
 		}
 
-		err = updateAllClusterProfiles(ctx, c, cluster, er, eventSourceMap, eventTriggerMap, logger)
+		err = updateAllClusterProfiles(ctx, c, cluster, er, eventSourceMap, eventTriggerMap, reprocessRequests,
+			independent, logger)
 		if err == nil {
 			updateEventReportStatus(ctx, clusterClient, er, logger)
+		} else {
+			setClusterReprocessFailed(reprocessRequests, eventTriggers, cluster)
 		}
 	}
 
@@ -419,7 +559,8 @@ func isEventTriggerMatchingTheCluster(et *v1beta1.EventTrigger, cluster *corev1.
 
 func updateAllClusterProfiles(ctx context.Context, mgmtClient client.Client, cluster *corev1.ObjectReference,
 	er *libsveltosv1beta1.EventReport, eventSourceMap map[string][]*v1beta1.EventTrigger,
-	eventTriggerMap map[string]libsveltosset.Set, logger logr.Logger) error {
+	eventTriggerMap map[string]libsveltosset.Set, reprocessRequests map[string]*reprocessRequest,
+	independent map[string]*clusterIndependentResources, logger logr.Logger) error {
 
 	clusterType := clusterproxy.GetClusterType(cluster)
 
@@ -430,6 +571,11 @@ func updateAllClusterProfiles(ctx context.Context, mgmtClient client.Client, clu
 	// Get all EventTriggers referencing this EventSource
 	eventTriggers := eventSourceMap[eventSourceName]
 
+	// EventReport changes not yet turned into ClusterProfile changes
+	hasPendingChanges := shouldReprocess(er) || !er.DeletionTimestamp.IsZero()
+	queueKey := getScheduleQueueKey(cluster.Namespace, cluster.Name, string(clusterType), er.Name)
+	queued := false
+
 	// For each EventTrigger
 	for i := range eventTriggers {
 		l := logger.WithValues("eventTrigger", eventTriggers[i].Name)
@@ -440,14 +586,67 @@ func updateAllClusterProfiles(ctx context.Context, mgmtClient client.Client, clu
 			continue
 		}
 
+		// A reprocess request is handled like any other change
+		hasChanges := hasPendingChanges || reprocessRequests[eventTriggers[i].Name].isClusterRequested(cluster)
+
+		// If EventTrigger has a Schedule and no window is currently open, changes are queued
+		if !canApplyChanges(eventTriggers[i], queueKey, hasChanges, l) {
+			queued = queued || hasChanges
+			continue
+		}
+
+		// Template policy is resolved once and used for every template rendered while instantiating
+		if err := resolveTemplatePolicy(ctx, mgmtClient, eventTriggers[i]); err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to resolve template policy: %v", err))
+			return err
+		}
+
+		fingerprint, err := getInstantiationFingerprint(ctx, mgmtClient, cluster.Namespace, cluster.Name,
+			clusterType, eventTriggers[i], getClusterIndependentResources(independent, eventTriggers[i]), er, l)
+		if err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to compute fingerprint: %v", err))
+			return err
+		}
+
+		// A reprocess request forces instantiation
+		if !reprocessRequests[eventTriggers[i].Name].isClusterRequested(cluster) {
+			skip, err := canSkipInstantiation(ctx, mgmtClient, cluster.Namespace, cluster.Name, clusterType,
+				eventTriggers[i], er, fingerprint, logger)
+			if err != nil {
+				return err
+			}
+			if skip {
+				l.V(logs.LogDebug).Info("EventReport and EventTrigger inputs unchanged. Skip updating ClusterProfile")
+				continue
+			}
+		}
+
 		l.V(logs.LogDebug).Info("updating ClusterProfile")
-		err := updateClusterProfiles(ctx, mgmtClient, cluster.Namespace, cluster.Name, clusterType,
+		err = updateClusterProfiles(ctx, mgmtClient, cluster.Namespace, cluster.Name, clusterType,
 			eventTriggers[i], er, logger)
+		if isTenantAccessDenied(err) || isTemplatePolicyViolation(err) {
+			setInstantiationFailure(eventTriggers[i].Name, cluster, err)
+		} else if err == nil {
+			clearInstantiationFailure(eventTriggers[i].Name, cluster)
+		}
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update ClusterProfile for EventTrigger %s: %v",
 				eventTriggers[i].GetName(), err))
 			return err
 		}
+
+		err = recordInstantiationFingerprint(ctx, mgmtClient, cluster.Namespace, cluster.Name, clusterType,
+			eventTriggers[i], er, fingerprint, l)
+		if err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to record fingerprint: %v", err))
+			return err
+		}
+	}
+
+	if queued {
+		// Do not mark EventReport as processed. Changes (including CloudEvents) must be
+		// kept till a schedule window opens
+		return errOutsideScheduleWindow
 	}
 
 	return nil
@@ -38,6 +38,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/event-manager/api/v1beta1"
 	"github.com/projectsveltos/event-manager/pkg/scope"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
@@ -146,10 +147,11 @@ type EventTriggerReconciler struct {
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventtriggers,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventtriggers/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventtriggers/finalizers,verbs=update
-//+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventsources,verbs=get;list;watch
+//+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventsources,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventreports,verbs=create;update;delete;get;watch;list
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=eventreports/status,verbs=get;list;update
 //+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clusterprofiles,verbs=get;list;update;create;delete;watch;patch
+//+kubebuilder:rbac:groups=config.projectsveltos.io,resources=clustersummaries,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=clustersets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=clustersets/status,verbs=get;watch;list
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=configurationgroups,verbs=get;list;watch;create;delete;update;patch
@@ -162,6 +164,7 @@ type EventTriggerReconciler struct {
 //+kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines/status,verbs=get;watch;list
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=sveltosclusters,verbs=get;watch;list
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=sveltosclusters/status,verbs=get;watch;list
+//+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=rolerequests,verbs=get;watch;list
 //+kubebuilder:rbac:groups="",resources=secrets,verbs="*"
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs="*"
 //+kubebuilder:rbac:groups="source.toolkit.fluxcd.io",resources=gitrepositories,verbs=get;watch;list
@@ -189,6 +192,12 @@ func (r *EventTriggerReconciler) Reconcile(ctx context.Context, req ctrl.Request
 		)
 	}
 
+	// When EventTriggers are sharded across replicas, only the owner reconciles it
+	if !isEventTriggerOwned(eventTrigger) {
+		logger.V(logs.LogDebug).Info("EventTrigger is owned by another replica")
+		return reconcile.Result{}, nil
+	}
+
 	eventTriggerScope, err := scope.NewEventTriggerScope(scope.EventTriggerScopeParams{
 		Client:         r.Client,
 		Logger:         logger,
@@ -231,6 +240,12 @@ func (r *EventTriggerReconciler) reconcileDelete(
 	logger.V(logs.LogInfo).Info("Reconciling EventTrigger delete")
 
 	eventTriggerScope.SetMatchingClusterRefs(nil)
+	eventTriggerScope.SetDestinationMatchingClusterRefs(nil)
+	eventTriggerScope.SetUnresolvedDestinations(nil)
+	removeUnresolvedDestinations(eventTriggerScope.Name())
+	removeInstantiationFailures(eventTriggerScope.Name())
+	removeBlockedEvents(eventTriggerScope.Name())
+	removeTemplatePolicy(eventTriggerScope.Name())
 
 	r.cleanMaps(eventTriggerScope)
 
@@ -244,6 +259,8 @@ func (r *EventTriggerReconciler) reconcileDelete(
 		controllerutil.RemoveFinalizer(eventTriggerScope.EventTrigger, v1beta1.EventTriggerFinalizer)
 	}
 
+	getDigestCache().forget(v1beta1.EventTriggerKind, "", eventTriggerScope.Name())
+
 	logger.V(logs.LogInfo).Info("Reconcile delete success")
 	return reconcile.Result{}
 }
@@ -262,6 +279,12 @@ func (r *EventTriggerReconciler) reconcileNormal(
 		}
 	}
 
+	err := reconcileInlineEventSource(ctx, r.Client, eventTriggerScope.EventTrigger, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to reconcile inline EventSource: %v", err))
+		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
+	}
+
 	matchingCluster, err := clusterproxy.GetMatchingClusters(ctx, r.Client, eventTriggerScope.GetSelector(), "",
 		r.CapiOnboardAnnotation, eventTriggerScope.Logger)
 	if err != nil {
@@ -298,19 +321,41 @@ func (r *EventTriggerReconciler) reconcileNormal(
 		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
 	}
 
+	// Templates in referenced resource names are rendered while deploying
+	if err := resolveTemplatePolicy(ctx, r.Client, eventTriggerScope.EventTrigger); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to resolve template policy: %v", err))
+		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
+	}
+
 	f := getHandlersForFeature(v1beta1.FeatureEventTrigger)
 	if err := r.deployEventTrigger(ctx, eventTriggerScope, f, logger); err != nil {
 		logger.V(logs.LogInfo).Error(err, "failed to deploy")
 		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
 	}
 
+	generatedClusterProfiles, err := getGeneratedClusterProfileStatuses(ctx, r.Client,
+		eventTriggerScope.EventTrigger.Name)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to collect generated ClusterProfile statuses: %v", err))
+		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
+	}
+	eventTriggerScope.SetGeneratedClusterProfiles(generatedClusterProfiles)
+	eventTriggerScope.SetDestinationMatchingClusterRefs(getDestinationMatchingClusterRefs(generatedClusterProfiles))
+
+	unresolvedDestinations, err := getUnresolvedDestinations(ctx, r.Client, eventTriggerScope.EventTrigger, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to collect unresolved destinations: %v", err))
+		return reconcile.Result{Requeue: true, RequeueAfter: normalRequeueAfter}
+	}
+	eventTriggerScope.SetUnresolvedDestinations(unresolvedDestinations)
+
 	logger.V(logs.LogInfo).Info("Reconcile success")
 	return reconcile.Result{}
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *EventTriggerReconciler) SetupWithManager(mgr ctrl.Manager) (controller.Controller, error) {
-	c, err := ctrl.NewControllerManagedBy(mgr).
+	b := ctrl.NewControllerManagedBy(mgr).
 		For(&v1beta1.EventTrigger{}).
 		WithOptions(controller.Options{
 			MaxConcurrentReconciles: r.ConcurrentReconciles,
@@ -351,26 +396,56 @@ func (r *EventTriggerReconciler) SetupWithManager(mgr ctrl.Manager) (controller.
 				SecretPredicates(mgr.GetLogger().WithValues("predicate", "secretpredicate")),
 			),
 		).
-		Build(r)
-	if err != nil {
-		return nil, errors.Wrap(err, "error creating controller")
+		Watches(&configv1beta1.ClusterProfile{},
+			handler.EnqueueRequestsFromMapFunc(r.requeueEventTriggerForClusterProfile),
+			builder.WithPredicates(
+				ClusterProfilePredicates(mgr.GetLogger().WithValues("predicate", "clusterprofilepredicate")),
+			),
+		).
+		Watches(&configv1beta1.ClusterSummary{},
+			handler.EnqueueRequestsFromMapFunc(r.requeueEventTriggerForClusterSummary),
+			builder.WithPredicates(
+				ClusterSummaryPredicates(mgr.GetLogger().WithValues("predicate", "clustersummarypredicate")),
+			),
+		)
+
+	if isTriggerShardingEnabled() {
+		// When members change, all EventTriggers are requeued so new owners pick them up
+		b = b.WatchesRawSource(source.Channel(getTriggerSharding().events, &handler.EnqueueRequestForObject{}))
+
+		group := r.ShardKey
+		if group == "" {
+			group = defaultShardGroup
+		}
+		err := mgr.Add(&triggerShardMembership{
+			client: mgr.GetClient(),
+			reader: mgr.GetAPIReader(),
+			group:  group,
+			logger: mgr.GetLogger().WithValues("runnable", "trigger-shard-membership"),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error adding trigger shard membership")
+		}
 	}
 
-	/* TODO
-	// When projectsveltos ClusterProfile changes, according to ClusterProfilePredicates,
-	// one or more EventTriggers need to be reconciled.
-	err = c.Watch(&source.Kind{Type: &configv1beta1.ClusterProfile{}},
-		handler.EnqueueRequestsFromMapFunc(r.requeueEventTriggerForClusterProfile),
-		ClusterProfilePredicates(mgr.GetLogger().WithValues("predicate", "clusterrofilepredicate")),
-	)
+	c, err := b.Build(r)
 	if err != nil {
 		return nil, errors.Wrap(err, "error creating controller")
 	}
-	*/
 
 	if r.EventReportMode == CollectFromManagementCluster {
-		go collectEventReports(mgr.GetConfig(), mgr.GetClient(), mgr.GetScheme(), r.ShardKey,
-			r.CapiOnboardAnnotation, getVersion(), mgr.GetLogger())
+		err = mgr.Add(&eventReportCollector{
+			config:                mgr.GetConfig(),
+			client:                mgr.GetClient(),
+			scheme:                mgr.GetScheme(),
+			shardKey:              r.ShardKey,
+			capiOnboardAnnotation: r.CapiOnboardAnnotation,
+			version:               getVersion(),
+			logger:                mgr.GetLogger(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error adding EventReport collector")
+		}
 	}
 
 	return c, nil
@@ -565,7 +640,7 @@ func (r *EventTriggerReconciler) updateEventSourceMaps(eventTriggerScope *scope.
 	currentReferences.Insert(&corev1.ObjectReference{
 		APIVersion: libsveltosv1beta1.GroupVersion.String(), // the only resources that can be referenced is EventSource
 		Kind:       libsveltosv1beta1.EventSourceKind,
-		Name:       eventTriggerScope.EventTrigger.Spec.EventSourceName,
+		Name:       getEventSourceName(eventTriggerScope.EventTrigger),
 	})
 
 	r.Mux.Lock()
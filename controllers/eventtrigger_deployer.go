@@ -17,7 +17,6 @@ limitations under the License.
 package controllers
 
 import (
-	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/base64"
@@ -25,11 +24,7 @@ import (
 	"fmt"
 	"reflect"
 	"strings"
-	"text/template"
 
-	sourcev1 "github.com/fluxcd/source-controller/api/v1"
-	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
-	"github.com/gdexlab/go-render/render"
 	"github.com/go-logr/logr"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -38,6 +33,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
@@ -46,6 +42,7 @@ import (
 	"sigs.k8s.io/cluster-api/util"
 	"sigs.k8s.io/cluster-api/util/annotations"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	"github.com/projectsveltos/event-manager/api/v1beta1"
@@ -75,6 +72,8 @@ const (
 	clusterTypeLabel                 = "eventtrigger.lib.projectsveltos.io/clustertype"
 	cloudEventSourceLabel            = "eventtrigger.lib.projectsveltos.io/cesource"
 	cloudEventSubjectLabel           = "eventtrigger.lib.projectsveltos.io/cesubject"
+	resourceNameLabel                = "eventtrigger.lib.projectsveltos.io/resourcename"
+	resourceNamespaceLabel           = "eventtrigger.lib.projectsveltos.io/resourcenamespace"
 	generatorLabel                   = "eventtrigger.lib.projectsveltos.io/fromgenerator"
 	referencedResourceNamespaceLabel = "eventtrigger.lib.projectsveltos.io/refnamespace"
 	referencedResourceNameLabel      = "eventtrigger.lib.projectsveltos.io/refname"
@@ -118,6 +117,8 @@ func (r *EventTriggerReconciler) deployEventTrigger(ctx context.Context, eScope
 	var errorSeen error
 	allProcessed := true
 
+	independent := newClusterIndependentResources(resource)
+
 	for i := range resource.Status.ClusterInfo {
 		c := &resource.Status.ClusterInfo[i]
 
@@ -144,7 +145,7 @@ func (r *EventTriggerReconciler) deployEventTrigger(ctx context.Context, eScope
 				clusterInfo.Hash = []byte(str)
 			}
 		} else {
-			clusterInfo, err = r.processEventTrigger(ctx, eScope, &c.Cluster, f, logger)
+			clusterInfo, err = r.processEventTrigger(ctx, eScope, independent, &c.Cluster, f, logger)
 			if err != nil {
 				errorSeen = err
 			}
@@ -294,57 +295,36 @@ func undeployEventTriggerResourcesFromCluster(ctx context.Context, c client.Clie
 
 	logger.V(logs.LogDebug).Info("Clearing instantiated ClusterProfile/ConfigMap/Secret instances")
 	return removeInstantiatedResources(ctx, c, clusterNamespace, clusterName, clusterType, resource,
-		nil, nil, nil, logger)
+		nil, nil, nil, nil, logger)
 }
 
-// eventTriggerHash returns the EventTrigger hash
+// eventTriggerHash returns the EventTrigger hash. Cluster independent inputs and referenced
+// resources are hashed only when changed (see digestCache).
 func eventTriggerHash(ctx context.Context, c client.Client,
 	e *v1beta1.EventTrigger, cluster *corev1.ObjectReference, logger logr.Logger) ([]byte, error) {
 
-	resources, err := fetchReferencedResources(ctx, c, e, cluster, logger)
-	if err != nil {
-		return nil, err
-	}
-
-	config := getVersion()
-	config += render.AsCode(e.Spec)
-	config += render.AsCode(e.Labels)
+	return computeEventTriggerHash(ctx, c, e, newClusterIndependentResources(e), cluster, logger)
+}
 
-	for i := range resources {
-		switch r := resources[i].(type) {
-		case *corev1.ConfigMap:
-			config += render.AsCode(r.Data)
-		case *corev1.Secret:
-			config += render.AsCode(r.Data)
-		case *sourcev1b2.Bucket:
-			config += render.AsCode(r.Status.Artifact)
-		case *sourcev1b2.OCIRepository:
-			config += render.AsCode(r.Status.Artifact)
-		case *sourcev1.GitRepository:
-			config += render.AsCode(r.Status.Artifact)
-		case *libsveltosv1beta1.EventSource:
-			config += render.AsCode(r.Spec)
-		case *libsveltosv1beta1.EventReport:
-			config += render.AsCode(r.Spec)
-		default:
-			panic(1)
-		}
-	}
+// computeEventTriggerHash returns the EventTrigger hash. Referenced resources not depending on
+// the cluster are fetched by independent, so only once when computing the hash for many clusters.
+func computeEventTriggerHash(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	independent *clusterIndependentResources, cluster *corev1.ObjectReference, logger logr.Logger) ([]byte, error) {
 
-	// When in agentless mode, EventSources instances are not copued to managed cluster anymore.
-	// This addition ensures the EvenTrigger is redeployed due to the change in deployment location.
-	if getAgentInMgmtCluster() {
-		config += ("agentless")
+	resources, err := fetchReferencedResourcesForCluster(ctx, c, independent, cluster, logger)
+	if err != nil {
+		return nil, err
 	}
 
 	h := sha256.New()
-	h.Write([]byte(config))
+	h.Write(getEventTriggerDigest(e))
+	writeReferencedResources(h, resources)
 	return h.Sum(nil), nil
 }
 
 // processEventTrigger detects whether it is needed to deploy EventBasedAddon resources in current passed cluster.
 func (r *EventTriggerReconciler) processEventTrigger(ctx context.Context, eScope *scope.EventTriggerScope,
-	cluster *corev1.ObjectReference, f feature, logger logr.Logger,
+	independent *clusterIndependentResources, cluster *corev1.ObjectReference, f feature, logger logr.Logger,
 ) (*libsveltosv1beta1.ClusterInfo, error) {
 
 	if !isClusterStillMatching(eScope, cluster) {
@@ -354,8 +334,19 @@ func (r *EventTriggerReconciler) processEventTrigger(ctx context.Context, eScope
 	resource := eScope.EventTrigger
 
 	// Get EventTrigger Spec hash (at this very precise moment)
-	currentHash, err := eventTriggerHash(ctx, r.Client, resource, cluster, logger)
-	if err != nil {
+	currentHash, err := computeEventTriggerHash(ctx, r.Client, resource, independent, cluster, logger)
+	if err != nil {
+		if isTenantAccessDenied(err) || isTemplatePolicyViolation(err) {
+			// Nothing is instantiated for this cluster till tenant is granted access
+			// or template is fixed
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to fetch referenced resources: %v", err))
+			errorMessage := err.Error()
+			return &libsveltosv1beta1.ClusterInfo{
+				Cluster:        *cluster,
+				Status:         libsveltosv1beta1.SveltosStatusFailed,
+				FailureMessage: &errorMessage,
+			}, err
+		}
 		return nil, err
 	}
 
@@ -846,7 +837,7 @@ func deployEventSource(ctx context.Context, c client.Client,
 	eventTrigger *v1beta1.EventTrigger, options deployer.Options, isPullMode bool, logger logr.Logger) error {
 
 	currentEventSource, err := fetchEventSource(ctx, c, clusterNamespace, clusterName,
-		eventTrigger.Spec.EventSourceName, clusterType, logger)
+		getEventSourceName(eventTrigger), clusterType, logger)
 	if err != nil {
 		return err
 	}
@@ -870,8 +861,7 @@ func deployEventSource(ctx context.Context, c client.Client,
 
 	// If sveltos-agent is deployed to the managed cluster, deply EventSource there
 	var remoteClient client.Client
-	remoteClient, err = clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName,
-		"", "", clusterType, logger)
+	remoteClient, err = getRemoteClient(ctx, c, clusterNamespace, clusterName, clusterType, logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get managed cluster client: %v", err))
 		return err
@@ -1031,7 +1021,7 @@ func removeStaleEventSources(ctx context.Context, c client.Client,
 		if !removeAll && eventTrigger.DeletionTimestamp.IsZero() {
 			// If removeAll is false and eventTrigger still exists, remove all entries but the one pointing
 			// to current referenced EventSource
-			leaveEntry = eventTrigger.Spec.EventSourceName
+			leaveEntry = getEventSourceName(eventTrigger)
 		}
 
 		return removeEventSourceFromConfigMap(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger,
@@ -1058,8 +1048,7 @@ func proceedRemovingStaleEventSources(ctx context.Context, c client.Client,
 	clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
 	eventTrigger *v1beta1.EventTrigger, removeAll bool, logger logr.Logger) error {
 
-	remoteClient, err := clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName,
-		"", "", clusterType, logger)
+	remoteClient, err := getRemoteClient(ctx, c, clusterNamespace, clusterName, clusterType, logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get managed cluster client: %v", err))
 		return err
@@ -1079,7 +1068,7 @@ func proceedRemovingStaleEventSources(ctx context.Context, c client.Client,
 		// removeAll indicates all EventSources deployed by this EventTrigger on this cluster
 		// need to be removed (cluster is no longer a match)
 		if !removeAll && eventTrigger.DeletionTimestamp.IsZero() &&
-			es.Name == eventTrigger.Spec.EventSourceName {
+			es.Name == getEventSourceName(eventTrigger) {
 			// eventTrigger still exists and eventSource is still referenced
 			continue
 		}
@@ -1155,9 +1144,30 @@ func updateClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
 	logger logr.Logger) error {
 
-	var err error
-	// If no resource is currently matching, clear all
-	if !er.DeletionTimestamp.IsZero() || !hasMatchingResources(er) {
+	sourceCluster := getClusterRef(clusterNamespace, clusterName, clusterType)
+
+	// Unresolved destinations are recorded again while instantiating
+	clearUnresolvedDestinations(eventTrigger.Name, sourceCluster, er.Name)
+	pruneBlockedEvents(eventTrigger, sourceCluster, er)
+
+	// Nothing is instantiated for events in a cluster tenant admin has no access to
+	blocked := false
+	err := validateTenantSourceCluster(ctx, c, eventTrigger, sourceCluster)
+	if err != nil {
+		if !isTenantClusterViolation(err) {
+			return err
+		}
+		if er.DeletionTimestamp.IsZero() && hasMatchingResources(er) {
+			reportBlockedEvent(ctx, c, eventTrigger.Name,
+				getEventCause(clusterNamespace, clusterName, clusterType, er, nil), err, logger)
+		}
+		blocked = true
+	} else {
+		unblockSourceCluster(eventTrigger.Name, sourceCluster)
+	}
+
+	// If no resource is currently matching or source cluster is blocked, clear all
+	if !er.DeletionTimestamp.IsZero() || !hasMatchingResources(er) || blocked {
 		// ClusterProfiles created because of CloudEvents are removed when CloudEventAction is set to Delete.
 		// Fetch all ClusterProfiles created because of CloudEvents by this eventTrigger and append to list
 		// of ClusterProfiles that are not stale
@@ -1168,11 +1178,12 @@ func updateClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 			return err
 		}
 		return removeInstantiatedResources(ctx, c, clusterNamespace, clusterName, clusterType,
-			eventTrigger, er, clusterProfiles, nil, logger)
+			eventTrigger, er, clusterProfiles, nil, nil, logger)
 	}
 
 	var clusterProfiles []*configv1beta1.ClusterProfile
 	var fromGenerators []libsveltosv1beta1.PolicyRef
+	var fromResourceGenerators []corev1.ObjectReference
 
 	// Resources (ClusterProfiles, ConfigMaps and Secrets) created because of CloudEvent contains the
 	// cloudEventSubjectLabel and cloudEventSourceLabel. This means a CloudEvent is uniquely identified
@@ -1194,6 +1205,13 @@ func updateClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate from generators: %v", err))
 			return err
 		}
+		// Instantiate resources from ResourceGenerator
+		fromResourceGenerators, err = instantiateFromResourceGeneratorsPerResource(ctx, c, eventTrigger, er,
+			clusterNamespace, clusterName, clusterType, logger)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate from resource generators: %v", err))
+			return err
+		}
 	} else {
 		logger.V(logs.LogDebug).Info("updating one clusterProfile for all resources")
 		clusterProfiles, err = instantiateOneClusterProfilePerAllResource(ctx, c, clusterNamespace, clusterName,
@@ -1209,6 +1227,12 @@ func updateClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate from generators: %v", err))
 			return err
 		}
+		fromResourceGenerators, err = instantiateFromResourceGeneratorsPerAllResource(ctx, c, eventTrigger, er,
+			clusterNamespace, clusterName, clusterType, logger)
+		if err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate from resource generators: %v", err))
+			return err
+		}
 	}
 
 	// ClusterProfiles created because of CloudEvents are removed when CloudEventAction is set to Delete.
@@ -1220,10 +1244,17 @@ func updateClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 		return err
 	}
 
-	// Remove stale ClusterProfiles/ConfigMaps/Secrets, i.e, resources previously created by this EventTrigger
-	// instance for this cluster but currently not needed anymore
+	// Instantiated ConfigMaps/Secrets referenced by a ClusterProfile are owned by it
+	err = addClusterProfileOwnerReferences(ctx, c, eventTrigger, clusterProfiles, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to set ClusterProfile as owner: %v", err))
+		return err
+	}
+
+	// Remove stale ClusterProfiles/ConfigMaps/Secrets/resources, i.e, resources previously created by this
+	// EventTrigger instance for this cluster but currently not needed anymore
 	return removeInstantiatedResources(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger, er,
-		clusterProfiles, fromGenerators, logger)
+		clusterProfiles, fromGenerators, fromResourceGenerators, logger)
 }
 
 // instantiateOneClusterProfilePerResource instantiate a ClusterProfile for each resource/cloudEvent currently matching
@@ -1312,33 +1343,35 @@ func instantiateClusterProfileForResource(ctx context.Context, c client.Client,
 		if *instantiatedCloudEventAction == v1beta1.CloudEventActionDelete {
 			// Resources created because of a cloudEvent are ONLY removed when same (same subject/source) cloudEvent
 			// is received and EventTrigger.Spec.CloudEventAction is set to delete.
-			return nil, deleteClusterProfile(ctx, c, clusterProfile, logger)
+			return nil, deleteClusterProfile(ctx, c, eventTrigger.Name, clusterProfile,
+				getEventCause(clusterNamespace, clusterName, clusterType, er, object), logger)
 		}
 	}
 
+	cause := getEventCause(clusterNamespace, clusterName, clusterType, er, object)
 	clusterProfileSpec, err := instantiateClusterProfileSpecForResource(ctx, c, clusterNamespace, clusterName,
-		clusterType, eventTrigger, labels, object, logger)
+		clusterType, eventTrigger, labels, object, cause, logger)
 	if err != nil {
+		if isTenantClusterViolation(err) {
+			reportBlockedEvent(ctx, c, eventTrigger.Name, cause, err, logger)
+			return nil, nil
+		}
+		if isUnresolvedDestination(err) {
+			logger.V(logs.LogInfo).Info(err.Error())
+			return getExistingClusterProfile(ctx, c, clusterProfileName)
+		}
 		return nil, err
 	}
 	clusterProfile.Spec = *clusterProfileSpec
+	unblockEvent(eventTrigger.Name, cause)
 
-	addTypeInformationToObject(mgmtClusterSchema, clusterProfile)
-
-	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig,
-		clusterProfile.GetObjectKind().GroupVersionKind(), clusterProfile.GetNamespace())
-	if err != nil {
-		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get dynamic client: %v", err))
-		return nil, err
-	}
-
-	return clusterProfile, updateResource(ctx, dr, clusterProfile, logger)
+	return applyClusterProfile(ctx, c, eventTrigger, clusterProfile, cause, logger)
 }
 
 // instantiateClusterProfileSpecForResource creates one ClusterProfile.Spec per event
 func instantiateClusterProfileSpecForResource(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
 	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, labels map[string]string,
-	object *currentObject, logger logr.Logger) (*configv1beta1.Spec, error) {
+	object *currentObject, cause *eventCause, logger logr.Logger) (*configv1beta1.Spec, error) {
 
 	clusterProfileSpec := *getClusterProfileSpec(eventTrigger)
 
@@ -1355,6 +1388,17 @@ func instantiateClusterProfileSpecForResource(ctx context.Context, c client.Clie
 		return nil, err
 	}
 
+	err = validateTenantClusters(ctx, c, eventTrigger, getClusterRef(clusterNamespace, clusterName, clusterType),
+		&clusterProfileSpec, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolveDestinationClusters(ctx, c, eventTrigger, cause, &clusterProfileSpec, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	instantiateHelmChartsWithResource, err := instantiateHelmChartsWithResource(ctx, c, eventTrigger,
 		clusterNamespace, templateName, eventTrigger.Spec.HelmCharts, object, labels, logger)
 	if err != nil {
@@ -1416,28 +1460,38 @@ func instantiateOneClusterProfilePerAllResource(ctx context.Context, c client.Cl
 
 	clusterProfile := getNonInstantiatedClusterProfile(eventTrigger, clusterProfileName, labels)
 
+	cause := getEventCause(clusterNamespace, clusterName, clusterType, eventReport, nil)
 	clusterProfileSpec, err := instantiateClusterProfileSpecPerAllResource(ctx, c, clusterNamespace,
-		clusterName, clusterType, eventTrigger, labels, objects, logger)
+		clusterName, clusterType, eventTrigger, labels, objects, cause, logger)
 	if err != nil {
+		if isTenantClusterViolation(err) {
+			reportBlockedEvent(ctx, c, eventTrigger.Name, cause, err, logger)
+			return nil, nil
+		}
+		if isUnresolvedDestination(err) {
+			logger.V(logs.LogInfo).Info(err.Error())
+			existing, err := getExistingClusterProfile(ctx, c, clusterProfileName)
+			if err != nil || existing == nil {
+				return nil, err
+			}
+			return []*configv1beta1.ClusterProfile{existing}, nil
+		}
 		return nil, err
 	}
 	clusterProfile.Spec = *clusterProfileSpec
+	unblockEvent(eventTrigger.Name, cause)
 
-	addTypeInformationToObject(mgmtClusterSchema, clusterProfile)
-
-	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig,
-		clusterProfile.GetObjectKind().GroupVersionKind(), clusterProfile.GetNamespace())
+	clusterProfile, err = applyClusterProfile(ctx, c, eventTrigger, clusterProfile, cause, logger)
 	if err != nil {
-		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get dynamic client: %v", err))
 		return nil, err
 	}
 
-	return []*configv1beta1.ClusterProfile{clusterProfile}, updateResource(ctx, dr, clusterProfile, logger)
+	return []*configv1beta1.ClusterProfile{clusterProfile}, nil
 }
 
 func instantiateClusterProfileSpecPerAllResource(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
 	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, labels map[string]string,
-	objects *currentObjects, logger logr.Logger) (*configv1beta1.Spec, error) {
+	objects *currentObjects, cause *eventCause, logger logr.Logger) (*configv1beta1.Spec, error) {
 
 	clusterProfileSpec := getClusterProfileSpec(eventTrigger)
 
@@ -1454,6 +1508,17 @@ func instantiateClusterProfileSpecPerAllResource(ctx context.Context, c client.C
 		return nil, err
 	}
 
+	err = validateTenantClusters(ctx, c, eventTrigger, getClusterRef(clusterNamespace, clusterName, clusterType),
+		clusterProfileSpec, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = resolveDestinationClusters(ctx, c, eventTrigger, cause, clusterProfileSpec, logger)
+	if err != nil {
+		return nil, err
+	}
+
 	instantiateHelmChartsWithResources, err := instantiateHelmChartsWithAllResources(ctx, c, eventTrigger,
 		clusterNamespace, templateName, eventTrigger.Spec.HelmCharts, objects, labels, logger)
 	if err != nil {
@@ -1484,7 +1549,8 @@ func setTemplateResourceRefs(clusterProfileSpec *configv1beta1.Spec,
 	logger logr.Logger) error {
 
 	templateResourceRefs, err := instantiateTemplateResourceRefs(templateName, clusterContent, data,
-		eventTrigger.Spec.TemplateResourceRefs, funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations))
+		eventTrigger.Spec.TemplateResourceRefs, funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations),
+		getTemplatePolicy(eventTrigger))
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate TemplateResourceRefs: %v", err))
 		return err
@@ -1514,8 +1580,13 @@ func setClusterSelector(clusterProfileSpec *configv1beta1.Spec, clusterNamespace
 	useSameCluster := true
 	if !reflect.DeepEqual(eventTrigger.Spec.DestinationClusterSelector, libsveltosv1beta1.Selector{}) {
 		useSameCluster = false
+		destinationClusterSelector, err := getDestinationClusterSelector(clusterNamespace, clusterName,
+			eventTrigger, data, logger)
+		if err != nil {
+			return err
+		}
 		clusterProfileSpec.ClusterRefs = nil
-		clusterProfileSpec.ClusterSelector = eventTrigger.Spec.DestinationClusterSelector
+		clusterProfileSpec.ClusterSelector = *destinationClusterSelector
 	}
 
 	if eventTrigger.Spec.DestinationCluster != nil {
@@ -1527,7 +1598,7 @@ func setClusterSelector(clusterProfileSpec *configv1beta1.Spec, clusterNamespace
 			return err
 		}
 
-		instantiated, err := instantiateSection(templateName, raw, data, false, logger)
+		instantiated, err := instantiateSection(templateName, raw, data, false, getTemplatePolicy(eventTrigger), logger)
 		if err != nil {
 			return err
 		}
@@ -1540,6 +1611,26 @@ func setClusterSelector(clusterProfileSpec *configv1beta1.Spec, clusterNamespace
 		clusterProfileSpec.ClusterRefs = []corev1.ObjectReference{destinationCluster}
 	}
 
+	if eventTrigger.Spec.DestinationClusters != "" {
+		useSameCluster = false
+		destinationClusters, err := getDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, data, logger)
+		if err != nil {
+			return err
+		}
+		clusterProfileSpec.ClusterRefs = destinationClusters
+	}
+
+	if len(eventTrigger.Spec.DestinationClusterSetRefs) != 0 {
+		useSameCluster = false
+		destinationClusterSetRefs, err := getDestinationClusterSetRefs(clusterNamespace, clusterName,
+			eventTrigger, data, logger)
+		if err != nil {
+			return err
+		}
+		clusterProfileSpec.SetRefs = destinationClusterSetRefs
+	}
+
 	if useSameCluster {
 		clusterProfileSpec.ClusterRefs = []corev1.ObjectReference{*getClusterRef(clusterNamespace, clusterName, clusterType)}
 		clusterProfileSpec.ClusterSelector = libsveltosv1beta1.Selector{}
@@ -1617,23 +1708,157 @@ func getCloudEvents(eventReport *libsveltosv1beta1.EventReport, logger logr.Logg
 	return result, nil
 }
 
+// getDestinationClusterSelector instantiates eventTrigger.Spec.DestinationClusterSelector. MatchLabels
+// values and MatchExpressions keys and values are instantiated.
+func getDestinationClusterSelector(clusterNamespace, clusterName string, eventTrigger *v1beta1.EventTrigger,
+	data any, logger logr.Logger) (*libsveltosv1beta1.Selector, error) {
+
+	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
+	useTxtFuncMap := funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations)
+	policy := getTemplatePolicy(eventTrigger)
+
+	instantiate := func(value string) (string, error) {
+		instantiated, err := instantiateSection(templateName, []byte(value), data, useTxtFuncMap, policy, logger)
+		if err != nil {
+			return "", err
+		}
+		return string(instantiated), nil
+	}
+
+	selector := eventTrigger.Spec.DestinationClusterSelector.DeepCopy()
+	for k, v := range selector.MatchLabels {
+		instantiated, err := instantiate(v)
+		if err != nil {
+			return nil, err
+		}
+		selector.MatchLabels[k] = instantiated
+	}
+
+	for i := range selector.MatchExpressions {
+		expression := &selector.MatchExpressions[i]
+		instantiated, err := instantiate(expression.Key)
+		if err != nil {
+			return nil, err
+		}
+		expression.Key = instantiated
+		for j := range expression.Values {
+			instantiated, err := instantiate(expression.Values[j])
+			if err != nil {
+				return nil, err
+			}
+			expression.Values[j] = instantiated
+		}
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("instantiated destinationClusterSelector is not valid: %v", err))
+		return nil, err
+	}
+
+	return selector, nil
+}
+
+// getDestinationClusterSetRefs instantiates eventTrigger.Spec.DestinationClusterSetRefs. Entries
+// instantiated to an empty string are ignored.
+func getDestinationClusterSetRefs(clusterNamespace, clusterName string, eventTrigger *v1beta1.EventTrigger,
+	data any, logger logr.Logger) ([]string, error) {
+
+	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
+	useTxtFuncMap := funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations)
+	policy := getTemplatePolicy(eventTrigger)
+
+	result := make([]string, 0, len(eventTrigger.Spec.DestinationClusterSetRefs))
+	added := make(map[string]bool)
+	for i := range eventTrigger.Spec.DestinationClusterSetRefs {
+		instantiated, err := instantiateSection(templateName, []byte(eventTrigger.Spec.DestinationClusterSetRefs[i]),
+			data, useTxtFuncMap, policy, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		clusterSetName := strings.TrimSpace(string(instantiated))
+		if clusterSetName == "" || added[clusterSetName] {
+			continue
+		}
+		added[clusterSetName] = true
+		result = append(result, clusterSetName)
+	}
+
+	return result, nil
+}
+
+// getDestinationClusters instantiates eventTrigger.Spec.DestinationClusters. Returned list can be empty.
+// Source cluster is added if eventTrigger.Spec.IncludeSourceCluster is set.
+func getDestinationClusters(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
+	eventTrigger *v1beta1.EventTrigger, data any, logger logr.Logger) ([]corev1.ObjectReference, error) {
+
+	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
+	instantiated, err := instantiateSection(templateName, []byte(eventTrigger.Spec.DestinationClusters), data,
+		funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations), getTemplatePolicy(eventTrigger), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var destinationClusters []corev1.ObjectReference
+	if err := yaml.Unmarshal(instantiated, &destinationClusters); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("instantiated destinationClusters is not a list of clusters: %v", err))
+		return nil, err
+	}
+
+	if eventTrigger.Spec.IncludeSourceCluster {
+		destinationClusters = append(destinationClusters, *getClusterRef(clusterNamespace, clusterName, clusterType))
+	}
+
+	result := make([]corev1.ObjectReference, 0, len(destinationClusters))
+	added := make(map[string]bool)
+	for i := range destinationClusters {
+		cluster := &destinationClusters[i]
+		if cluster.Name == "" || cluster.Namespace == "" || cluster.Kind == "" {
+			return nil, fmt.Errorf("instantiated destinationClusters entry %d must have kind, namespace and name", i)
+		}
+		var gv schema.GroupVersion
+		switch cluster.Kind {
+		case libsveltosv1beta1.SveltosClusterKind:
+			gv = libsveltosv1beta1.GroupVersion
+		case clusterv1.ClusterKind:
+			gv = clusterv1.GroupVersion
+		default:
+			return nil, fmt.Errorf("instantiated destinationClusters entry %d has unsupported kind %q (must be %s or %s)",
+				i, cluster.Kind, clusterv1.ClusterKind, libsveltosv1beta1.SveltosClusterKind)
+		}
+		if cluster.APIVersion == "" {
+			cluster.APIVersion = gv.String()
+		} else if cluster.GroupVersionKind().Group != gv.Group {
+			return nil, fmt.Errorf("instantiated destinationClusters entry %d has apiVersion %q not matching kind %s",
+				i, cluster.APIVersion, cluster.Kind)
+		}
+		key := getClusterKey(cluster)
+		if added[key] {
+			continue
+		}
+		added[key] = true
+		result = append(result, *cluster)
+	}
+
+	return result, nil
+}
+
 func instantiateSection(templateName string, toBeInstantiated []byte, data any,
-	useTxtFuncMap bool, logger logr.Logger) ([]byte, error) {
+	useTxtFuncMap bool, policy *templatePolicy, logger logr.Logger) ([]byte, error) {
 
-	tmpl, err := template.New(templateName).Option("missingkey=error").Funcs(
-		funcmap.SveltosFuncMap(useTxtFuncMap)).Parse(string(toBeInstantiated))
+	tmpl, err := newTemplate(templateName, useTxtFuncMap, policy).Parse(string(toBeInstantiated))
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse template: %v", err))
 		return nil, err
 	}
 
-	var buffer bytes.Buffer
-	if err = tmpl.Execute(&buffer, data); err != nil {
+	instantiated, err := executeTemplate(tmpl, data, policy)
+	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to execute template: %v", err))
 		return nil, err
 	}
 
-	return buffer.Bytes(), nil
+	return instantiated, nil
 }
 
 func instantiateHelmCharts(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
@@ -1647,7 +1872,7 @@ func instantiateHelmCharts(ctx context.Context, c client.Client, e *v1beta1.Even
 	}
 
 	instantiatedData, err := instantiateSection(templateName, helmChartJson, data,
-		funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to execute template: %v", err))
 		return nil, err
@@ -1682,7 +1907,7 @@ func instantiateKustomizationRefs(ctx context.Context, c client.Client, e *v1bet
 	}
 
 	instantiatedData, err := instantiateSection(templateName, kustomizationRefsJson, data,
-		funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to execute template: %v", err))
 		return nil, err
@@ -1718,7 +1943,7 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 			namespace = clusterNamespace
 		} else {
 			instantiantedNamespace, err := instantiateSection(templateName, []byte(ref.Namespace), data,
-				funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+				funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name: %v", err))
 				return err
@@ -1727,7 +1952,7 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 		}
 
 		name, err := instantiateSection(templateName, []byte(ref.Name), data,
-			funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 		if err != nil {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate name: %v", err))
 			return err
@@ -1735,10 +1960,10 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 
 		var resource client.Object
 		if ref.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-			resource, err = getConfigMap(ctx, c,
+			resource, err = getConfigMap(ctx, c, e,
 				types.NamespacedName{Namespace: namespace, Name: string(name)})
 		} else {
-			resource, err = getSecret(ctx, c,
+			resource, err = getSecret(ctx, c, e,
 				types.NamespacedName{Namespace: namespace, Name: string(name)})
 		}
 
@@ -1753,13 +1978,17 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 		}
 
 		var info *types.NamespacedName
-		if _, ok := resource.GetAnnotations()[v1beta1.InstantiateAnnotation]; !ok {
+		if !requiresInstantiation(resource) {
 			// referenced ConfigMap/Secret is not a template. So there is no
 			// need to instantiate a new one. Generated ClusterProfile can directly
 			// reference this one
 			info = &types.NamespacedName{Namespace: resource.GetNamespace(), Name: resource.GetName()}
 		} else {
-			name, err := getResourceName(ctx, c, resource, labels)
+			instantiatedNamespace, err := getInstantiatedNamespace(e, "", templateName, data, logger)
+			if err != nil {
+				return err
+			}
+			name, err := getResourceName(ctx, c, resource, instantiatedNamespace, labels)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get %s name: %v", resource.GetObjectKind(), err))
 				return err
@@ -1768,13 +1997,17 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 			// information from the resources in the managed cluster that generated the event.
 			// Generate then a new ConfigMap/Secret. The autocreated ClusterProfile will reference
 			// this new resource.
-			info, err = instantiateReferencedPolicy(ctx, e, resource, templateName, name, data, labels, logger)
+			info, err = instantiateReferencedPolicy(ctx, c, e, resource, templateName, instantiatedNamespace, name,
+				data, labels, logger)
 			if err != nil {
 				msg := fmt.Sprintf("failed to instantiate content for ValuesFrom: %s/%s",
 					resource.GetNamespace(), resource.GetName())
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("%s. Error: %v", msg, err))
 				return errors.Wrapf(err, "%s", msg)
 			}
+			if isSopsEncrypted(resource) {
+				ref.Kind = string(libsveltosv1beta1.SecretReferencedResourceKind)
+			}
 		}
 
 		ref.Name = info.Name
@@ -1785,7 +2018,7 @@ func instantiateValuesFrom(ctx context.Context, c client.Client, e *v1beta1.Even
 }
 
 func instantiateDataSection(templateName string, content map[string]string, data any,
-	useTxtFuncMap bool, logger logr.Logger) (map[string]string, error) {
+	useTxtFuncMap bool, policy *templatePolicy, logger logr.Logger) (map[string]string, error) {
 
 	contentJson, err := json.Marshal(content)
 	if err != nil {
@@ -1793,21 +2026,20 @@ func instantiateDataSection(templateName string, content map[string]string, data
 		return nil, err
 	}
 
-	tmpl, err := template.New(templateName).Option("missingkey=error").Funcs(
-		funcmap.SveltosFuncMap(useTxtFuncMap)).Parse(string(contentJson))
+	tmpl, err := newTemplate(templateName, useTxtFuncMap, policy).Parse(string(contentJson))
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to parse content: %v", err))
 		return nil, err
 	}
 
-	var buffer bytes.Buffer
-	if err = tmpl.Execute(&buffer, data); err != nil {
+	instantiated, err := executeTemplate(tmpl, data, policy)
+	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to execute content: %v", err))
 		return nil, err
 	}
 
 	instantiatedContent := make(map[string]string)
-	err = json.Unmarshal(buffer.Bytes(), &instantiatedContent)
+	err = json.Unmarshal(instantiated, &instantiatedContent)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to unmarshal content: %v", err))
 		return nil, err
@@ -1818,40 +2050,36 @@ func instantiateDataSection(templateName string, content map[string]string, data
 
 func instantiateTemplateResourceRefs(templateName string,
 	clusterContent map[string]interface{}, data any, templateResourceRefs []configv1beta1.TemplateResourceRef,
-	useTxtFuncMap bool) ([]configv1beta1.TemplateResourceRef, error) {
+	useTxtFuncMap bool, policy *templatePolicy) ([]configv1beta1.TemplateResourceRef, error) {
 
 	var uCluster unstructured.Unstructured
 	uCluster.SetUnstructuredContent(clusterContent)
 
 	instantiated := make([]configv1beta1.TemplateResourceRef, len(templateResourceRefs))
 	for i := range templateResourceRefs {
-		tmpl, err := template.New(templateName).Option("missingkey=error").Funcs(
-			funcmap.SveltosFuncMap(useTxtFuncMap)).Parse(templateResourceRefs[i].Resource.Name)
+		tmpl, err := newTemplate(templateName, useTxtFuncMap, policy).Parse(templateResourceRefs[i].Resource.Name)
 		if err != nil {
 			return nil, err
 		}
 
-		var nameBuffer bytes.Buffer
-		err = tmpl.Execute(&nameBuffer, data)
+		name, err := executeTemplate(tmpl, data, policy)
 		if err != nil {
 			return nil, err
 		}
 
-		tmpl, err = template.New(templateName).Option("missingkey=error").Funcs(
-			funcmap.SveltosFuncMap(useTxtFuncMap)).Parse(templateResourceRefs[i].Resource.Namespace)
+		tmpl, err = newTemplate(templateName, useTxtFuncMap, policy).Parse(templateResourceRefs[i].Resource.Namespace)
 		if err != nil {
 			return nil, err
 		}
 
-		var namespaceBuffer bytes.Buffer
-		err = tmpl.Execute(&namespaceBuffer, data)
+		namespace, err := executeTemplate(tmpl, data, policy)
 		if err != nil {
 			return nil, err
 		}
 
 		instantiated[i] = templateResourceRefs[i]
-		instantiated[i].Resource.Namespace = namespaceBuffer.String()
-		instantiated[i].Resource.Name = nameBuffer.String()
+		instantiated[i].Resource.Namespace = string(namespace)
+		instantiated[i].Resource.Name = string(name)
 	}
 
 	return instantiated, nil
@@ -1935,13 +2163,17 @@ func instantiateResources(ctx context.Context, c client.Client, e *v1beta1.Event
 		l.V(logs.LogDebug).Info("process referenced resource")
 		var info *types.NamespacedName
 
-		if _, ok := ref.GetAnnotations()[v1beta1.InstantiateAnnotation]; !ok {
+		if !requiresInstantiation(ref) {
 			// referenced ConfigMap/Secret is not a template. So there is no
 			// need to instantiate a new one. Generated ClusterProfile can directly
 			// reference this one
 			info = &types.NamespacedName{Namespace: ref.GetNamespace(), Name: ref.GetName()}
 		} else {
-			name, err := getResourceName(ctx, c, ref, labels)
+			instantiatedNamespace, err := getInstantiatedNamespace(e, "", templateName, objects, logger)
+			if err != nil {
+				return nil, err
+			}
+			name, err := getResourceName(ctx, c, ref, instantiatedNamespace, labels)
 			if err != nil {
 				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get %s name: %v", ref.GetObjectKind(), err))
 				return nil, err
@@ -1950,10 +2182,12 @@ func instantiateResources(ctx context.Context, c client.Client, e *v1beta1.Event
 			// information from the resources in the managed cluster that generated the event.
 			// Generate then a new ConfigMap/Secret. The autocreated ClusterProfile will reference
 			// this new resource.
-			info, err = instantiateReferencedPolicy(ctx, e, ref, templateName, name, objects, labels, logger)
+			info, err = instantiateReferencedPolicy(ctx, c, e, ref, templateName, instantiatedNamespace, name,
+				objects, labels, logger)
 			if err != nil {
 				return nil, err
 			}
+			kind = getInstantiatedKind(ref)
 		}
 
 		result = append(result, configv1beta1.PolicyRef{
@@ -1965,17 +2199,35 @@ func instantiateResources(ctx context.Context, c client.Client, e *v1beta1.Event
 	return result, nil
 }
 
-func instantiateReferencedPolicy(ctx context.Context, e *v1beta1.EventTrigger, ref client.Object,
-	templateName, name string, objects any, labels map[string]string, logger logr.Logger,
+func instantiateReferencedPolicy(ctx context.Context, c client.Client, e *v1beta1.EventTrigger, ref client.Object,
+	templateName, namespace, name string, objects any, labels map[string]string, logger logr.Logger,
 ) (*types.NamespacedName, error) {
 
 	l := logger.WithValues("referencedResource",
 		fmt.Sprintf("%s:%s/%s", ref.GetObjectKind(), ref.GetNamespace(), ref.GetName()))
 
+	if err := authorizeInstantiatedNamespace(ctx, c, e, getInstantiatedKind(ref), namespace, name); err != nil {
+		l.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate referenced resource: %v", err))
+		return nil, err
+	}
+
 	content := getDataSection(ref)
+	if isSopsEncrypted(ref) {
+		if err := validateSopsAccess(e); err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to decrypt referenced resource content: %v", err))
+			return nil, err
+		}
+		// Decrypted content only lives in memory and in the generated Secret
+		decryptedContent, err := decryptSopsContent(ctx, c, content)
+		if err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to decrypt referenced resource content: %v", err))
+			return nil, err
+		}
+		content = decryptedContent
+	}
 
 	instantiatedContent, err := instantiateDataSection(templateName, content, objects,
-		funcmap.HasTextTemplateAnnotation(e.Annotations), l)
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), l)
 	if err != nil {
 		l.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiated referenced resource content: %v", err))
 		return nil, err
@@ -1995,11 +2247,12 @@ func instantiateReferencedPolicy(ctx context.Context, e *v1beta1.EventTrigger, r
 	tmpLabels[referencedResourceNameLabel] = ref.GetName()
 
 	var instantiatedObject client.Object
-	if ref.GetObjectKind().GroupVersionKind().Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-		instantiatedObject = generateConfigMap(ref, name, tmpLabels, content)
+	if getInstantiatedKind(ref) == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
+		instantiatedObject = generateConfigMap(ref, namespace, name, tmpLabels, content)
 	} else {
-		instantiatedObject = generateSecret(ref, name, tmpLabels, content)
+		instantiatedObject = generateSecret(ref, namespace, name, tmpLabels, content)
 	}
+	instantiatedObject.SetOwnerReferences(getEventTriggerOwnerReferences(e))
 	addTypeInformationToObject(mgmtClusterSchema, instantiatedObject)
 
 	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig,
@@ -2028,14 +2281,60 @@ func instantiateReferencedPolicy(ctx context.Context, e *v1beta1.EventTrigger, r
 		&corev1.ObjectReference{Kind: v1beta1.EventTriggerKind, Name: e.GetName(), APIVersion: v1beta1.GroupVersion.String()},
 	)
 
-	return &types.NamespacedName{Namespace: ReportNamespace, Name: name}, nil
+	return &types.NamespacedName{Namespace: namespace, Name: name}, nil
+}
+
+// getInstantiatedNamespace returns the namespace where a ConfigMap/Secret instantiated by the EventTrigger
+// must be created. namespaceFormat, if set, takes precedence over EventTrigger.Spec.InstantiatedNamespace.
+// If neither is set, the namespace configured at startup is used.
+func getInstantiatedNamespace(e *v1beta1.EventTrigger, namespaceFormat, templateName string, data any,
+	logger logr.Logger) (string, error) {
+
+	if namespaceFormat == "" {
+		namespaceFormat = e.Spec.InstantiatedNamespace
+	}
+
+	if namespaceFormat == "" {
+		return getDefaultInstantiatedNamespace(), nil
+	}
+
+	namespace, err := instantiateSection(templateName, []byte(namespaceFormat), data,
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate %q: %v", namespaceFormat, err))
+		return "", err
+	}
+
+	if string(namespace) == "" {
+		return getDefaultInstantiatedNamespace(), nil
+	}
+
+	return string(namespace), nil
 }
 
-func generateConfigMap(ref client.Object, name string, labels, content map[string]string) client.Object {
+// authorizeInstantiatedNamespace returns an error if EventTrigger was created by a tenant admin whose
+// service account is not allowed to create and update the instantiated ConfigMap/Secret.
+// Instantiating in the namespace configured in the event-manager is always allowed.
+func authorizeInstantiatedNamespace(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	kind, namespace, name string) error {
+
+	if namespace == getDefaultInstantiatedNamespace() {
+		return nil
+	}
+
+	resource := "secrets"
+	if kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
+		resource = "configmaps"
+	}
+
+	return checkTenantWriteAccess(ctx, c, e, "", resource, namespace, name)
+}
+
+func generateConfigMap(ref client.Object, namespace, name string, labels, content map[string]string) client.Object {
 	return &corev1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
-			Namespace:   ReportNamespace,
+			Namespace:   namespace,
 			Labels:      labels,
 			Annotations: ref.GetAnnotations(), //  libsveltosv1beta1.PolicyTemplateAnnotation might be set
 		},
@@ -2043,18 +2342,29 @@ func generateConfigMap(ref client.Object, name string, labels, content map[strin
 	}
 }
 
-func generateSecret(ref client.Object, name string, labels, content map[string]string) client.Object {
+func generateSecret(ref client.Object, namespace, name string, labels, content map[string]string) client.Object {
 	data := make(map[string][]byte)
 	for key, value := range content {
 		data[key] = []byte(value)
 	}
 
+	// Content is never encrypted once instantiated
+	var annotations map[string]string
+	if ref.GetAnnotations() != nil {
+		annotations = make(map[string]string, len(ref.GetAnnotations()))
+		for k, v := range ref.GetAnnotations() {
+			if k != v1beta1.SopsEncryptedAnnotation {
+				annotations[k] = v
+			}
+		}
+	}
+
 	return &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        name,
-			Namespace:   ReportNamespace,
+			Namespace:   namespace,
 			Labels:      labels,
-			Annotations: ref.GetAnnotations(), //  libsveltosv1beta1.PolicyTemplateAnnotation might be set
+			Annotations: annotations, //  libsveltosv1beta1.PolicyTemplateAnnotation might be set
 		},
 		Data: data,
 		Type: libsveltosv1beta1.ClusterProfileSecretType,
@@ -2123,7 +2433,7 @@ func getClusterProfileName(ctx context.Context, c client.Client, labels map[stri
 	return getInstantiatedObjectName(ctx, c, objects)
 }
 
-func getResourceName(ctx context.Context, c client.Client, ref client.Object,
+func getResourceName(ctx context.Context, c client.Client, ref client.Object, namespace string,
 	labels map[string]string) (name string, err error) {
 
 	// Always append the labels identifying the referenced resource
@@ -2132,23 +2442,27 @@ func getResourceName(ctx context.Context, c client.Client, ref client.Object,
 
 	switch ref.(type) {
 	case *corev1.ConfigMap:
-		name, err = getConfigMapName(ctx, c, labels)
+		if isSopsEncrypted(ref) {
+			// Decrypted content is written to a Secret
+			return getSecretName(ctx, c, namespace, labels)
+		}
+		name, err = getConfigMapName(ctx, c, namespace, labels)
 	case *corev1.Secret:
-		name, err = getSecretName(ctx, c, labels)
+		name, err = getSecretName(ctx, c, namespace, labels)
 	default:
 		panic(1)
 	}
 	return
 }
 
-// getConfigMapName returns the name for a given ConfigMap given the labels such ConfigMap
-// should have. And an error if any occurs.
-func getConfigMapName(ctx context.Context, c client.Client, labels map[string]string,
+// getConfigMapName returns the name for a given ConfigMap given the namespace and the labels
+// such ConfigMap should have. And an error if any occurs.
+func getConfigMapName(ctx context.Context, c client.Client, namespace string, labels map[string]string,
 ) (name string, err error) {
 
 	listOptions := []client.ListOption{
 		client.MatchingLabels(labels),
-		client.InNamespace(ReportNamespace), // all instantianted ConfigMaps are in this namespace
+		client.InNamespace(namespace),
 	}
 
 	configMapList := &corev1.ConfigMapList{}
@@ -2165,14 +2479,14 @@ func getConfigMapName(ctx context.Context, c client.Client, labels map[string]st
 	return getInstantiatedObjectName(ctx, c, objects)
 }
 
-// getSecretName returns the name for a given Secret given the labels such Secret
-// should have. And an error if any occurs.
-func getSecretName(ctx context.Context, c client.Client, labels map[string]string,
+// getSecretName returns the name for a given Secret given the namespace and the labels
+// such Secret should have. And an error if any occurs.
+func getSecretName(ctx context.Context, c client.Client, namespace string, labels map[string]string,
 ) (name string, err error) {
 
 	listOptions := []client.ListOption{
 		client.MatchingLabels(labels),
-		client.InNamespace(ReportNamespace), // all instantianted Secrets are in this namespace
+		client.InNamespace(namespace),
 	}
 
 	secretList := &corev1.SecretList{}
@@ -2248,10 +2562,10 @@ func getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTriggerName
 
 // appendInstantiatedObjectLabelsForResource appends labels specific to a specific resource
 func appendInstantiatedObjectLabelsForResource(labels map[string]string, resourceNamespace, resourceName string) map[string]string {
-	labels["eventtrigger.lib.projectsveltos.io/resourcename"] = resourceName
+	labels[resourceNameLabel] = resourceName
 
 	if resourceNamespace != "" {
-		labels["eventtrigger.lib.projectsveltos.io/resourcenamespace"] = resourceNamespace
+		labels[resourceNamespaceLabel] = resourceNamespace
 	}
 
 	return labels
@@ -2326,7 +2640,7 @@ func getCESubject(cloudEvent map[string]interface{}) string {
 func removeInstantiatedResources(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
 	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
 	clusterProfiles []*configv1beta1.ClusterProfile, fromGenerators []libsveltosv1beta1.PolicyRef,
-	logger logr.Logger) error {
+	fromResourceGenerators []corev1.ObjectReference, logger logr.Logger) error {
 
 	if err := removeClusterProfiles(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger, er,
 		clusterProfiles, logger); err != nil {
@@ -2353,6 +2667,12 @@ func removeInstantiatedResources(ctx context.Context, c client.Client, clusterNa
 		policyRefs[fromGenerators[i]] = true
 	}
 
+	// ConfigMap/Secret instances created from ResourceGenerator are managed by removeGeneratedResources
+	resourceGeneratorRefs := getGeneratedPolicyRefs(fromResourceGenerators)
+	for i := range resourceGeneratorRefs {
+		policyRefs[resourceGeneratorRefs[i]] = true
+	}
+
 	if err := removeConfigMaps(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger,
 		er, policyRefs, logger); err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to remove stale configMaps: %v", err))
@@ -2365,6 +2685,12 @@ func removeInstantiatedResources(ctx context.Context, c client.Client, clusterNa
 		return err
 	}
 
+	if err := removeGeneratedResources(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger,
+		er, fromResourceGenerators, logger); err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to remove stale resources: %v", err))
+		return err
+	}
+
 	return nil
 }
 
@@ -2412,9 +2738,10 @@ func removeConfigMaps(ctx context.Context, c client.Client, clusterNamespace, cl
 	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
 		er, clusterType)
 
+	// Instantiated resources can be in any namespace (and namespace might have changed
+	// since resources were created). So do not filter by namespace
 	listOptions := []client.ListOption{
 		client.MatchingLabels(labels),
-		client.InNamespace(ReportNamespace),
 	}
 
 	configMaps := &corev1.ConfigMapList{}
@@ -2433,7 +2760,18 @@ func removeConfigMaps(ctx context.Context, c client.Client, clusterNamespace, cl
 			continue
 		}
 
-		if _, ok := policyRefs[*getPolicyRef(cm)]; !ok {
+		if _, ok := policyRefs[*getPolicyRef(cm)]; ok {
+			if err := clearPendingRemoval(ctx, c, cm, logger); err != nil {
+				return err
+			}
+			continue
+		}
+
+		remove, err := canBeRemoved(ctx, c, eventTrigger, er, cm, logger)
+		if err != nil {
+			return err
+		}
+		if remove {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("deleting configMap %s", cm.Name))
 			err = c.Delete(ctx, cm)
 			if err != nil {
@@ -2457,9 +2795,10 @@ func removeSecrets(ctx context.Context, c client.Client, clusterNamespace, clust
 	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
 		er, clusterType)
 
+	// Instantiated resources can be in any namespace (and namespace might have changed
+	// since resources were created). So do not filter by namespace
 	listOptions := []client.ListOption{
 		client.MatchingLabels(labels),
-		client.InNamespace(ReportNamespace),
 	}
 
 	secrets := &corev1.SecretList{}
@@ -2477,7 +2816,18 @@ func removeSecrets(ctx context.Context, c client.Client, clusterNamespace, clust
 			// when CloudEventAction is set to Delete
 			continue
 		}
-		if _, ok := policyRefs[*getPolicyRef(secret)]; !ok {
+		if _, ok := policyRefs[*getPolicyRef(secret)]; ok {
+			if err := clearPendingRemoval(ctx, c, secret, logger); err != nil {
+				return err
+			}
+			continue
+		}
+
+		remove, err := canBeRemoved(ctx, c, eventTrigger, er, secret, logger)
+		if err != nil {
+			return err
+		}
+		if remove {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("deleting secret %s", secret.Name))
 			err = c.Delete(ctx, secret)
 			if err != nil {
@@ -2521,12 +2871,26 @@ func removeClusterProfiles(ctx context.Context, c client.Client, clusterNamespac
 
 	for i := range clusterProfileList.Items {
 		cp := &clusterProfileList.Items[i]
-		if _, ok := currentClusterProfiles[cp.Name]; !ok {
+		if _, ok := currentClusterProfiles[cp.Name]; ok {
+			if err := clearPendingRemoval(ctx, c, cp, logger); err != nil {
+				return err
+			}
+			continue
+		}
+
+		remove, err := canBeRemoved(ctx, c, eventTrigger, er, cp, logger)
+		if err != nil {
+			return err
+		}
+		if remove {
 			logger.V(logs.LogInfo).Info(fmt.Sprintf("deleting clusterProfile %s", cp.Name))
 			err = c.Delete(ctx, cp)
 			if err != nil {
 				return err
 			}
+			recordAuditEntry(ctx, c, eventTrigger.Name, auditActionDeleted,
+				getEventCause(clusterNamespace, clusterName, clusterType, er, nil),
+				getClusterProfileAuditObjects(cp), logger)
 		}
 	}
 
@@ -2571,8 +2935,9 @@ func getNonInstantiatedClusterProfile(eventTrigger *v1beta1.EventTrigger,
 
 	return &configv1beta1.ClusterProfile{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:   clusterProfileName,
-			Labels: labels,
+			Name:            clusterProfileName,
+			Labels:          labels,
+			OwnerReferences: getEventTriggerOwnerReferences(eventTrigger),
 		},
 		Spec: *getClusterProfileSpec(eventTrigger),
 	}
@@ -2673,6 +3038,14 @@ func prepareCurrentObjectList(ctx context.Context, c client.Client, clusterNames
 func updateResource(ctx context.Context, dr dynamic.ResourceInterface, object client.Object,
 	logger logr.Logger) error {
 
+	_, err := applyResource(ctx, dr, object, logger)
+	return err
+}
+
+// applyResource server side applies object and returns the resulting resource
+func applyResource(ctx context.Context, dr dynamic.ResourceInterface, object client.Object,
+	logger logr.Logger) (*unstructured.Unstructured, error) {
+
 	l := logger.WithValues("resourceNamespace", object.GetNamespace(), "resourceName", object.GetName(),
 		"resourceGVK", object.GetObjectKind().GroupVersionKind())
 	l.V(logs.LogDebug).Info("updating resource")
@@ -2685,15 +3058,10 @@ func updateResource(ctx context.Context, dr dynamic.ResourceInterface, object cl
 
 	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, object)
 	if err != nil {
-		return err
-	}
-
-	_, err = dr.Patch(ctx, object.GetName(), types.ApplyPatchType, data, options)
-	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return dr.Patch(ctx, object.GetName(), types.ApplyPatchType, data, options)
 }
 
 func appendServiceAccountLabels(eventTrigger *v1beta1.EventTrigger, labels map[string]string) map[string]string {
@@ -2754,6 +3122,20 @@ func instantiateFromGeneratorsPerResource(ctx context.Context, c client.Client,
 			}
 		}
 
+		cause := getEventCause(clusterNamespace, clusterName, clusterType, er, &objects[i])
+		if isEventBlocked(eventTrigger.Name, cause) {
+			continue
+		}
+
+		if isWaitingForDestinations(eventTrigger, cause) {
+			existing, err := getExistingFromGenerators(ctx, c, labels)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, existing...)
+			continue
+		}
+
 		secretInfo, err := instantiateSecrets(ctx, c, eventTrigger, objects[i], clusterNamespace,
 			templateName, labels, logger)
 		if err != nil {
@@ -2777,6 +3159,20 @@ func instantiateFromGeneratorsPerAllResource(ctx context.Context, c client.Clien
 	er *libsveltosv1beta1.EventReport, clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
 	logger logr.Logger) ([]libsveltosv1beta1.PolicyRef, error) {
 
+	cause := getEventCause(clusterNamespace, clusterName, clusterType, er, nil)
+	if isEventBlocked(eventTrigger.Name, cause) {
+		return nil, nil
+	}
+
+	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
+		er, clusterType)
+	labels = appendGeneratorLabel(labels)
+	labels = appendServiceAccountLabels(eventTrigger, labels)
+
+	if isWaitingForDestinations(eventTrigger, cause) {
+		return getExistingFromGenerators(ctx, c, labels)
+	}
+
 	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
 
 	objects, err := prepareCurrentObjects(ctx, c, clusterNamespace, clusterName, clusterType, er, logger)
@@ -2785,11 +3181,6 @@ func instantiateFromGeneratorsPerAllResource(ctx context.Context, c client.Clien
 		return nil, err
 	}
 
-	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
-		er, clusterType)
-	labels = appendGeneratorLabel(labels)
-	labels = appendServiceAccountLabels(eventTrigger, labels)
-
 	result, err := instantiateSecrets(ctx, c, eventTrigger, objects, clusterNamespace,
 		templateName, labels, logger)
 	if err != nil {
@@ -2822,11 +3213,7 @@ func instantiateSecrets(ctx context.Context, c client.Client, e *v1beta1.EventTr
 			return nil, err
 		}
 		if info != nil {
-			resources = append(resources, libsveltosv1beta1.PolicyRef{
-				Namespace: info.Namespace,
-				Name:      info.Name,
-				Kind:      string(libsveltosv1beta1.SecretReferencedResourceKind),
-			})
+			resources = append(resources, *info)
 		}
 	}
 
@@ -2848,11 +3235,7 @@ func instantiateConfigMaps(ctx context.Context, c client.Client, e *v1beta1.Even
 			return nil, err
 		}
 		if info != nil {
-			resources = append(resources, libsveltosv1beta1.PolicyRef{
-				Namespace: info.Namespace,
-				Name:      info.Name,
-				Kind:      string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
-			})
+			resources = append(resources, *info)
 		}
 	}
 
@@ -2862,7 +3245,7 @@ func instantiateConfigMaps(ctx context.Context, c client.Client, e *v1beta1.Even
 // instantiateResourceFromGenerator creates a resource from generator and patch it to the management cluster
 func instantiateResourceFromGenerator(ctx context.Context, c client.Client, generator *v1beta1.GeneratorReference,
 	e *v1beta1.EventTrigger, clusterNamespace, templateName, kind string, data any, labels map[string]string,
-	logger logr.Logger) (*types.NamespacedName, error) {
+	logger logr.Logger) (*libsveltosv1beta1.PolicyRef, error) {
 
 	var namespace string
 	// The name of the referenced resource can be expressed as a template
@@ -2870,7 +3253,7 @@ func instantiateResourceFromGenerator(ctx context.Context, c client.Client, gene
 		namespace = clusterNamespace
 	} else {
 		referencedNamespace, err := instantiateSection(templateName, []byte(generator.Namespace), data,
-			funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 		if err != nil {
 			return nil, err
 		}
@@ -2879,17 +3262,17 @@ func instantiateResourceFromGenerator(ctx context.Context, c client.Client, gene
 
 	// The name of the referenced resource can be expressed as a template
 	referencedName, err := instantiateSection(templateName, []byte(generator.Name), data,
-		funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 	if err != nil {
 		return nil, err
 	}
 
 	var referencedResource client.Object
 	if kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-		referencedResource, err = getConfigMap(ctx, c,
+		referencedResource, err = getConfigMap(ctx, c, e,
 			types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 	} else {
-		referencedResource, err = getSecret(ctx, c,
+		referencedResource, err = getSecret(ctx, c, e,
 			types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 	}
 	if err != nil {
@@ -2911,22 +3294,42 @@ func instantiateResourceFromGenerator(ctx context.Context, c client.Client, gene
 	)
 
 	instantiatedName, err := instantiateSection(templateName, []byte(generator.InstantiatedResourceNameFormat), data,
-		funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate %q: %v", generator.InstantiatedResourceNameFormat, err))
 		return nil, err
 	}
 
-	info, err := instantiateReferencedPolicy(ctx, e, referencedResource, templateName, string(instantiatedName), data, labels, logger)
+	instantiatedNamespace, err := getInstantiatedNamespace(e, generator.InstantiatedNamespace, templateName,
+		data, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	instantiatedKind := kind
+	if isSopsEncrypted(referencedResource) {
+		instantiatedKind = string(libsveltosv1beta1.SecretReferencedResourceKind)
+	}
+
+	info, err := instantiateReferencedPolicy(ctx, c, e, referencedResource, templateName, instantiatedNamespace,
+		string(instantiatedName), data, labels, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	return info, nil
+	return &libsveltosv1beta1.PolicyRef{
+		Namespace: info.Namespace,
+		Name:      info.Name,
+		Kind:      instantiatedKind,
+	}, nil
 }
 
-func getValuesFrom(ctx context.Context, c client.Client, valuesFrom []configv1beta1.ValueFrom,
-	templateName string, cluster *corev1.ObjectReference, data any, useTxtFuncMap bool, logger logr.Logger) []client.Object {
+// getValuesFrom returns the referenced ConfigMaps/Secrets. Resources which cannot be fetched are skipped.
+// An error is returned only if the tenant admin who created EventTrigger e is not allowed to get one of those
+// or if a template violates the template policy.
+func getValuesFrom(ctx context.Context, c client.Client, e *v1beta1.EventTrigger, valuesFrom []configv1beta1.ValueFrom,
+	templateName string, cluster *corev1.ObjectReference, data any, useTxtFuncMap bool, policy *templatePolicy,
+	logger logr.Logger) ([]client.Object, error) {
 
 	result := make([]client.Object, 0, len(valuesFrom))
 	for i := range valuesFrom {
@@ -2936,31 +3339,41 @@ func getValuesFrom(ctx context.Context, c client.Client, valuesFrom []configv1be
 		if ref.Namespace == "" {
 			namespace = cluster.Namespace
 		} else {
-			instantiatedNamespace, err := instantiateSection(templateName, []byte(ref.Namespace), data, useTxtFuncMap, logger)
+			instantiatedNamespace, err := instantiateSection(templateName, []byte(ref.Namespace), data, useTxtFuncMap,
+				policy, logger)
 			if err != nil {
+				if isTemplatePolicyViolation(err) {
+					return nil, err
+				}
 				continue
 			}
 			namespace = string(instantiatedNamespace)
 		}
 
-		name, err := instantiateSection(templateName, []byte(ref.Name), data, useTxtFuncMap, logger)
+		name, err := instantiateSection(templateName, []byte(ref.Name), data, useTxtFuncMap, policy, logger)
 		if err != nil {
+			if isTemplatePolicyViolation(err) {
+				return nil, err
+			}
 			continue
 		}
 
 		var resource client.Object
 		if ref.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-			resource, err = getConfigMap(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(name)})
+			resource, err = getConfigMap(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(name)})
 		} else {
-			resource, err = getSecret(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(name)})
+			resource, err = getSecret(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(name)})
 		}
 		if err != nil {
+			if isTenantAccessDenied(err) {
+				return nil, err
+			}
 			continue
 		}
 
 		result = append(result, resource)
 	}
-	return result
+	return result, nil
 }
 
 func hasMatchingResources(er *libsveltosv1beta1.EventReport) bool {
@@ -2994,8 +3407,8 @@ func appendCloudEventClusterProfiles(ctx context.Context, c client.Client,
 	return clusterProfiles, nil
 }
 
-func deleteClusterProfile(ctx context.Context, c client.Client, clusterProfile *configv1beta1.ClusterProfile,
-	logger logr.Logger) error {
+func deleteClusterProfile(ctx context.Context, c client.Client, eventTriggerName string,
+	clusterProfile *configv1beta1.ClusterProfile, cause *eventCause, logger logr.Logger) error {
 
 	currentClusterProfile := &configv1beta1.ClusterProfile{}
 	err := c.Get(ctx, types.NamespacedName{Name: clusterProfile.Name}, currentClusterProfile)
@@ -3007,7 +3420,14 @@ func deleteClusterProfile(ctx context.Context, c client.Client, clusterProfile *
 	}
 
 	logger.V(logs.LogInfo).Info(fmt.Sprintf("delete ClusterProfile %s", clusterProfile.Name))
-	return c.Delete(ctx, currentClusterProfile)
+	err = c.Delete(ctx, currentClusterProfile)
+	if err != nil {
+		return err
+	}
+
+	recordAuditEntry(ctx, c, eventTriggerName, auditActionDeleted, cause,
+		getClusterProfileAuditObjects(currentClusterProfile), logger)
+	return nil
 }
 
 func deleteInstantiatedFromGenerators(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
@@ -3020,9 +3440,10 @@ func deleteInstantiatedFromGenerators(ctx context.Context, c client.Client, clus
 	labels = appendInstantiatedObjectLabelsForCloudEvent(labels, getCESource(cloudEvent),
 		getCESubject(cloudEvent))
 
+	// Instantiated resources can be in any namespace (and namespace might have changed
+	// since resources were created). So do not filter by namespace
 	listOptions := []client.ListOption{
 		client.MatchingLabels(labels),
-		client.InNamespace(ReportNamespace),
 	}
 
 	configMaps := &corev1.ConfigMapList{}
@@ -3057,7 +3478,7 @@ func deleteInstantiatedFromGenerators(ctx context.Context, c client.Client, clus
 		}
 	}
 
-	return nil
+	return deleteInstantiatedFromResourceGenerators(ctx, c, eventTrigger, labels, logger)
 }
 
 func instantiateCloudEventAction(clusterNamespace, clusterName string, eventTrigger *v1beta1.EventTrigger,
@@ -3066,7 +3487,7 @@ func instantiateCloudEventAction(clusterNamespace, clusterName string, eventTrig
 	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
 
 	instantiatedData, err := instantiateSection(templateName, []byte(eventTrigger.Spec.CloudEventAction), data,
-		funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations), logger)
+		funcmap.HasTextTemplateAnnotation(eventTrigger.Annotations), getTemplatePolicy(eventTrigger), logger)
 	if err != nil {
 		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to instantiate CloudEventAction template: %v", err))
 		return nil, err
@@ -18,18 +18,17 @@ package controllers_test
 
 import (
 	"context"
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/cloudevents/sdk-go/v2/event"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
-	"github.com/gdexlab/go-render/render"
 	"github.com/go-logr/logr"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
@@ -174,6 +173,7 @@ var _ = Describe("EventTrigger deployer", func() {
 
 		f := controllers.GetHandlersForFeature(v1beta1.FeatureEventTrigger)
 		clusterInfo, err := controllers.ProcessEventTrigger(&reconciler, context.TODO(), eScope,
+			controllers.NewClusterIndependentResources(eScope.EventTrigger),
 			controllers.GetKeyFromObject(c.Scheme(), currentCluster), f, logger)
 		Expect(err).To(BeNil())
 
@@ -351,16 +351,6 @@ var _ = Describe("EventTrigger deployer", func() {
 			eventReport,
 		}
 
-		config := render.AsCode(e.Spec)
-		config += render.AsCode(e.Labels)
-		config += render.AsCode(eventSource.Spec)
-		config += render.AsCode(eventReport.Spec)
-		config += render.AsCode(configMap.Data)
-		config += render.AsCode(secret.Data)
-		h := sha256.New()
-		h.Write([]byte(config))
-		expectedHash := h.Sum(nil)
-
 		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
 			WithObjects(initObjects...).Build()
 		Expect(addTypeInformationToObject(c.Scheme(), cluster)).To(Succeed())
@@ -368,7 +358,20 @@ var _ = Describe("EventTrigger deployer", func() {
 		hash, err := controllers.EventTriggerHash(context.TODO(), c, e, getClusterRef(cluster), logger)
 		Expect(err).To(BeNil())
 		Expect(hash).ToNot(BeNil())
-		Expect(reflect.DeepEqual(hash, expectedHash)).To(BeTrue())
+
+		// Hash is stable
+		currentHash, err := controllers.EventTriggerHash(context.TODO(), c, e, getClusterRef(cluster), logger)
+		Expect(err).To(BeNil())
+		Expect(reflect.DeepEqual(hash, currentHash)).To(BeTrue())
+
+		// Hash changes when a referenced resource changes
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(configMap), configMap)).To(Succeed())
+		configMap.Data = map[string]string{randomString(): randomString()}
+		Expect(c.Update(context.TODO(), configMap)).To(Succeed())
+
+		currentHash, err = controllers.EventTriggerHash(context.TODO(), c, e, getClusterRef(cluster), logger)
+		Expect(err).To(BeNil())
+		Expect(reflect.DeepEqual(hash, currentHash)).To(BeFalse())
 	})
 
 	It("removeStaleEventReports removes all EventReports for a given cluster/eventSource pair", func() {
@@ -1565,6 +1568,183 @@ spec:
 		Expect(configMapList.Items[0].Name).To(Equal(configMap.Name))
 	})
 
+	It("removeConfigMaps removes stale ConfigMaps from any namespace", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		clusterType := libsveltosv1beta1.ClusterTypeCapi
+		eventTriggerName := randomString()
+
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.EventSourceNameLabel: randomString(),
+				},
+			},
+		}
+
+		// ConfigMap instantiated in the new namespace
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: clusterNamespace,
+				Name:      randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(clusterNamespace,
+					clusterName, eventTriggerName, eventReport, clusterType),
+			},
+		}
+
+		// ConfigMap previously instantiated in the default namespace
+		toBeRemovedConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controllers.ReportNamespace,
+				Name:      configMap.Name,
+				Labels: controllers.GetInstantiatedObjectLabels(clusterNamespace,
+					clusterName, eventTriggerName, eventReport, clusterType),
+			},
+		}
+
+		initObjects := []client.Object{
+			configMap, toBeRemovedConfigMap,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(initObjects...).
+			WithObjects(initObjects...).Build()
+
+		eventTrigger := v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: eventTriggerName,
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				InstantiatedNamespace: "{{ .Cluster.metadata.namespace }}",
+			},
+		}
+		policyRef := libsveltosv1beta1.PolicyRef{
+			Kind:      string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			Namespace: configMap.Namespace,
+			Name:      configMap.Name,
+		}
+		policyRefs := map[libsveltosv1beta1.PolicyRef]bool{policyRef: true}
+
+		Expect(controllers.RemoveConfigMaps(context.TODO(), c, clusterNamespace, clusterName, clusterType,
+			&eventTrigger, eventReport, policyRefs, logger)).To(Succeed())
+
+		configMapList := &corev1.ConfigMapList{}
+		Expect(c.List(context.TODO(), configMapList)).To(Succeed())
+		Expect(len(configMapList.Items)).To(Equal(1))
+		Expect(configMapList.Items[0].Namespace).To(Equal(configMap.Namespace))
+		Expect(configMapList.Items[0].Name).To(Equal(configMap.Name))
+	})
+
+	It("removeConfigMaps honors DeletionGracePeriod", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		clusterType := libsveltosv1beta1.ClusterTypeSveltos
+		eventTriggerName := randomString()
+
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.EventSourceNameLabel: randomString(),
+				},
+			},
+		}
+
+		staleConfigMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: controllers.ReportNamespace,
+				Name:      randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(clusterNamespace,
+					clusterName, eventTriggerName, eventReport, clusterType),
+			},
+		}
+
+		initObjects := []client.Object{
+			staleConfigMap,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		eventTrigger := v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: eventTriggerName,
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DeletionGracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+		}
+
+		// ConfigMap is stale. It is marked as pending removal
+		Expect(controllers.RemoveConfigMaps(context.TODO(), c, clusterNamespace, clusterName, clusterType,
+			&eventTrigger, eventReport, nil, logger)).To(Succeed())
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: staleConfigMap.Namespace,
+			Name: staleConfigMap.Name}, currentConfigMap)).To(Succeed())
+		Expect(currentConfigMap.Annotations).To(HaveKey(controllers.PendingRemovalAnnotation))
+
+		// Match is back. Annotation is removed
+		policyRef := libsveltosv1beta1.PolicyRef{
+			Kind:      string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+			Namespace: staleConfigMap.Namespace,
+			Name:      staleConfigMap.Name,
+		}
+		Expect(controllers.RemoveConfigMaps(context.TODO(), c, clusterNamespace, clusterName, clusterType,
+			&eventTrigger, eventReport, map[libsveltosv1beta1.PolicyRef]bool{policyRef: true}, logger)).To(Succeed())
+		Expect(c.Get(context.TODO(), types.NamespacedName{Namespace: staleConfigMap.Namespace,
+			Name: staleConfigMap.Name}, currentConfigMap)).To(Succeed())
+		Expect(currentConfigMap.Annotations).ToNot(HaveKey(controllers.PendingRemovalAnnotation))
+
+		// Grace period has expired. ConfigMap is removed
+		currentConfigMap.Annotations = map[string]string{
+			controllers.PendingRemovalAnnotation: time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339),
+		}
+		Expect(c.Update(context.TODO(), currentConfigMap)).To(Succeed())
+		Expect(controllers.RemoveConfigMaps(context.TODO(), c, clusterNamespace, clusterName, clusterType,
+			&eventTrigger, eventReport, nil, logger)).To(Succeed())
+		err := c.Get(context.TODO(), types.NamespacedName{Namespace: staleConfigMap.Namespace,
+			Name: staleConfigMap.Name}, currentConfigMap)
+		Expect(err).ToNot(BeNil())
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("getInstantiatedNamespace returns the namespace for instantiated resources", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+
+		objects := &controllers.CurrentObjects{
+			Cluster: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"namespace": clusterNamespace,
+					"name":      clusterName,
+				},
+			},
+		}
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+		templateName := randomString()
+
+		// Nothing set. Default namespace is used
+		namespace, err := controllers.GetInstantiatedNamespace(eventTrigger, "", templateName, objects, logger)
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal(controllers.ReportNamespace))
+
+		eventTrigger.Spec.InstantiatedNamespace = "{{ .Cluster.metadata.namespace }}"
+		namespace, err = controllers.GetInstantiatedNamespace(eventTrigger, "", templateName, objects, logger)
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal(clusterNamespace))
+
+		// Generator level namespace takes precedence
+		namespace, err = controllers.GetInstantiatedNamespace(eventTrigger, "{{ .Cluster.metadata.name }}",
+			templateName, objects, logger)
+		Expect(err).To(BeNil())
+		Expect(namespace).To(Equal(clusterName))
+	})
+
 	It("removeSecrets removes stale Secrets", func() {
 		clusterNamespace := randomString()
 		clusterName := randomString()
@@ -1665,7 +1845,7 @@ spec:
 		}
 
 		var instantiatedContent map[string]string
-		instantiatedContent, err = controllers.InstantiateDataSection(randomString(), content, object, false, logger)
+		instantiatedContent, err = controllers.InstantiateDataSection(randomString(), content, object, false, nil, logger)
 		Expect(err).To(BeNil())
 		Expect(instantiatedContent).ToNot(BeEmpty())
 
@@ -2041,6 +2221,188 @@ data:
 			types.NamespacedName{Namespace: configMap2.Namespace, Name: configMap2.Name}, currentConfigMap)
 		Expect(err).To(BeNil())
 	})
+
+	It("getDestinationClusters instantiates the list of destination clusters", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		clusterType := libsveltosv1beta1.ClusterTypeSveltos
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DestinationClusters: `{{ range $i, $name := splitList "," (index .Resource.metadata.annotations "clusters") }}
+- apiVersion: lib.projectsveltos.io/v1beta1
+  kind: SveltosCluster
+  namespace: {{ $.Resource.metadata.namespace }}
+  name: {{ $name }}
+{{ end }}`,
+			},
+		}
+
+		object := controllers.CurrentObject{
+			Resource: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"namespace": clusterNamespace,
+					"annotations": map[string]interface{}{
+						"clusters": "east,west,east",
+					},
+				},
+			},
+		}
+
+		destinations, err := controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		// Duplicates are removed
+		Expect(len(destinations)).To(Equal(2))
+		Expect(destinations[0].Name).To(Equal("east"))
+		Expect(destinations[0].Namespace).To(Equal(clusterNamespace))
+		Expect(destinations[1].Name).To(Equal("west"))
+
+		eventTrigger.Spec.IncludeSourceCluster = true
+		destinations, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		Expect(len(destinations)).To(Equal(3))
+		Expect(destinations[2].Name).To(Equal(clusterName))
+
+		// An empty list is valid
+		eventTrigger.Spec.IncludeSourceCluster = false
+		eventTrigger.Spec.DestinationClusters = `{{ if eq (index .Resource.metadata.annotations "clusters") "none" }}
+- kind: SveltosCluster
+  name: none
+{{ end }}`
+		destinations, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		Expect(destinations).To(BeEmpty())
+
+		// Kind and name are required
+		eventTrigger.Spec.DestinationClusters = `- name: east`
+		_, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).ToNot(BeNil())
+
+		// Namespace is required
+		eventTrigger.Spec.DestinationClusters = `- {kind: SveltosCluster, name: east}`
+		_, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).ToNot(BeNil())
+
+		// Only Cluster and SveltosCluster are valid kinds
+		eventTrigger.Spec.DestinationClusters = `- {kind: ConfigMap, namespace: default, name: east}`
+		_, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).ToNot(BeNil())
+
+		// apiVersion must match kind and defaults to it
+		eventTrigger.Spec.DestinationClusters = `- {apiVersion: v1, kind: Cluster, namespace: default, name: east}`
+		_, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).ToNot(BeNil())
+
+		eventTrigger.Spec.DestinationClusters = `- {kind: Cluster, namespace: default, name: east}`
+		destinations, err = controllers.GetDestinationClusters(clusterNamespace, clusterName, clusterType,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		Expect(len(destinations)).To(Equal(1))
+		Expect(destinations[0].APIVersion).To(Equal(clusterv1.GroupVersion.String()))
+	})
+
+	It("getDestinationClusterSelector instantiates label values and match expressions", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DestinationClusterSelector: libsveltosv1beta1.Selector{
+					LabelSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{
+							"region": `{{ index .Resource.metadata.labels "region" }}`,
+							"env":    "production",
+						},
+						MatchExpressions: []metav1.LabelSelectorRequirement{
+							{
+								Key:      `{{ index .Resource.metadata.labels "tier-key" }}`,
+								Operator: metav1.LabelSelectorOpIn,
+								Values:   []string{`{{ index .Resource.metadata.labels "tier" }}`, "shared"},
+							},
+						},
+					},
+				},
+			},
+		}
+
+		object := controllers.CurrentObject{
+			Resource: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"region":   "us-east",
+						"tier-key": "tier",
+						"tier":     "gold",
+					},
+				},
+			},
+		}
+
+		selector, err := controllers.GetDestinationClusterSelector(clusterNamespace, clusterName,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		Expect(selector.MatchLabels).To(Equal(map[string]string{"region": "us-east", "env": "production"}))
+		Expect(len(selector.MatchExpressions)).To(Equal(1))
+		Expect(selector.MatchExpressions[0].Key).To(Equal("tier"))
+		Expect(selector.MatchExpressions[0].Values).To(Equal([]string{"gold", "shared"}))
+
+		// EventTrigger spec is not modified
+		Expect(eventTrigger.Spec.DestinationClusterSelector.MatchLabels["region"]).To(
+			Equal(`{{ index .Resource.metadata.labels "region" }}`))
+
+		// Instantiated selector must be valid
+		object.Resource["metadata"].(map[string]interface{})["labels"].(map[string]interface{})["region"] = "us east"
+		_, err = controllers.GetDestinationClusterSelector(clusterNamespace, clusterName,
+			eventTrigger, object, logger)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("getDestinationClusterSetRefs instantiates destination ClusterSet names", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DestinationClusterSetRefs: []string{
+					"static",
+					`{{ index .Resource.metadata.labels "region" }}-failover`,
+					`{{ index .Resource.metadata.labels "missing" | default "" }}`,
+					"static",
+				},
+			},
+		}
+
+		object := controllers.CurrentObject{
+			Resource: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"region":  "us-east",
+						"missing": "",
+					},
+				},
+			},
+		}
+
+		clusterSets, err := controllers.GetDestinationClusterSetRefs(clusterNamespace, clusterName,
+			eventTrigger, object, logger)
+		Expect(err).To(BeNil())
+		Expect(clusterSets).To(Equal([]string{"static", "us-east-failover"}))
+	})
 })
 
 func getClusterInfo(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType) *libsveltosv1beta1.ClusterInfo {
@@ -49,11 +49,15 @@ var (
 type (
 	CurrentObject  = currentObject
 	CurrentObjects = currentObjects
+	EventCause     = eventCause
 )
 
 var (
 	RemoveClusterInfoEntry          = removeClusterInfoEntry
 	EventTriggerHash                = eventTriggerHash
+	ComputeEventTriggerHash         = computeEventTriggerHash
+	NewClusterIndependentResources  = newClusterIndependentResources
+	FilterReferences                = filterReferences
 	RemoveStaleEventSources         = removeStaleEventSources
 	RemoveStaleEventReports         = removeStaleEventReports
 	DeployEventSource               = deployEventSource
@@ -69,15 +73,74 @@ var (
 	InstantiateFromGeneratorsPerResource = instantiateFromGeneratorsPerResource
 	DeleteInstantiatedFromGenerators     = deleteInstantiatedFromGenerators
 
+	InstantiateResourceGenerators  = instantiateResourceGenerators
+	ValidateGeneratedResourceScope = validateGeneratedResourceScope
+	RemoveGeneratedResources       = removeGeneratedResources
+	SplitYAMLDocuments             = splitYAMLDocuments
+	GetGeneratedPolicyRefs         = getGeneratedPolicyRefs
+
 	GetInstantiatedObjectLabels = getInstantiatedObjectLabels
 	GetClusterProfileName       = getClusterProfileName
 
 	InstantiateReferencedPolicyRefs = instantiateReferencedPolicyRefs
 	InstantiateDataSection          = instantiateDataSection
+	InstantiateSection              = instantiateSection
+	GetDestinationClusters          = getDestinationClusters
+	GetDestinationClusterSelector   = getDestinationClusterSelector
+	GetDestinationClusterSetRefs    = getDestinationClusterSetRefs
+	IsTemplatePolicyViolation       = isTemplatePolicyViolation
+	ResolveTemplatePolicy           = resolveTemplatePolicy
+	GetTemplatePolicy               = getTemplatePolicy
+	RemoveTemplatePolicy            = removeTemplatePolicy
 	RemoveConfigMaps                = removeConfigMaps
 	RemoveSecrets                   = removeSecrets
+	GetInstantiatedNamespace        = getInstantiatedNamespace
 	UnstructuredToTyped             = unstructuredToTyped
 
+	GetEventTriggerOwnerReferences   = getEventTriggerOwnerReferences
+	AppendOwnerReferences            = appendOwnerReferences
+	AddClusterProfileOwnerReferences = addClusterProfileOwnerReferences
+
+	SweepOrphansOnce = sweepOrphansOnce
+
+	IsInScheduleWindow    = isInScheduleWindow
+	GetNextScheduleWindow = getNextScheduleWindow
+
+	ApplyClusterProfile       = applyClusterProfile
+	IsClusterProfileApproved  = isClusterProfileApproved
+	GetClusterProfileSpecHash = getClusterProfileSpecHash
+	UpdateApprovalStatuses    = updateApprovalStatuses
+
+	RecordAuditEntry      = recordAuditEntry
+	GetAuditConfigMaps    = getAuditConfigMaps
+	GetAuditConfigMapName = getAuditConfigMapName
+
+	GetReprocessRequests      = getReprocessRequests
+	IsReprocessRequested      = isReprocessRequested
+	SetClusterReprocessed     = setClusterReprocessed
+	SetClusterReprocessFailed = setClusterReprocessFailed
+	CompleteReprocessRequests = completeReprocessRequests
+
+	GetDestinationStatus               = getDestinationStatus
+	GetGeneratedClusterProfileStatuses = getGeneratedClusterProfileStatuses
+	GetDestinationMatchingClusterRefs  = getDestinationMatchingClusterRefs
+
+	GetRemoteClientCache = getRemoteClientCache
+	RemoteClientCacheGet = (*remoteClientCache).get
+	RemoteClientCacheSet = (*remoteClientCache).set
+
+	SetCollectionRunning           = setCollectionRunning
+	CheckEventReportCollectionAt   = checkEventReportCollection
+	GetDrainContext                = getDrainContext
+	GetShardOwner                  = getShardOwner
+	GetLiveMembers                 = getLiveMembers
+	GetStaleLeases                 = getStaleLeases
+	IsShardOwner                   = isShardOwner
+	GetEventTriggerShardingKey     = getEventTriggerShardingKey
+	GetEventReportShardingKey      = getEventReportShardingKey
+	CanSkipInstantiation           = canSkipInstantiation
+	RecordInstantiationFingerprint = recordInstantiationFingerprint
+
 	BuildEventTriggersForEventSourceMap = buildEventTriggersForEventSourceMap
 	BuildEventTriggersForClusterMap     = buildEventTriggersForClusterMap
 	ShouldIgnore                        = shouldIgnore
@@ -95,16 +158,57 @@ var (
 const (
 	ReferencedResourceNamespaceLabel = referencedResourceNamespaceLabel
 	ReferencedResourceNameLabel      = referencedResourceNameLabel
+	PendingRemovalAnnotation         = pendingRemovalAnnotation
+	PendingApprovalAnnotation        = pendingApprovalAnnotation
+	PendingApprovalDetailsAnnotation = pendingApprovalDetailsAnnotation
+	ApprovedAnnotation               = approvedAnnotation
+	AuditEntriesPerConfigMap         = auditEntriesPerConfigMap
+	FingerprintAnnotation            = fingerprintAnnotation
+	EventTriggerShardGroupLabel      = eventTriggerShardGroupLabel
 )
 
 // fetcher
 var (
-	GetConfigMap             = getConfigMap
-	GetSecret                = getSecret
-	FetchPolicyRefs          = fetchPolicyRefs
-	FetchEventReports        = fetchEventReports
-	FetchEventSource         = fetchEventSource
-	FetchReferencedResources = fetchReferencedResources
+	GetConfigMap                   = getConfigMap
+	GetSecret                      = getSecret
+	IsTenantAccessDenied           = isTenantAccessDenied
+	AuthorizeGeneratedResource     = authorizeGeneratedResource
+	AuthorizeInstantiatedNamespace = authorizeInstantiatedNamespace
+	ValidateTenantClusters         = validateTenantClusters
+	IsTenantClusterViolation       = isTenantClusterViolation
+	ValidateTenantSourceCluster    = validateTenantSourceCluster
+	ReportBlockedEvent             = reportBlockedEvent
+	IsEventBlocked                 = isEventBlocked
+	PruneBlockedEvents             = pruneBlockedEvents
+	RemoveBlockedEvents            = removeBlockedEvents
+	UnblockEvent                   = unblockEvent
+	UpdateBlockedEventStatuses     = updateBlockedEventStatuses
+	DecryptSopsContent             = decryptSopsContent
+	ValidateSopsAccess             = validateSopsAccess
+	GetInstantiatedKind            = getInstantiatedKind
+	FetchPolicyRefs                = fetchPolicyRefs
+	FetchEventReports              = fetchEventReports
+	FetchEventSource               = fetchEventSource
+	FetchReferencedResources       = fetchReferencedResources
+)
+
+// destination resolution
+var (
+	GetEventCause                     = getEventCause
+	ResolveDestinationClusters        = resolveDestinationClusters
+	GetUnresolvedDestinations         = getUnresolvedDestinations
+	GetEventTriggersWaitingForCluster = getEventTriggersWaitingForCluster
+	IsUnresolvedDestination           = isUnresolvedDestination
+	IsWaitingForDestinations          = isWaitingForDestinations
+	GetExistingFromGenerators         = getExistingFromGenerators
+	RemoveUnresolvedDestinations      = removeUnresolvedDestinations
+)
+
+// inline EventSource
+var (
+	GetEventSourceName         = getEventSourceName
+	GetInlineEventSourceName   = getInlineEventSourceName
+	ReconcileInlineEventSource = reconcileInlineEventSource
 )
 
 func SetSchema(s *runtime.Scheme) {
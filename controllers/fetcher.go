@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
 	sourcev1 "github.com/fluxcd/source-controller/api/v1"
 	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
@@ -42,17 +44,28 @@ import (
 // - EventSource and corresponding EventReports (from the passed in cluster only);
 // - ConfigMaps referenced in the ConfigMapGenerator section, in the PolicyRefs section and ValuesFrom
 // - Secrets referenced in the SecretGenerator section, in the PolicyRefs section and ValuesFrom
+// - ConfigMaps referenced in the ResourceGenerator section
 func fetchReferencedResources(ctx context.Context, c client.Client,
 	e *v1beta1.EventTrigger, cluster *corev1.ObjectReference, logger logr.Logger) ([]client.Object, error) {
 
-	result := make([]client.Object, 0)
-
 	if e == nil {
 		return nil, fmt.Errorf("nil EventTrigger")
 	}
 
+	return fetchReferencedResourcesForCluster(ctx, c, newClusterIndependentResources(e), cluster, logger)
+}
+
+// fetchReferencedResourcesForCluster is fetchReferencedResources where referenced resources not
+// depending on the cluster are fetched by independent, so only once across clusters
+func fetchReferencedResourcesForCluster(ctx context.Context, c client.Client,
+	independent *clusterIndependentResources, cluster *corev1.ObjectReference, logger logr.Logger,
+) ([]client.Object, error) {
+
+	result := make([]client.Object, 0)
+	e := independent.clusterDependent
+
 	logger.V(logs.LogDebug).Info("fetch EventSource")
-	resource, err := fetchEventSource(ctx, c, cluster.Namespace, cluster.Name, e.Spec.EventSourceName,
+	resource, err := fetchEventSource(ctx, c, cluster.Namespace, cluster.Name, getEventSourceName(e),
 		clusterproxy.GetClusterType(cluster), logger)
 	if err != nil {
 		return nil, err
@@ -85,41 +98,166 @@ func fetchReferencedResources(ctx context.Context, c client.Client,
 
 		templateName := getTemplateName(cluster.Namespace, cluster.Name, e.Name)
 
-		referencedResources, err := collectResourcesFromConfigMapGenerators(ctx, c, objects, e,
-			templateName, cluster, logger)
+		referencedResources, err := fetchReferences(ctx, c, e, cluster, objects, templateName, logger)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, referencedResources...)
 
-		referencedResources, err = collectResourcesFromSecretGenerators(ctx, c, objects, e,
-			templateName, cluster, logger)
+		referencedResources, err = independent.get(ctx, c, logger)
 		if err != nil {
 			return nil, err
 		}
 		result = append(result, referencedResources...)
+	}
 
-		local, remote, _ := fetchPolicyRefs(ctx, c, e, cluster, objects, templateName, logger)
+	return result, nil
+}
 
-		result = appendToResult(result, local)
-		result = appendToResult(result, remote)
+// fetchReferences fetches the ConfigMaps/Secrets/Sources referenced in the generators, PolicyRefs and
+// ValuesFrom sections
+func fetchReferences(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	cluster *corev1.ObjectReference, objects any, templateName string, logger logr.Logger) ([]client.Object, error) {
+
+	result := make([]client.Object, 0)
+
+	referencedResources, err := collectResourcesFromConfigMapGenerators(ctx, c, objects, e,
+		templateName, cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, referencedResources...)
+
+	referencedResources, err = collectResourcesFromSecretGenerators(ctx, c, objects, e,
+		templateName, cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, referencedResources...)
 
-		for i := range e.Spec.HelmCharts {
-			valuesFrom := getValuesFrom(ctx, c, e.Spec.HelmCharts[i].ValuesFrom, templateName,
-				cluster, objects, funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
-			result = append(result, valuesFrom...)
+	referencedResources, err = collectResourcesFromResourceGenerators(ctx, c, objects, e,
+		templateName, cluster, logger)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, referencedResources...)
+
+	local, remote, err := fetchPolicyRefs(ctx, c, e, cluster, objects, templateName, logger)
+	if isTenantAccessDenied(err) || isTemplatePolicyViolation(err) {
+		return nil, err
+	}
+
+	result = appendToResult(result, local)
+	result = appendToResult(result, remote)
+
+	for i := range e.Spec.HelmCharts {
+		valuesFrom, err := getValuesFrom(ctx, c, e, e.Spec.HelmCharts[i].ValuesFrom, templateName,
+			cluster, objects, funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, valuesFrom...)
+	}
 
-		for i := range e.Spec.KustomizationRefs {
-			valuesFrom := getValuesFrom(ctx, c, e.Spec.KustomizationRefs[i].ValuesFrom, templateName,
-				cluster, objects, funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
-			result = append(result, valuesFrom...)
+	for i := range e.Spec.KustomizationRefs {
+		valuesFrom, err := getValuesFrom(ctx, c, e, e.Spec.KustomizationRefs[i].ValuesFrom, templateName,
+			cluster, objects, funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+		if err != nil {
+			return nil, err
 		}
+		result = append(result, valuesFrom...)
 	}
 
 	return result, nil
 }
 
+// clusterIndependentResources fetches, once per reconciliation (or EventReport collection), the resources referenced by an
+// EventTrigger whose namespace is set and whose namespace and name are not templates. Those are
+// the same for every matching cluster.
+type clusterIndependentResources struct {
+	// eventTrigger has only the references not depending on the cluster
+	eventTrigger *v1beta1.EventTrigger
+	// clusterDependent has only the references depending on the cluster
+	clusterDependent *v1beta1.EventTrigger
+
+	fetched   bool
+	resources []client.Object
+	err       error
+}
+
+func newClusterIndependentResources(e *v1beta1.EventTrigger) *clusterIndependentResources {
+	return &clusterIndependentResources{
+		eventTrigger:     filterReferences(e, true),
+		clusterDependent: filterReferences(e, false),
+	}
+}
+
+// getClusterIndependentResources returns the clusterIndependentResources for e from cache,
+// adding it if missing. cache can be nil, in which case nothing is cached.
+func getClusterIndependentResources(cache map[string]*clusterIndependentResources, e *v1beta1.EventTrigger,
+) *clusterIndependentResources {
+
+	if r, ok := cache[e.Name]; ok {
+		return r
+	}
+
+	r := newClusterIndependentResources(e)
+	if cache != nil {
+		cache[e.Name] = r
+	}
+	return r
+}
+
+// get returns the cluster independent resources. Those are fetched only the first time.
+func (r *clusterIndependentResources) get(ctx context.Context, c client.Client, logger logr.Logger,
+) ([]client.Object, error) {
+
+	if !r.fetched {
+		// No template to instantiate, so no cluster and no objects are needed
+		r.resources, r.err = fetchReferences(ctx, c, r.eventTrigger, &corev1.ObjectReference{}, currentObjects{},
+			getTemplateName("", "", r.eventTrigger.Name), logger)
+		r.fetched = true
+	}
+
+	return r.resources, r.err
+}
+
+// isClusterIndependentReference returns true if the referenced resource is the same for every cluster
+func isClusterIndependentReference(namespace, name string) bool {
+	return namespace != "" && !strings.Contains(namespace, "{{") && !strings.Contains(name, "{{")
+}
+
+// filterReferences returns a copy of e with only the references which do not depend
+// (clusterIndependent true) or which depend (clusterIndependent false) on the cluster
+func filterReferences(e *v1beta1.EventTrigger, clusterIndependent bool) *v1beta1.EventTrigger {
+	filtered := e.DeepCopy()
+	spec := &filtered.Spec
+
+	spec.ConfigMapGenerator = slices.DeleteFunc(spec.ConfigMapGenerator, func(r v1beta1.GeneratorReference) bool {
+		return isClusterIndependentReference(r.Namespace, r.Name) != clusterIndependent
+	})
+	spec.SecretGenerator = slices.DeleteFunc(spec.SecretGenerator, func(r v1beta1.GeneratorReference) bool {
+		return isClusterIndependentReference(r.Namespace, r.Name) != clusterIndependent
+	})
+	spec.ResourceGenerator = slices.DeleteFunc(spec.ResourceGenerator, func(r v1beta1.ResourceGeneratorReference) bool {
+		return isClusterIndependentReference(r.Namespace, r.Name) != clusterIndependent
+	})
+	spec.PolicyRefs = slices.DeleteFunc(spec.PolicyRefs, func(r configv1beta1.PolicyRef) bool {
+		return isClusterIndependentReference(r.Namespace, r.Name) != clusterIndependent
+	})
+	filterValuesFrom := func(r configv1beta1.ValueFrom) bool {
+		return isClusterIndependentReference(r.Namespace, r.Name) != clusterIndependent
+	}
+	for i := range spec.HelmCharts {
+		spec.HelmCharts[i].ValuesFrom = slices.DeleteFunc(spec.HelmCharts[i].ValuesFrom, filterValuesFrom)
+	}
+	for i := range spec.KustomizationRefs {
+		spec.KustomizationRefs[i].ValuesFrom = slices.DeleteFunc(spec.KustomizationRefs[i].ValuesFrom, filterValuesFrom)
+	}
+
+	return filtered
+}
+
 func appendToResult(result []client.Object, objects map[configv1beta1.PolicyRef]client.Object) []client.Object {
 	for k := range objects {
 		result = append(result, objects[k])
@@ -141,7 +279,7 @@ func collectResourcesFromConfigMapGenerators(ctx context.Context, c client.Clien
 			namespace = cluster.Namespace
 		} else {
 			instantiatedNamespace, err := instantiateSection(templateName, []byte(generator.Namespace), objects,
-				funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+				funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 			if err != nil {
 				return nil, err
 			}
@@ -150,13 +288,13 @@ func collectResourcesFromConfigMapGenerators(ctx context.Context, c client.Clien
 
 		// The name of the referenced resource can be expressed as a template
 		referencedName, err := instantiateSection(templateName, []byte(generator.Name), objects,
-			funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 		if err != nil {
 			return nil, err
 		}
 
 		var referencedResource client.Object
-		referencedResource, err = getConfigMap(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
+		referencedResource, err = getConfigMap(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				logger.V(logs.LogInfo).Info("referenced ConfigMapGenerator %s/%s not found",
@@ -188,7 +326,7 @@ func collectResourcesFromSecretGenerators(ctx context.Context, c client.Client,
 			namespace = cluster.Namespace
 		} else {
 			instantiatedNamespace, err := instantiateSection(templateName, []byte(generator.Namespace), objects,
-				funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+				funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 			if err != nil {
 				return nil, err
 			}
@@ -197,7 +335,7 @@ func collectResourcesFromSecretGenerators(ctx context.Context, c client.Client,
 
 		// The name of the referenced resource can be expressed as a template
 		referencedName, err := instantiateSection(templateName, []byte(generator.Name), objects,
-			funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 		if err != nil {
 			if apierrors.IsNotFound(err) {
 				logger.V(logs.LogInfo).Info("referenced SecretMapGenerator %s/%s not found",
@@ -210,7 +348,7 @@ func collectResourcesFromSecretGenerators(ctx context.Context, c client.Client,
 		}
 
 		var referencedResource client.Object
-		referencedResource, err = getSecret(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
+		referencedResource, err = getSecret(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 		if err != nil {
 			return nil, err
 		}
@@ -221,6 +359,28 @@ func collectResourcesFromSecretGenerators(ctx context.Context, c client.Client,
 	return results, nil
 }
 
+// collectResourcesFromResourceGenerators returns all ConfigMaps referenced by ResourceGenerators
+func collectResourcesFromResourceGenerators(ctx context.Context, c client.Client, objects any,
+	e *v1beta1.EventTrigger, templateName string, cluster *corev1.ObjectReference, logger logr.Logger,
+) ([]client.Object, error) {
+
+	results := make([]client.Object, 0, len(e.Spec.ResourceGenerator))
+
+	for i := range e.Spec.ResourceGenerator {
+		configMap, err := getResourceGeneratorConfigMap(ctx, c, &e.Spec.ResourceGenerator[i], e, cluster.Namespace,
+			templateName, objects, logger)
+		if err != nil {
+			return nil, err
+		}
+		if configMap == nil {
+			continue
+		}
+		results = append(results, configMap)
+	}
+
+	return results, nil
+}
+
 // fetchEventSource fetches referenced EventSource
 func fetchEventSource(ctx context.Context, c client.Client,
 	clusterNamespace, clusterName, eventSourceName string, clusterType libsveltosv1beta1.ClusterType,
@@ -284,7 +444,7 @@ func fetchPolicyRefs(ctx context.Context, c client.Client, e *v1beta1.EventTrigg
 			namespace = cluster.Namespace
 		} else {
 			instantiatedNamespace, err := instantiateSection(templateName, []byte(policyRef.Namespace), objects,
-				funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+				funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -292,17 +452,17 @@ func fetchPolicyRefs(ctx context.Context, c client.Client, e *v1beta1.EventTrigg
 		}
 
 		referencedName, err := instantiateSection(templateName, []byte(policyRef.Name), objects,
-			funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 		if err != nil {
 			return nil, nil, err
 		}
 
 		if policyRef.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) {
-			object, err = getConfigMap(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
+			object, err = getConfigMap(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 		} else if policyRef.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind) {
-			object, err = getSecret(ctx, c, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
+			object, err = getSecret(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
 		} else {
-			object, err = getSource(ctx, c, namespace, string(referencedName), policyRef.Kind)
+			object, err = getSource(ctx, c, e, namespace, string(referencedName), policyRef.Kind)
 		}
 
 		if err != nil {
@@ -321,7 +481,7 @@ func fetchPolicyRefs(ctx context.Context, c client.Client, e *v1beta1.EventTrigg
 		var referencedPath []byte
 		if policyRef.Path != "" {
 			referencedPath, err = instantiateSection(templateName, []byte(policyRef.Path), objects,
-				funcmap.HasTextTemplateAnnotation(e.Annotations), logger)
+				funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -349,9 +509,15 @@ func fetchPolicyRefs(ctx context.Context, c client.Client, e *v1beta1.EventTrigg
 }
 
 // getConfigMap retrieves any ConfigMap from the given name and namespace.
-func getConfigMap(ctx context.Context, c client.Client, configmapName types.NamespacedName,
+// If EventTrigger e was created by a tenant admin, tenant must be allowed to get the ConfigMap.
+func getConfigMap(ctx context.Context, c client.Client, e *v1beta1.EventTrigger, configmapName types.NamespacedName,
 ) (*corev1.ConfigMap, error) {
 
+	if err := checkTenantAccess(ctx, c, e, "", "configmaps", configmapName.Namespace,
+		configmapName.Name); err != nil {
+		return nil, err
+	}
+
 	configMap := &corev1.ConfigMap{}
 	if err := c.Get(ctx, configmapName, configMap); err != nil {
 		return nil, err
@@ -361,9 +527,15 @@ func getConfigMap(ctx context.Context, c client.Client, configmapName types.Name
 }
 
 // getSecret retrieves any Secret from the given secret name and namespace.
-func getSecret(ctx context.Context, c client.Client, secretName types.NamespacedName,
+// If EventTrigger e was created by a tenant admin, tenant must be allowed to get the Secret.
+func getSecret(ctx context.Context, c client.Client, e *v1beta1.EventTrigger, secretName types.NamespacedName,
 ) (*corev1.Secret, error) {
 
+	if err := checkTenantAccess(ctx, c, e, "", "secrets", secretName.Namespace,
+		secretName.Name); err != nil {
+		return nil, err
+	}
+
 	secret := &corev1.Secret{}
 	if err := c.Get(ctx, secretName, secret); err != nil {
 		return nil, err
@@ -376,9 +548,22 @@ func getSecret(ctx context.Context, c client.Client, secretName types.Namespaced
 	return secret, nil
 }
 
-func getSource(ctx context.Context, c client.Client, namespace, sourceName, sourceKind string,
+// getSource retrieves a Flux Source. If EventTrigger e was created by a tenant admin, tenant must be
+// allowed to get the Source.
+func getSource(ctx context.Context, c client.Client, e *v1beta1.EventTrigger, namespace, sourceName, sourceKind string,
 ) (client.Object, error) {
 
+	if resource, ok := map[string]string{
+		sourcev1.GitRepositoryKind:   "gitrepositories",
+		sourcev1b2.OCIRepositoryKind: "ocirepositories",
+		sourcev1b2.BucketKind:        "buckets",
+	}[sourceKind]; ok {
+		if err := checkTenantAccess(ctx, c, e, sourcev1.GroupVersion.Group, resource, namespace,
+			sourceName); err != nil {
+			return nil, err
+		}
+	}
+
 	namespacedName := types.NamespacedName{
 		Namespace: namespace,
 		Name:      sourceName,
@@ -64,7 +64,7 @@ var _ = Describe("Fetcher", func() {
 			WithObjects(initObjects...).Build()
 
 		configMapName := types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name}
-		r, err := controllers.GetConfigMap(context.TODO(), c, configMapName)
+		r, err := controllers.GetConfigMap(context.TODO(), c, nil, configMapName)
 		Expect(err).To(BeNil())
 		Expect(r).ToNot(BeNil())
 	})
@@ -86,7 +86,7 @@ var _ = Describe("Fetcher", func() {
 			WithObjects(initObjects...).Build()
 
 		secretName := types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name}
-		r, err := controllers.GetSecret(context.TODO(), c, secretName)
+		r, err := controllers.GetSecret(context.TODO(), c, nil, secretName)
 		Expect(err).To(BeNil())
 		Expect(r).ToNot(BeNil())
 	})
@@ -465,5 +465,24 @@ var _ = Describe("Fetcher", func() {
 		result, err := controllers.FetchReferencedResources(context.TODO(), c, e, getClusterRef(cluster), logger)
 		Expect(err).To(BeNil())
 		Expect(len(result)).To(Equal(4)) // EventSource + EventReport + Referenced Resources
+
+		// Secret namespace is set and neither its namespace nor its name is a template.
+		// ConfigMap namespace is the cluster namespace.
+		independent := controllers.FilterReferences(e, true)
+		Expect(len(independent.Spec.PolicyRefs)).To(Equal(1))
+		Expect(independent.Spec.PolicyRefs[0].Name).To(Equal(secret.Name))
+		dependent := controllers.FilterReferences(e, false)
+		Expect(len(dependent.Spec.PolicyRefs)).To(Equal(1))
+		Expect(dependent.Spec.PolicyRefs[0].Name).To(Equal(configMap.Name))
+		Expect(len(e.Spec.PolicyRefs)).To(Equal(2))
+
+		// Fetching cluster independent resources once does not change the hash
+		hash, err := controllers.EventTriggerHash(context.TODO(), c, e, getClusterRef(cluster), logger)
+		Expect(err).To(BeNil())
+		resources := controllers.NewClusterIndependentResources(e)
+		currentHash, err := controllers.ComputeEventTriggerHash(context.TODO(), c, e, resources,
+			getClusterRef(cluster), logger)
+		Expect(err).To(BeNil())
+		Expect(currentHash).To(Equal(hash))
 	})
 })
@@ -0,0 +1,323 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/gdexlab/go-render/render"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// EventReports are evaluated on every collection iteration. Instantiating an EventTrigger means
+// rendering all templates and patching ClusterProfiles, ConfigMaps and Secrets even when nothing
+// changed. A fingerprint of everything instantiation depends on (EventReport, EventTrigger,
+// referenced resources and cluster) is stored on the generated ClusterProfiles. When it still
+// matches, instantiation is skipped.
+// The fingerprint recorded on each ClusterProfile also includes the digest of its Spec, so a
+// ClusterProfile Spec modified by anything other than event-manager is detected and instantiation
+// restores it.
+// Checking the fingerprint runs on every iteration for each EventReport and EventTrigger, so it is
+// kept cheap: referenced resources not depending on the cluster are fetched once per iteration, their
+// digests come from the digest cache and generated objects are listed once per check.
+
+const (
+	// fingerprintAnnotation is set on generated ClusterProfiles. Value is the fingerprint of the
+	// instantiation inputs and of the ClusterProfile Spec followed by the number of ClusterProfiles, ConfigMaps, Secrets and
+	// ResourceGenerator resources generated, so that any deleted object causes instantiation to
+	// happen again. The fingerprint is recorded right after instantiation, while the number of
+	// generated objects is recorded by the first check that lists them (see canSkipInstantiation).
+	fingerprintAnnotation = "eventtrigger.lib.projectsveltos.io/fingerprint"
+)
+
+// getInstantiationFingerprint returns the fingerprint of all inputs used when instantiating
+// eventTrigger for EventReport er in the cluster
+func getInstantiationFingerprint(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger,
+	independent *clusterIndependentResources, er *libsveltosv1beta1.EventReport, logger logr.Logger,
+) (string, error) {
+
+	cluster, err := clusterproxy.GetCluster(ctx, c, clusterNamespace, clusterName, clusterType)
+	if err != nil {
+		return "", err
+	}
+	// Those change on every update and do not affect instantiation
+	cluster.SetResourceVersion("")
+	cluster.SetManagedFields(nil)
+
+	resources, err := fetchReferencedResourcesForCluster(ctx, c, independent,
+		getClusterRef(clusterNamespace, clusterName, clusterType), logger)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	// For EventTriggers created by tenant admins, instantiation depends on the clusters tenant
+	// has access to (see validateTenantClusters)
+	if saNamespace, saName, ok := getTenantServiceAccount(eventTrigger); ok {
+		allowed, err := getTenantAllowedClusters(ctx, c, saNamespace, saName)
+		if err != nil {
+			return "", err
+		}
+		clusters := make([]string, 0, len(allowed))
+		for k := range allowed {
+			clusters = append(clusters, k)
+		}
+		sort.Strings(clusters)
+		h.Write([]byte(render.AsCode(clusters)))
+	}
+	// Instantiation depends on whether destination clusters exist and are ready
+	// (see resolveDestinationClusters)
+	err = writeUnresolvedDestinations(ctx, c, h, eventTrigger.Name,
+		getClusterRef(clusterNamespace, clusterName, clusterType), logger)
+	if err != nil {
+		return "", err
+	}
+	h.Write(getEventTriggerDigest(eventTrigger))
+	h.Write([]byte(render.AsCode(eventTrigger.Annotations)))
+	h.Write([]byte(render.AsCode(er.Spec)))
+	h.Write([]byte(render.AsCode(cluster)))
+	writeReferencedResources(h, resources)
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// canSkipInstantiation returns true if nothing changed since eventTrigger was last instantiated
+// for EventReport er in the cluster
+func canSkipInstantiation(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
+	fingerprint string, logger logr.Logger) (bool, error) {
+
+	// CloudEvents are consumed once processed. So those are always processed.
+	// When no resource is matching, stale resources are removed.
+	if !er.DeletionTimestamp.IsZero() || len(er.Spec.CloudEvents) != 0 || !hasMatchingResources(er) {
+		return false, nil
+	}
+
+	objects, err := getGeneratedObjects(ctx, c, clusterNamespace, clusterName, clusterType, eventTrigger, er, logger)
+	if err != nil {
+		return false, err
+	}
+
+	clusterProfiles := make([]*configv1beta1.ClusterProfile, 0, len(objects))
+	for i := range objects {
+		if cp, ok := objects[i].(*configv1beta1.ClusterProfile); ok {
+			clusterProfiles = append(clusterProfiles, cp)
+		}
+	}
+
+	if len(clusterProfiles) == 0 {
+		return false, nil
+	}
+
+	// Stale objects pending removal must be removed once DeletionGracePeriod elapses
+	if hasPendingRemovals(objects) {
+		return false, nil
+	}
+
+	toComplete := make(map[*configv1beta1.ClusterProfile]string)
+	for i := range clusterProfiles {
+		cpFingerprint, err := getClusterProfileFingerprint(fingerprint, clusterProfiles[i])
+		if err != nil {
+			return false, err
+		}
+		expected := getFingerprintAnnotationValue(cpFingerprint, len(objects))
+
+		annotations := clusterProfiles[i].Annotations
+		switch annotations[fingerprintAnnotation] {
+		case expected:
+		case cpFingerprint:
+			// Instantiated with the same inputs. Number of generated objects is not recorded yet.
+			toComplete[clusterProfiles[i]] = expected
+		default:
+			return false, nil
+		}
+		// An approval must be acted upon
+		if hash, ok := annotations[pendingApprovalAnnotation]; ok &&
+			isClusterProfileApproved(clusterProfiles[i], hash) {
+
+			return false, nil
+		}
+	}
+
+	for cp, expected := range toComplete {
+		if err := setFingerprintAnnotation(ctx, c, cp, expected, logger); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// recordInstantiationFingerprint stores the fingerprint on the ClusterProfiles generated by
+// eventTrigger for EventReport er in the cluster. Only ClusterProfiles are listed. The number of
+// generated objects is recorded by the next canSkipInstantiation.
+func recordInstantiationFingerprint(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
+	fingerprint string, logger logr.Logger) error {
+
+	clusterProfiles, err := getGeneratedClusterProfiles(ctx, c, clusterNamespace, clusterName, clusterType,
+		eventTrigger, er)
+	if err != nil {
+		return err
+	}
+
+	for i := range clusterProfiles {
+		cpFingerprint, err := getClusterProfileFingerprint(fingerprint, clusterProfiles[i])
+		if err != nil {
+			return err
+		}
+		if err := setFingerprintAnnotation(ctx, c, clusterProfiles[i], cpFingerprint, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getClusterProfileFingerprint returns the fingerprint of the instantiation inputs followed by the
+// digest of the ClusterProfile Spec
+func getClusterProfileFingerprint(fingerprint string, cp *configv1beta1.ClusterProfile) (string, error) {
+	hash, err := getClusterProfileSpecHash(&cp.Spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%s", fingerprint, hash), nil
+}
+
+func setFingerprintAnnotation(ctx context.Context, c client.Client, cp *configv1beta1.ClusterProfile,
+	value string, logger logr.Logger) error {
+
+	if cp.Annotations[fingerprintAnnotation] == value {
+		return nil
+	}
+
+	logger.V(logs.LogVerbose).Info(fmt.Sprintf("recording fingerprint on ClusterProfile %s", cp.Name))
+	patch := client.MergeFrom(cp.DeepCopy())
+	if cp.Annotations == nil {
+		cp.Annotations = make(map[string]string)
+	}
+	cp.Annotations[fingerprintAnnotation] = value
+	return c.Patch(ctx, cp, patch)
+}
+
+// getGeneratedClusterProfiles returns the ClusterProfiles generated by eventTrigger for the
+// resources matching in EventReport er. ClusterProfiles generated because of CloudEvents are excluded.
+func getGeneratedClusterProfiles(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
+) ([]*configv1beta1.ClusterProfile, error) {
+
+	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name, er, clusterType)
+
+	clusterProfileList := &configv1beta1.ClusterProfileList{}
+	err := c.List(ctx, clusterProfileList, client.MatchingLabels(labels))
+	if err != nil {
+		return nil, err
+	}
+
+	clusterProfiles := make([]*configv1beta1.ClusterProfile, 0, len(clusterProfileList.Items))
+	for i := range clusterProfileList.Items {
+		cp := &clusterProfileList.Items[i]
+		if isGeneratedFromCloudEvent(cp) || !cp.DeletionTimestamp.IsZero() {
+			continue
+		}
+		clusterProfiles = append(clusterProfiles, cp)
+	}
+
+	return clusterProfiles, nil
+}
+
+// getGeneratedObjects returns the ClusterProfiles, ConfigMaps, Secrets and resources generated by
+// eventTrigger for the resources matching in EventReport er. Objects generated because of CloudEvents
+// are excluded.
+func getGeneratedObjects(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
+	logger logr.Logger) ([]client.Object, error) {
+
+	clusterProfiles, err := getGeneratedClusterProfiles(ctx, c, clusterNamespace, clusterName, clusterType,
+		eventTrigger, er)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]client.Object, 0, len(clusterProfiles))
+	for i := range clusterProfiles {
+		result = append(result, clusterProfiles[i])
+	}
+
+	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name, er, clusterType)
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.List(ctx, configMaps, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range configMaps.Items {
+		result = append(result, &configMaps.Items[i])
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+	for i := range secrets.Items {
+		result = append(result, &secrets.Items[i])
+	}
+
+	var gvks []schema.GroupVersionKind
+	for _, gvk := range getGeneratedResourceKinds(eventTrigger) {
+		// ConfigMaps and Secrets are already listed
+		if gvk.Group == "" && (gvk.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) ||
+			gvk.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind)) {
+
+			continue
+		}
+		gvks = appendGVK(gvks, gvk)
+	}
+	resources, err := listGeneratedResources(ctx, c, gvks, appendGeneratorLabel(labels), logger)
+	if err != nil {
+		return nil, err
+	}
+	for i := range resources {
+		result = append(result, &resources[i])
+	}
+
+	filtered := make([]client.Object, 0, len(result))
+	for i := range result {
+		if isGeneratedFromCloudEvent(result[i]) || !result[i].GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		filtered = append(filtered, result[i])
+	}
+
+	return filtered, nil
+}
+
+func getFingerprintAnnotationValue(fingerprint string, generatedObjects int) string {
+	return fmt.Sprintf("%s:%d", fingerprint, generatedObjects)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Fingerprint", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("canSkipInstantiation returns true only when recorded fingerprint matches", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		clusterType := libsveltosv1beta1.ClusterTypeSveltos
+		eventSourceName := randomString()
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: clusterNamespace,
+				Name:      randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.EventSourceNameLabel: eventSourceName,
+				},
+			},
+			Spec: libsveltosv1beta1.EventReportSpec{
+				MatchingResources: []corev1.ObjectReference{
+					{Kind: "Service", APIVersion: "v1", Namespace: randomString(), Name: randomString()},
+				},
+			},
+		}
+
+		clusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					"eventtrigger.lib.projectsveltos.io/eventtriggername": eventTrigger.Name,
+					"eventtrigger.lib.projectsveltos.io/clusterNamespace": clusterNamespace,
+					"eventtrigger.lib.projectsveltos.io/clustername":      clusterName,
+					"eventtrigger.lib.projectsveltos.io/clustertype":      string(clusterType),
+					"eventtrigger.lib.projectsveltos.io/eventreportname":  eventSourceName,
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(clusterProfile).Build()
+
+		fingerprint := randomString()
+		skip, err := controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		Expect(controllers.RecordInstantiationFingerprint(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)).To(Succeed())
+
+		currentClusterProfile := &configv1beta1.ClusterProfile{}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterProfile), currentClusterProfile)).To(Succeed())
+		Expect(currentClusterProfile.Annotations).To(HaveKey(controllers.FingerprintAnnotation))
+
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeTrue())
+
+		// Number of generated objects is recorded by the first check
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterProfile), currentClusterProfile)).To(Succeed())
+		specHash, err := controllers.GetClusterProfileSpecHash(&currentClusterProfile.Spec)
+		Expect(err).To(BeNil())
+		Expect(currentClusterProfile.Annotations[controllers.FingerprintAnnotation]).To(
+			Equal(fingerprint + "." + specHash + ":1"))
+
+		// Inputs changed
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, randomString(), logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		// Generated ConfigMaps are part of the instantiation outcome
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+				Labels:    clusterProfile.Labels,
+			},
+		}
+		Expect(c.Create(context.TODO(), configMap)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		Expect(controllers.RecordInstantiationFingerprint(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeTrue())
+
+		// A deleted generated ConfigMap causes instantiation to happen again
+		Expect(c.Delete(context.TODO(), configMap)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		Expect(controllers.RecordInstantiationFingerprint(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)).To(Succeed())
+
+		// Objects pending removal must be reconsidered until the grace period elapses
+		eventTrigger.Spec.DeletionGracePeriod = &metav1.Duration{Duration: time.Minute}
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterProfile), currentClusterProfile)).To(Succeed())
+		currentClusterProfile.Annotations[controllers.PendingRemovalAnnotation] = time.Now().Format(time.RFC3339)
+		Expect(c.Update(context.TODO(), currentClusterProfile)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterProfile), currentClusterProfile)).To(Succeed())
+		delete(currentClusterProfile.Annotations, controllers.PendingRemovalAnnotation)
+		Expect(c.Update(context.TODO(), currentClusterProfile)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeTrue())
+
+		// A modified ClusterProfile Spec causes instantiation to happen again
+		Expect(c.Get(context.TODO(), client.ObjectKeyFromObject(clusterProfile), currentClusterProfile)).To(Succeed())
+		currentClusterProfile.Spec.Tier = 10
+		Expect(c.Update(context.TODO(), currentClusterProfile)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+
+		Expect(controllers.RecordInstantiationFingerprint(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)).To(Succeed())
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeTrue())
+
+		// CloudEvents are always processed
+		eventReport.Spec.CloudEvents = [][]byte{[]byte(`{"id":"1"}`)}
+		skip, err = controllers.CanSkipInstantiation(context.TODO(), c, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, fingerprint, logger)
+		Expect(err).To(BeNil())
+		Expect(skip).To(BeFalse())
+	})
+})
@@ -0,0 +1,208 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+	"sync"
+
+	sourcev1 "github.com/fluxcd/source-controller/api/v1"
+	sourcev1b2 "github.com/fluxcd/source-controller/api/v1beta2"
+	"github.com/gdexlab/go-render/render"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// An EventTrigger hash is computed for every matching cluster on every reconciliation. Rendering
+// the EventTrigger and all the referenced resources is what dominates that computation.
+// digestCache keeps, per object, the digest of its content along with the resourceVersion the
+// digest was computed for. A digest is recomputed only when the object changes.
+// The EventTrigger digest (cluster independent inputs) is cached the same way, so it is computed
+// once per reconciliation and not once per cluster.
+// Digests not used since the previous EventReport collection iteration are evicted (see evictUnused),
+// so digests for deleted objects do not accumulate.
+
+type digestEntry struct {
+	resourceVersion string
+	digest          []byte
+	// used is set when digest is used and reset on every eviction
+	used bool
+}
+
+type digestCache struct {
+	mux sync.Mutex
+
+	// key: object kind, namespace, name and UID
+	entries map[string]*digestEntry
+}
+
+var (
+	digestCacheInstance *digestCache
+	digestCacheLock     = &sync.Mutex{}
+)
+
+func getDigestCache() *digestCache {
+	if digestCacheInstance == nil {
+		digestCacheLock.Lock()
+		defer digestCacheLock.Unlock()
+		if digestCacheInstance == nil {
+			digestCacheInstance = &digestCache{
+				entries: make(map[string]*digestEntry),
+			}
+		}
+	}
+
+	return digestCacheInstance
+}
+
+func getDigestCacheKey(kind string, obj client.Object) string {
+	return fmt.Sprintf("%s:%s/%s:%s", kind, obj.GetNamespace(), obj.GetName(), obj.GetUID())
+}
+
+// getDigest returns the digest for obj, computing it with compute only if obj changed since
+// the digest was last computed
+func (d *digestCache) getDigest(kind string, obj client.Object, compute func() []byte) []byte {
+	resourceVersion := obj.GetResourceVersion()
+	if resourceVersion == "" {
+		// Object was not read from the API server. Nothing to key the digest on.
+		return compute()
+	}
+
+	key := getDigestCacheKey(kind, obj)
+
+	d.mux.Lock()
+	entry, ok := d.entries[key]
+	if ok && entry.resourceVersion == resourceVersion {
+		entry.used = true
+		d.mux.Unlock()
+		return entry.digest
+	}
+	d.mux.Unlock()
+
+	digest := compute()
+
+	d.mux.Lock()
+	d.entries[key] = &digestEntry{resourceVersion: resourceVersion, digest: digest, used: true}
+	d.mux.Unlock()
+
+	return digest
+}
+
+// evictUnused removes all digests not used since the previous call
+func (d *digestCache) evictUnused() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	for key, entry := range d.entries {
+		if !entry.used {
+			delete(d.entries, key)
+			continue
+		}
+		entry.used = false
+	}
+}
+
+// forget removes all digests for objects of the given kind, namespace and name
+func (d *digestCache) forget(kind, namespace, name string) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+
+	prefix := fmt.Sprintf("%s:%s/%s:", kind, namespace, name)
+	for key := range d.entries {
+		if strings.HasPrefix(key, prefix) {
+			delete(d.entries, key)
+		}
+	}
+}
+
+func getSHA256(content string) []byte {
+	h := sha256.Sum256([]byte(content))
+	return h[:]
+}
+
+// getEventTriggerDigest returns the digest of the cluster independent EventTrigger inputs
+func getEventTriggerDigest(e *v1beta1.EventTrigger) []byte {
+	return getDigestCache().getDigest(v1beta1.EventTriggerKind, e, func() []byte {
+		config := getVersion()
+		config += render.AsCode(e.Spec)
+		config += render.AsCode(e.Labels)
+		// When in agentless mode, EventSources instances are not copued to managed cluster anymore.
+		// This addition ensures the EvenTrigger is redeployed due to the change in deployment location.
+		if getAgentInMgmtCluster() {
+			config += ("agentless")
+		}
+		return getSHA256(config)
+	})
+}
+
+// getReferencedResourceDigest returns the digest of the content of a referenced resource that
+// matters when instantiating an EventTrigger
+func getReferencedResourceDigest(resource client.Object) []byte {
+	var kind string
+	var content func() string
+	switch r := resource.(type) {
+	case *corev1.ConfigMap:
+		kind, content = "ConfigMap", func() string { return render.AsCode(r.Data) }
+	case *corev1.Secret:
+		kind, content = "Secret", func() string { return render.AsCode(r.Data) }
+	case *sourcev1b2.Bucket:
+		kind, content = sourcev1b2.BucketKind, func() string { return render.AsCode(r.Status.Artifact) }
+	case *sourcev1b2.OCIRepository:
+		kind, content = sourcev1b2.OCIRepositoryKind, func() string { return render.AsCode(r.Status.Artifact) }
+	case *sourcev1.GitRepository:
+		kind, content = sourcev1.GitRepositoryKind, func() string { return render.AsCode(r.Status.Artifact) }
+	case *libsveltosv1beta1.EventSource:
+		kind, content = libsveltosv1beta1.EventSourceKind, func() string { return render.AsCode(r.Spec) }
+	case *libsveltosv1beta1.EventReport:
+		kind, content = libsveltosv1beta1.EventReportKind, func() string { return render.AsCode(r.Spec) }
+	default:
+		panic(1)
+	}
+
+	return getDigestCache().getDigest(kind, resource, func() []byte {
+		return getSHA256(content())
+	})
+}
+
+// writeReferencedResources writes to h the digests of the referenced resources. Digests are
+// sorted so that the result does not depend on the order resources were fetched in.
+func writeReferencedResources(h hash.Hash, resources []client.Object) {
+	digests := make([][]byte, 0, len(resources))
+	for i := range resources {
+		// Optional resources not found
+		if resources[i] == nil {
+			continue
+		}
+		digests = append(digests, getReferencedResourceDigest(resources[i]))
+	}
+
+	sort.Slice(digests, func(i, j int) bool {
+		return bytes.Compare(digests[i], digests[j]) < 0
+	})
+
+	for i := range digests {
+		h.Write(digests[i])
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+
+	"github.com/go-logr/logr"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// An EventTrigger can define its EventSource inline (Spec.EventSource) instead of referencing one
+// by name. The inline definition is materialized as an EventSource owned by the EventTrigger, so
+// Kubernetes garbage collector removes it along with the EventTrigger. From there on it is handled
+// like any referenced EventSource.
+
+const (
+	// maxInlineEventSourceNamePrefix is the maximum number of EventTrigger name characters used
+	// in the inline EventSource name. EventSource name is used as label value, so it must not be
+	// longer than 63 characters.
+	maxInlineEventSourceNamePrefix = 40
+)
+
+// getEventSourceName returns the name of the EventSource used by the EventTrigger
+func getEventSourceName(eventTrigger *v1beta1.EventTrigger) string {
+	if eventTrigger.Spec.EventSource != nil {
+		return getInlineEventSourceName(eventTrigger)
+	}
+
+	return eventTrigger.Spec.EventSourceName
+}
+
+// getInlineEventSourceName returns the name of the EventSource materialized from the EventTrigger
+// inline EventSource
+func getInlineEventSourceName(eventTrigger *v1beta1.EventTrigger) string {
+	prefix := eventTrigger.Name
+	if len(prefix) > maxInlineEventSourceNamePrefix {
+		prefix = prefix[:maxInlineEventSourceNamePrefix]
+	}
+
+	h := sha256.Sum256([]byte(eventTrigger.Name))
+	return fmt.Sprintf("%s-%x", prefix, h[:4])
+}
+
+// reconcileInlineEventSource creates/updates the EventSource materialized from the EventTrigger
+// inline EventSource. If the EventTrigger has no inline EventSource, a previously materialized one
+// is removed.
+func reconcileInlineEventSource(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	logger logr.Logger) error {
+
+	name := getInlineEventSourceName(eventTrigger)
+	logger = logger.WithValues("eventSource", name)
+
+	currentEventSource := &libsveltosv1beta1.EventSource{}
+	err := c.Get(ctx, types.NamespacedName{Name: name}, currentEventSource)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	exists := err == nil
+
+	if eventTrigger.Spec.EventSource == nil {
+		if exists && metav1.IsControlledBy(currentEventSource, eventTrigger) {
+			logger.V(logs.LogDebug).Info("removing inline eventSource")
+			return client.IgnoreNotFound(c.Delete(ctx, currentEventSource))
+		}
+		return nil
+	}
+
+	// Copy service account labels. If set, sveltos-agent will impersonate ServiceAccount representing
+	// the tenant admin when fetching resources
+	labels := appendServiceAccountLabels(eventTrigger, map[string]string{eventTriggerNameLabel: eventTrigger.Name})
+
+	if !exists {
+		eventSource := &libsveltosv1beta1.EventSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: labels,
+				OwnerReferences: []metav1.OwnerReference{
+					{
+						APIVersion: v1beta1.GroupVersion.String(),
+						Kind:       v1beta1.EventTriggerKind,
+						Name:       eventTrigger.Name,
+						UID:        eventTrigger.UID,
+						Controller: ptr.To(true),
+					},
+				},
+			},
+			Spec: *eventTrigger.Spec.EventSource,
+		}
+		logger.V(logs.LogDebug).Info("creating inline eventSource")
+		return c.Create(ctx, eventSource)
+	}
+
+	if !metav1.IsControlledBy(currentEventSource, eventTrigger) {
+		return fmt.Errorf("EventSource %s already exists and it is not owned by EventTrigger %s",
+			name, eventTrigger.Name)
+	}
+
+	if reflect.DeepEqual(currentEventSource.Spec, *eventTrigger.Spec.EventSource) &&
+		reflect.DeepEqual(currentEventSource.Labels, labels) {
+
+		return nil
+	}
+
+	logger.V(logs.LogDebug).Info("updating inline eventSource")
+	currentEventSource.Spec = *eventTrigger.Spec.EventSource
+	currentEventSource.Labels = labels
+	return c.Update(ctx, currentEventSource)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Inline EventSource", func() {
+	var eventTrigger *v1beta1.EventTrigger
+
+	BeforeEach(func() {
+		eventTrigger = &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				UID:  types.UID(randomString()),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				EventSource: &libsveltosv1beta1.EventSourceSpec{
+					ResourceSelectors: []libsveltosv1beta1.ResourceSelector{
+						{Group: "", Version: "v1", Kind: "Service"},
+					},
+				},
+			},
+		}
+	})
+
+	It("getEventSourceName returns referenced or inline EventSource name", func() {
+		Expect(controllers.GetEventSourceName(eventTrigger)).To(
+			Equal(controllers.GetInlineEventSourceName(eventTrigger)))
+
+		eventTrigger.Spec.EventSource = nil
+		eventTrigger.Spec.EventSourceName = randomString()
+		Expect(controllers.GetEventSourceName(eventTrigger)).To(Equal(eventTrigger.Spec.EventSourceName))
+	})
+
+	It("getInlineEventSourceName returns a unique name usable as label value", func() {
+		eventTrigger.Name = strings.Repeat("a", 253)
+		name := controllers.GetInlineEventSourceName(eventTrigger)
+		Expect(len(name)).To(BeNumerically("<=", 63))
+
+		other := eventTrigger.DeepCopy()
+		other.Name = strings.Repeat("a", 252)
+		Expect(controllers.GetInlineEventSourceName(other)).ToNot(Equal(name))
+	})
+
+	It("reconcileInlineEventSource creates, updates and removes the owned EventSource", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		Expect(controllers.ReconcileInlineEventSource(context.TODO(), c, eventTrigger, logger)).To(Succeed())
+
+		eventSource := &libsveltosv1beta1.EventSource{}
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Name: controllers.GetInlineEventSourceName(eventTrigger)}, eventSource)).To(Succeed())
+		Expect(eventSource.Spec).To(Equal(*eventTrigger.Spec.EventSource))
+		Expect(metav1.IsControlledBy(eventSource, eventTrigger)).To(BeTrue())
+
+		eventTrigger.Spec.EventSource.CollectResources = true
+		Expect(controllers.ReconcileInlineEventSource(context.TODO(), c, eventTrigger, logger)).To(Succeed())
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Name: controllers.GetInlineEventSourceName(eventTrigger)}, eventSource)).To(Succeed())
+		Expect(eventSource.Spec.CollectResources).To(BeTrue())
+
+		eventTrigger.Spec.EventSource = nil
+		eventTrigger.Spec.EventSourceName = randomString()
+		Expect(controllers.ReconcileInlineEventSource(context.TODO(), c, eventTrigger, logger)).To(Succeed())
+		err := c.Get(context.TODO(),
+			types.NamespacedName{Name: controllers.GetInlineEventSourceName(eventTrigger)}, eventSource)
+		Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("reconcileInlineEventSource does not take over an EventSource it does not own", func() {
+		eventSource := &libsveltosv1beta1.EventSource{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: controllers.GetInlineEventSourceName(eventTrigger),
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(eventSource).Build()
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		Expect(controllers.ReconcileInlineEventSource(context.TODO(), c, eventTrigger, logger)).ToNot(Succeed())
+
+		eventTrigger.Spec.EventSource = nil
+		Expect(controllers.ReconcileInlineEventSource(context.TODO(), c, eventTrigger, logger)).To(Succeed())
+		Expect(c.Get(context.TODO(),
+			types.NamespacedName{Name: eventSource.Name}, eventSource)).To(Succeed())
+	})
+})
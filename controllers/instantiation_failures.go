@@ -0,0 +1,140 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// Instantiations rejected because the tenant admin is not allowed to write generated resources are
+// kept in memory per EventTrigger and source cluster, and reported in the EventTrigger Status.
+// A rejected instantiation is not recorded in the instantiation fingerprint, so it is retried (and
+// the failure recorded again) on every EventReport collection. This also rebuilds the failures after
+// a restart.
+
+var (
+	// instantiationFailures contains, per EventTrigger name, the failure per source cluster
+	instantiationFailures     = make(map[string]map[string]*v1beta1.InstantiationFailure)
+	instantiationFailuresLock = &sync.RWMutex{}
+)
+
+// setInstantiationFailure records that instantiation failed for the events in the source cluster
+func setInstantiationFailure(eventTriggerName string, cluster *corev1.ObjectReference, err error) {
+	instantiationFailuresLock.Lock()
+	defer instantiationFailuresLock.Unlock()
+
+	if _, ok := instantiationFailures[eventTriggerName]; !ok {
+		instantiationFailures[eventTriggerName] = make(map[string]*v1beta1.InstantiationFailure)
+	}
+
+	instantiationFailures[eventTriggerName][getClusterKey(cluster)] = &v1beta1.InstantiationFailure{
+		Cluster: *cluster,
+		Message: err.Error(),
+	}
+}
+
+// clearInstantiationFailure forgets any failure recorded for the events in the source cluster
+func clearInstantiationFailure(eventTriggerName string, cluster *corev1.ObjectReference) {
+	instantiationFailuresLock.Lock()
+	defer instantiationFailuresLock.Unlock()
+
+	failures, ok := instantiationFailures[eventTriggerName]
+	if !ok {
+		return
+	}
+
+	delete(failures, getClusterKey(cluster))
+	if len(failures) == 0 {
+		delete(instantiationFailures, eventTriggerName)
+	}
+}
+
+// removeInstantiationFailures forgets all failures recorded for an EventTrigger
+func removeInstantiationFailures(eventTriggerName string) {
+	instantiationFailuresLock.Lock()
+	defer instantiationFailuresLock.Unlock()
+
+	delete(instantiationFailures, eventTriggerName)
+}
+
+// getInstantiationFailures returns the failures recorded for an EventTrigger sorted by cluster
+func getInstantiationFailures(eventTriggerName string) []v1beta1.InstantiationFailure {
+	instantiationFailuresLock.RLock()
+	defer instantiationFailuresLock.RUnlock()
+
+	failures := instantiationFailures[eventTriggerName]
+	if len(failures) == 0 {
+		return nil
+	}
+
+	result := make([]v1beta1.InstantiationFailure, 0, len(failures))
+	for k := range failures {
+		result = append(result, *failures[k])
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return lessClusterRef(&result[i].Cluster, &result[j].Cluster)
+	})
+
+	return result
+}
+
+// updateInstantiationFailureStatuses updates, for each EventTrigger, Status.InstantiationFailures
+func updateInstantiationFailureStatuses(ctx context.Context, c client.Client,
+	eventTriggers *v1beta1.EventTriggerList, logger logr.Logger) {
+
+	existing := make(map[string]bool, len(eventTriggers.Items))
+	for i := range eventTriggers.Items {
+		existing[eventTriggers.Items[i].Name] = true
+	}
+
+	// Forget about deleted EventTriggers
+	instantiationFailuresLock.Lock()
+	for eventTriggerName := range instantiationFailures {
+		if !existing[eventTriggerName] {
+			delete(instantiationFailures, eventTriggerName)
+		}
+	}
+	instantiationFailuresLock.Unlock()
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+
+		current := getInstantiationFailures(et.Name)
+		if reflect.DeepEqual(et.Status.InstantiationFailures, current) {
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.InstantiationFailures = current
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventTrigger %s instantiation failures: %v",
+				et.Name, err))
+		}
+	}
+}
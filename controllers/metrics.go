@@ -38,12 +38,30 @@ var (
 			Buckets:   []float64{0.1, 0.5, 1, 5, 10, 20, 30},
 		},
 	)
+
+	orphansFoundCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "projectsveltos",
+			Name:      "eventtrigger_orphans_found_total",
+			Help:      "Number of orphaned objects generated by EventTriggers found by the orphan sweeper",
+		},
+		[]string{"kind"},
+	)
+
+	orphansRemovedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "projectsveltos",
+			Name:      "eventtrigger_orphans_removed_total",
+			Help:      "Number of orphaned objects generated by EventTriggers removed by the orphan sweeper",
+		},
+		[]string{"kind"},
+	)
 )
 
 //nolint:gochecknoinits // forced pattern, can't workaround
 func init() {
 	// Register custom metrics with the global prometheus registry
-	metrics.Registry.MustRegister(programEventTriggerDurationHistogram)
+	metrics.Registry.MustRegister(programEventTriggerDurationHistogram, orphansFoundCounter, orphansRemovedCounter)
 }
 
 func newEventTriggerHistogram(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType,
@@ -95,3 +113,11 @@ func programDuration(elapsed time.Duration, clusterNamespace, clusterName, featu
 		}
 	}
 }
+
+func orphansFound(kind string) {
+	orphansFoundCounter.WithLabelValues(kind).Inc()
+}
+
+func orphansRemoved(kind string) {
+	orphansRemovedCounter.WithLabelValues(kind).Inc()
+}
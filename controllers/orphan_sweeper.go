@@ -0,0 +1,316 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+	"github.com/projectsveltos/libsveltos/lib/sharding"
+)
+
+// orphanReason describes why an object generated by an EventTrigger is considered an orphan
+type orphanReason string
+
+const (
+	orphanReasonEventTriggerNotFound = orphanReason("EventTriggerNotFound")
+	orphanReasonClusterNotFound      = orphanReason("ClusterNotFound")
+	orphanReasonEventReportNotFound  = orphanReason("EventReportNotFound")
+)
+
+// sweepOrphans periodically inventories all ClusterProfiles, ConfigMaps, Secrets and resources generated
+// by EventTriggers and cross-checks those against existing EventTriggers, clusters and EventReports.
+// An object is an orphan when:
+// - the EventTrigger that generated it does not exist anymore;
+// - the source cluster does not exist anymore;
+// - there is no EventReport for the EventSource and source cluster.
+// Orphans are deleted unless dryRun is set, in which case they are only reported.
+// Objects whose EventReport does not exist are removed honoring EventTrigger.Spec.DeletionGracePeriod,
+// while ClusterProfiles pending approval are left to the approver.
+// Each deployment sweeps only the objects generated for clusters matching its shardKey. Objects whose
+// source cluster does not exist anymore, and objects not generated for a cluster, are swept by the
+// deployment with no shardKey as the shard of those cannot be known.
+func sweepOrphans(ctx context.Context, c client.Client, shardKey string, interval time.Duration, dryRun bool,
+	logger logr.Logger) {
+
+	logger = logger.WithValues("dryRun", dryRun)
+	for {
+		// Sleep first. This gives the cache time to sync and EventTriggers time to be reconciled
+		select {
+		case <-ctx.Done():
+			logger.V(logs.LogInfo).Info("stopping orphan sweeper")
+			return
+		case <-time.After(interval):
+		}
+
+		// When EventTriggers are sharded across replicas, a single replica sweeps
+		if !isOwnedByThisReplica("orphan-sweeper") {
+			continue
+		}
+
+		logger.V(logs.LogDebug).Info("sweeping orphans")
+		if err := sweepOrphansOnce(ctx, c, shardKey, interval, dryRun, logger); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to sweep orphans: %v", err))
+		}
+	}
+}
+
+// StartOrphanSweeper starts the orphan sweeper in the background. An interval of zero disables it.
+// Only objects generated for clusters matching shardKey are swept.
+func StartOrphanSweeper(ctx context.Context, c client.Client, shardKey string, interval time.Duration,
+	dryRun bool, logger logr.Logger) {
+
+	if interval == 0 {
+		logger.V(logs.LogInfo).Info("orphan sweeper disabled")
+		return
+	}
+
+	go sweepOrphans(ctx, c, shardKey, interval, dryRun, logger)
+}
+
+func sweepOrphansOnce(ctx context.Context, c client.Client, shardKey string, minAge time.Duration,
+	dryRun bool, logger logr.Logger) error {
+
+	listOptions := []client.ListOption{
+		client.HasLabels{eventTriggerNameLabel},
+	}
+
+	clusterProfiles := &configv1beta1.ClusterProfileList{}
+	if err := c.List(ctx, clusterProfiles, listOptions...); err != nil {
+		return err
+	}
+	for i := range clusterProfiles.Items {
+		if err := processPossibleOrphan(ctx, c, shardKey, &clusterProfiles.Items[i], minAge, dryRun, logger); err != nil {
+			return err
+		}
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.List(ctx, configMaps, listOptions...); err != nil {
+		return err
+	}
+	for i := range configMaps.Items {
+		if err := processPossibleOrphan(ctx, c, shardKey, &configMaps.Items[i], minAge, dryRun, logger); err != nil {
+			return err
+		}
+	}
+
+	secrets := &corev1.SecretList{}
+	if err := c.List(ctx, secrets, listOptions...); err != nil {
+		return err
+	}
+	for i := range secrets.Items {
+		if err := processPossibleOrphan(ctx, c, shardKey, &secrets.Items[i], minAge, dryRun, logger); err != nil {
+			return err
+		}
+	}
+
+	resources, err := listResourceGeneratorObjects(ctx, c, logger)
+	if err != nil {
+		return err
+	}
+	for i := range resources {
+		if err := processPossibleOrphan(ctx, c, shardKey, &resources[i], minAge, dryRun, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// listResourceGeneratorObjects lists all resources generated by ResourceGenerators. Those can be of any
+// kind, so all kinds recorded by existing EventTriggers are listed. Resources generated by a deleted
+// EventTrigger are removed by the garbage collector as they are owned by it.
+func listResourceGeneratorObjects(ctx context.Context, c client.Client, logger logr.Logger,
+) ([]unstructured.Unstructured, error) {
+
+	eventTriggers := &v1beta1.EventTriggerList{}
+	if err := c.List(ctx, eventTriggers); err != nil {
+		return nil, err
+	}
+
+	var gvks []schema.GroupVersionKind
+	for i := range eventTriggers.Items {
+		for _, gvk := range getGeneratedResourceKinds(&eventTriggers.Items[i]) {
+			// ConfigMaps and Secrets are already inventoried
+			if gvk.Group == "" && (gvk.Kind == string(libsveltosv1beta1.ConfigMapReferencedResourceKind) ||
+				gvk.Kind == string(libsveltosv1beta1.SecretReferencedResourceKind)) {
+
+				continue
+			}
+			gvks = appendGVK(gvks, gvk)
+		}
+	}
+
+	return listGeneratedResources(ctx, c, gvks, map[string]string{generatorLabel: "ok"}, logger)
+}
+
+func processPossibleOrphan(ctx context.Context, c client.Client, shardKey string, object client.Object,
+	minAge time.Duration, dryRun bool, logger logr.Logger) error {
+
+	if !object.GetDeletionTimestamp().IsZero() {
+		return nil
+	}
+
+	// Skip recently created objects. Those might have been generated while this sweep is running
+	if time.Since(object.GetCreationTimestamp().Time) < minAge {
+		return nil
+	}
+
+	reason, eventTrigger, err := isOrphan(ctx, c, shardKey, object)
+	if err != nil {
+		return err
+	}
+	if reason == nil {
+		return nil
+	}
+
+	addTypeInformationToObject(c.Scheme(), object)
+	kind := object.GetObjectKind().GroupVersionKind().Kind
+	l := logger.WithValues("kind", kind, "namespace", object.GetNamespace(), "name", object.GetName(),
+		"reason", string(*reason))
+	l.V(logs.LogInfo).Info("found orphan")
+	orphansFound(kind)
+
+	if dryRun {
+		return nil
+	}
+
+	if *reason == orphanReasonEventReportNotFound {
+		remove, err := canOrphanBeRemoved(ctx, c, eventTrigger, object, l)
+		if err != nil || !remove {
+			return err
+		}
+	}
+
+	err = c.Delete(ctx, object)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		l.V(logs.LogInfo).Info(fmt.Sprintf("failed to delete orphan: %v", err))
+		return err
+	}
+
+	l.V(logs.LogInfo).Info("removed orphan")
+	orphansRemoved(kind)
+	return nil
+}
+
+// canOrphanBeRemoved returns true if object, whose EventReport does not exist, can be deleted now.
+// ClusterProfiles pending approval are never removed. Otherwise EventTrigger.Spec.DeletionGracePeriod
+// is honored.
+func canOrphanBeRemoved(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	object client.Object, logger logr.Logger) (bool, error) {
+
+	if _, ok := object.(*configv1beta1.ClusterProfile); ok {
+		if _, ok := object.GetAnnotations()[pendingApprovalAnnotation]; ok {
+			logger.V(logs.LogDebug).Info("ClusterProfile is pending approval")
+			return false, nil
+		}
+	}
+
+	return isGracePeriodElapsed(ctx, c, eventTrigger, object, logger)
+}
+
+// isOrphan returns the reason why object is an orphan. Nil if object is not an orphan or if object
+// must be swept by a deployment with a different shardKey. When object is an orphan because its
+// EventReport does not exist, the EventTrigger which generated it is also returned.
+// Any error other than NotFound (for instance CAPI not installed) means object is not
+// considered an orphan.
+func isOrphan(ctx context.Context, c client.Client, shardKey string, object client.Object,
+) (*orphanReason, *v1beta1.EventTrigger, error) {
+
+	lbls := object.GetLabels()
+
+	clusterNamespace, hasNamespace := lbls[clusterNamespaceLabel]
+	clusterName, hasName := lbls[clusterNameLabel]
+	clusterType := libsveltosv1beta1.ClusterType(lbls[clusterTypeLabel])
+	if !hasNamespace || !hasName {
+		// Not generated for a cluster
+		if shardKey != "" {
+			return nil, nil, nil
+		}
+		clusterName = ""
+	}
+
+	clusterFound := false
+	if clusterName != "" {
+		cluster, err := clusterproxy.GetCluster(ctx, c, clusterNamespace, clusterName, clusterType)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, nil
+			}
+			if shardKey != "" {
+				// Shard of a cluster which does not exist is unknown
+				return nil, nil, nil
+			}
+		} else {
+			if !sharding.IsShardAMatch(shardKey, cluster) {
+				return nil, nil, nil
+			}
+			clusterFound = true
+		}
+	}
+
+	eventTrigger := &v1beta1.EventTrigger{}
+	err := c.Get(ctx, client.ObjectKey{Name: lbls[eventTriggerNameLabel]}, eventTrigger)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			reason := orphanReasonEventTriggerNotFound
+			return &reason, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	if clusterName == "" {
+		return nil, nil, nil
+	}
+	if !clusterFound {
+		reason := orphanReasonClusterNotFound
+		return &reason, nil, nil
+	}
+
+	eventSourceName, ok := lbls[eventSourceNameLabel]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	eventReports, err := fetchEventReports(ctx, c, clusterNamespace, clusterName, eventSourceName, clusterType)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(eventReports.Items) == 0 {
+		reason := orphanReasonEventReportNotFound
+		return &reason, eventTrigger, nil
+	}
+
+	return nil, nil, nil
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/sharding"
+)
+
+var _ = Describe("Orphan sweeper", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("sweepOrphansOnce removes objects whose EventTrigger or cluster does not exist", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		sveltosCluster := &libsveltosv1beta1.SveltosCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+		}
+
+		// EventTrigger and cluster exist. Labels do not include EventSource so not an orphan
+		validClusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(sveltosCluster.Namespace, sveltosCluster.Name,
+					eventTrigger.Name, nil, libsveltosv1beta1.ClusterTypeSveltos),
+			},
+		}
+
+		// EventTrigger does not exist
+		noEventTriggerClusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(sveltosCluster.Namespace, sveltosCluster.Name,
+					randomString(), nil, libsveltosv1beta1.ClusterTypeSveltos),
+			},
+		}
+
+		// Cluster does not exist
+		noClusterClusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: controllers.GetInstantiatedObjectLabels(randomString(), randomString(),
+					eventTrigger.Name, nil, libsveltosv1beta1.ClusterTypeSveltos),
+			},
+		}
+
+		// Not generated by an EventTrigger
+		clusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		initObjects := []client.Object{
+			eventTrigger, sveltosCluster, validClusterProfile, noEventTriggerClusterProfile,
+			noClusterClusterProfile, clusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		// In dry run mode, nothing is removed
+		Expect(controllers.SweepOrphansOnce(context.TODO(), c, "", 0, true, logger)).To(Succeed())
+		clusterProfiles := &configv1beta1.ClusterProfileList{}
+		Expect(c.List(context.TODO(), clusterProfiles)).To(Succeed())
+		Expect(len(clusterProfiles.Items)).To(Equal(4))
+
+		Expect(controllers.SweepOrphansOnce(context.TODO(), c, "", 0, false, logger)).To(Succeed())
+		Expect(c.List(context.TODO(), clusterProfiles)).To(Succeed())
+		Expect(len(clusterProfiles.Items)).To(Equal(2))
+		names := []string{clusterProfiles.Items[0].Name, clusterProfiles.Items[1].Name}
+		Expect(names).To(ContainElement(validClusterProfile.Name))
+		Expect(names).To(ContainElement(clusterProfile.Name))
+	})
+
+	It("sweepOrphansOnce honors shard key, deletion grace period and pending approvals", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				DeletionGracePeriod: &metav1.Duration{Duration: time.Hour},
+			},
+		}
+
+		shardKey := randomString()
+		shardCluster := &libsveltosv1beta1.SveltosCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   randomString(),
+				Name:        randomString(),
+				Annotations: map[string]string{sharding.ShardAnnotation: shardKey},
+			},
+		}
+		otherCluster := &libsveltosv1beta1.SveltosCluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+		}
+
+		// No EventReport exists for this EventSource
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{libsveltosv1beta1.EventSourceNameLabel: randomString()},
+			},
+		}
+
+		getClusterProfile := func(cluster *libsveltosv1beta1.SveltosCluster) *configv1beta1.ClusterProfile {
+			lbls := controllers.GetInstantiatedObjectLabels(cluster.Namespace, cluster.Name,
+				eventTrigger.Name, eventReport, libsveltosv1beta1.ClusterTypeSveltos)
+			lbls["eventtrigger.lib.projectsveltos.io/eventsourcename"] =
+				eventReport.Labels[libsveltosv1beta1.EventSourceNameLabel]
+			return &configv1beta1.ClusterProfile{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   randomString(),
+					Labels: lbls,
+				},
+			}
+		}
+
+		shardClusterProfile := getClusterProfile(shardCluster)
+		pendingApprovalClusterProfile := getClusterProfile(shardCluster)
+		pendingApprovalClusterProfile.Annotations = map[string]string{
+			"eventtrigger.lib.projectsveltos.io/pending-approval": randomString(),
+		}
+		otherClusterProfile := getClusterProfile(otherCluster)
+
+		initObjects := []client.Object{
+			eventTrigger, shardCluster, otherCluster, shardClusterProfile, pendingApprovalClusterProfile,
+			otherClusterProfile,
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(initObjects...).Build()
+
+		Expect(controllers.SweepOrphansOnce(context.TODO(), c, shardKey, 0, false, logger)).To(Succeed())
+
+		// Nothing is removed before the grace period elapses. Only the ClusterProfile for the
+		// cluster in the shard, and not pending approval, is marked for removal.
+		clusterProfiles := &configv1beta1.ClusterProfileList{}
+		Expect(c.List(context.TODO(), clusterProfiles)).To(Succeed())
+		Expect(len(clusterProfiles.Items)).To(Equal(3))
+
+		currentClusterProfile := &configv1beta1.ClusterProfile{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: shardClusterProfile.Name},
+			currentClusterProfile)).To(Succeed())
+		Expect(currentClusterProfile.Annotations).To(HaveKey("eventtrigger.lib.projectsveltos.io/pending-removal"))
+
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: pendingApprovalClusterProfile.Name},
+			currentClusterProfile)).To(Succeed())
+		Expect(currentClusterProfile.Annotations).ToNot(HaveKey("eventtrigger.lib.projectsveltos.io/pending-removal"))
+
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: otherClusterProfile.Name},
+			currentClusterProfile)).To(Succeed())
+		Expect(currentClusterProfile.Annotations).ToNot(HaveKey("eventtrigger.lib.projectsveltos.io/pending-removal"))
+	})
+})
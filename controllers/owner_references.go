@@ -0,0 +1,194 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// Objects generated by an EventTrigger (ClusterProfiles, ConfigMaps, Secrets and resources
+// created from ResourceGenerators) are owned by the EventTrigger. Instantiated ConfigMaps/Secrets
+// referenced by a ClusterProfile are also owned by such ClusterProfile.
+// Stale objects are removed by removeInstantiatedResources. Owner references guarantee that,
+// even when that does not happen (for instance event-manager is down when EventTrigger is deleted),
+// Kubernetes garbage collector removes generated objects.
+
+// getEventTriggerOwnerReferences returns the OwnerReferences to set on objects generated
+// by the EventTrigger.
+func getEventTriggerOwnerReferences(eventTrigger *v1beta1.EventTrigger) []metav1.OwnerReference {
+	if eventTrigger == nil || eventTrigger.UID == "" {
+		return nil
+	}
+
+	return []metav1.OwnerReference{
+		{
+			APIVersion: v1beta1.GroupVersion.String(),
+			Kind:       v1beta1.EventTriggerKind,
+			Name:       eventTrigger.Name,
+			UID:        eventTrigger.UID,
+		},
+	}
+}
+
+// appendOwnerReferences appends toAdd to current skipping OwnerReferences already present
+func appendOwnerReferences(current, toAdd []metav1.OwnerReference) []metav1.OwnerReference {
+	for i := range toAdd {
+		if !hasOwnerReference(current, &toAdd[i]) {
+			current = append(current, toAdd[i])
+		}
+	}
+	return current
+}
+
+func hasOwnerReference(ownerReferences []metav1.OwnerReference, ownerReference *metav1.OwnerReference) bool {
+	for i := range ownerReferences {
+		if ownerReferences[i].UID == ownerReference.UID {
+			return true
+		}
+	}
+	return false
+}
+
+// addClusterProfileOwnerReferences sets each ClusterProfile as owner of the ConfigMaps/Secrets
+// instantiated by the EventTrigger and referenced by the ClusterProfile (in PolicyRefs and ValuesFrom).
+// ConfigMaps/Secrets not instantiated by the EventTrigger are never modified.
+func addClusterProfileOwnerReferences(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	clusterProfiles []*configv1beta1.ClusterProfile, logger logr.Logger) error {
+
+	owners := make(map[libsveltosv1beta1.PolicyRef][]metav1.OwnerReference)
+	for i := range clusterProfiles {
+		cp := clusterProfiles[i]
+		if cp.UID == "" {
+			continue
+		}
+
+		ownerReference := metav1.OwnerReference{
+			APIVersion: configv1beta1.GroupVersion.String(),
+			Kind:       configv1beta1.ClusterProfileKind,
+			Name:       cp.Name,
+			UID:        cp.UID,
+		}
+
+		policyRefs := make(map[libsveltosv1beta1.PolicyRef]bool)
+		for j := range cp.Spec.PolicyRefs {
+			policyRefs[libsveltosv1beta1.PolicyRef{
+				Namespace: cp.Spec.PolicyRefs[j].Namespace,
+				Name:      cp.Spec.PolicyRefs[j].Name,
+				Kind:      cp.Spec.PolicyRefs[j].Kind,
+			}] = true
+		}
+		policyRefs = appendHelmChartValuesFrom(policyRefs, cp.Spec.HelmCharts)
+		policyRefs = appendKustomizationRefValuesFrom(policyRefs, cp.Spec.KustomizationRefs)
+
+		for ref := range policyRefs {
+			owners[ref] = append(owners[ref], ownerReference)
+		}
+	}
+
+	for ref := range owners {
+		if err := addOwnerReferences(ctx, c, eventTrigger, &ref, owners[ref], logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addOwnerReferences adds ownerReferences to the referenced ConfigMap/Secret if it was instantiated
+// by eventTrigger
+func addOwnerReferences(ctx context.Context, c client.Client, eventTrigger *v1beta1.EventTrigger,
+	ref *libsveltosv1beta1.PolicyRef, ownerReferences []metav1.OwnerReference, logger logr.Logger) error {
+
+	var object client.Object
+	switch ref.Kind {
+	case string(libsveltosv1beta1.ConfigMapReferencedResourceKind):
+		object = &corev1.ConfigMap{}
+	case string(libsveltosv1beta1.SecretReferencedResourceKind):
+		object = &corev1.Secret{}
+	default:
+		return nil
+	}
+
+	err := getInstantiatedObject(ctx, c, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, object)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if object.GetLabels()[eventTriggerNameLabel] != eventTrigger.Name {
+		return nil
+	}
+
+	current := object.GetOwnerReferences()
+	updated := appendOwnerReferences(current, ownerReferences)
+	if len(updated) == len(current) {
+		return nil
+	}
+
+	logger.V(logs.LogDebug).Info(fmt.Sprintf("set ClusterProfile as owner of %s %s/%s",
+		ref.Kind, ref.Namespace, ref.Name))
+	object.SetOwnerReferences(updated)
+
+	err = c.Update(ctx, object)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// getInstantiatedObject gets a ConfigMap/Secret using the cached client. ConfigMaps/Secrets might
+// have just been instantiated, so if not in the cache yet, the object is fetched from the API server.
+func getInstantiatedObject(ctx context.Context, c client.Client, key types.NamespacedName,
+	object client.Object) error {
+
+	err := c.Get(ctx, key, object)
+	if err == nil || !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	addTypeInformationToObject(c.Scheme(), object)
+	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig, object.GetObjectKind().GroupVersionKind(),
+		key.Namespace)
+	if err != nil {
+		return err
+	}
+
+	u, err := dr.Get(ctx, key.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(u.UnstructuredContent(), object)
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("Owner references", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("getEventTriggerOwnerReferences returns EventTrigger OwnerReference", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		// No UID, no OwnerReference
+		Expect(controllers.GetEventTriggerOwnerReferences(eventTrigger)).To(BeNil())
+
+		eventTrigger.UID = types.UID(randomString())
+		ownerReferences := controllers.GetEventTriggerOwnerReferences(eventTrigger)
+		Expect(len(ownerReferences)).To(Equal(1))
+		Expect(ownerReferences[0].Kind).To(Equal(v1beta1.EventTriggerKind))
+		Expect(ownerReferences[0].Name).To(Equal(eventTrigger.Name))
+		Expect(ownerReferences[0].UID).To(Equal(eventTrigger.UID))
+
+		// Appending same OwnerReference twice is a no-op
+		ownerReferences = controllers.AppendOwnerReferences(ownerReferences,
+			controllers.GetEventTriggerOwnerReferences(eventTrigger))
+		Expect(len(ownerReferences)).To(Equal(1))
+	})
+
+	It("addClusterProfileOwnerReferences sets ClusterProfile as owner of instantiated ConfigMaps only", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+
+		namespace := randomString()
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: namespace,
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), ns)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, ns)).To(Succeed())
+
+		// ConfigMap instantiated by the EventTrigger
+		instantiated := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      randomString(),
+				Labels: map[string]string{
+					"eventtrigger.lib.projectsveltos.io/eventtriggername": eventTrigger.Name,
+				},
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), instantiated)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, instantiated)).To(Succeed())
+
+		// ConfigMap directly referenced (not a template)
+		referenced := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      randomString(),
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), referenced)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, referenced)).To(Succeed())
+
+		clusterProfile := &configv1beta1.ClusterProfile{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: configv1beta1.Spec{
+				PolicyRefs: []configv1beta1.PolicyRef{
+					{Namespace: namespace, Name: instantiated.Name, Kind: "ConfigMap"},
+					{Namespace: namespace, Name: referenced.Name, Kind: "ConfigMap"},
+				},
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), clusterProfile)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, clusterProfile)).To(Succeed())
+
+		Expect(controllers.AddClusterProfileOwnerReferences(context.TODO(), testEnv.Client, eventTrigger,
+			[]*configv1beta1.ClusterProfile{clusterProfile}, logger)).To(Succeed())
+
+		Eventually(func() bool {
+			currentConfigMap := &corev1.ConfigMap{}
+			err := testEnv.Get(context.TODO(),
+				types.NamespacedName{Namespace: namespace, Name: instantiated.Name}, currentConfigMap)
+			if err != nil {
+				return false
+			}
+			for i := range currentConfigMap.OwnerReferences {
+				if currentConfigMap.OwnerReferences[i].UID == clusterProfile.UID {
+					return true
+				}
+			}
+			return false
+		}, timeout, pollingInterval).Should(BeTrue())
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Expect(testEnv.Get(context.TODO(),
+			types.NamespacedName{Namespace: namespace, Name: referenced.Name}, currentConfigMap)).To(Succeed())
+		Expect(currentConfigMap.OwnerReferences).To(BeEmpty())
+	})
+})
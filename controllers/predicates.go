@@ -25,6 +25,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
 )
@@ -300,6 +301,100 @@ func SecretPredicates(logger logr.Logger) predicate.Funcs {
 	}
 }
 
+// ClusterProfilePredicates predicates for ClusterProfiles generated by EventTriggers.
+// EventTriggerReconciler watches ClusterProfile events and react to those by reconciling
+// itself based on following predicates
+func ClusterProfilePredicates(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			newClusterProfile := e.ObjectNew.(*configv1beta1.ClusterProfile)
+			oldClusterProfile := e.ObjectOld.(*configv1beta1.ClusterProfile)
+			log := logger.WithValues("predicate", "updateEvent",
+				"clusterprofile", newClusterProfile.Name,
+			)
+
+			if _, ok := newClusterProfile.Labels[eventTriggerNameLabel]; !ok {
+				return false
+			}
+
+			if oldClusterProfile == nil {
+				log.V(logs.LogVerbose).Info("Old ClusterProfile is nil. Reconcile EventTrigger.")
+				return true
+			}
+
+			if !reflect.DeepEqual(oldClusterProfile.Status.MatchingClusterRefs,
+				newClusterProfile.Status.MatchingClusterRefs) {
+
+				log.V(logs.LogVerbose).Info(
+					"ClusterProfile MatchingClusterRefs changed. Will attempt to reconcile associated EventTrigger.",
+				)
+				return true
+			}
+
+			// otherwise, return false
+			log.V(logs.LogVerbose).Info(
+				"ClusterProfile did not match expected conditions.  Will not attempt to reconcile associated EventTrigger.")
+			return false
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			if _, ok := e.Object.GetLabels()[eventTriggerNameLabel]; !ok {
+				return false
+			}
+			return CreateFuncTrue(e, logger)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			if _, ok := e.Object.GetLabels()[eventTriggerNameLabel]; !ok {
+				return false
+			}
+			return DeleteFuncTrue(e, logger)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return GenericFuncFalse(e, logger)
+		},
+	}
+}
+
+// ClusterSummaryPredicates predicates for ClusterSummaries. EventTriggerReconciler watches
+// ClusterSummary events and react to those by reconciling itself based on following predicates
+func ClusterSummaryPredicates(logger logr.Logger) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			newClusterSummary := e.ObjectNew.(*configv1beta1.ClusterSummary)
+			oldClusterSummary := e.ObjectOld.(*configv1beta1.ClusterSummary)
+			log := logger.WithValues("predicate", "updateEvent",
+				"namespace", newClusterSummary.Namespace,
+				"clustersummary", newClusterSummary.Name,
+			)
+
+			if oldClusterSummary == nil {
+				log.V(logs.LogVerbose).Info("Old ClusterSummary is nil. Reconcile EventTrigger.")
+				return true
+			}
+
+			if !reflect.DeepEqual(getDestinationStatus(oldClusterSummary), getDestinationStatus(newClusterSummary)) {
+				log.V(logs.LogVerbose).Info(
+					"ClusterSummary deployment status changed. Will attempt to reconcile associated EventTrigger.",
+				)
+				return true
+			}
+
+			// otherwise, return false
+			log.V(logs.LogVerbose).Info(
+				"ClusterSummary did not match expected conditions.  Will not attempt to reconcile associated EventTrigger.")
+			return false
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return CreateFuncTrue(e, logger)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return DeleteFuncTrue(e, logger)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return GenericFuncFalse(e, logger)
+		},
+	}
+}
+
 var (
 	CreateFuncTrue = func(e event.CreateEvent, logger logr.Logger) bool {
 		log := logger.WithValues("predicate", "createEvent",
@@ -0,0 +1,284 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// Both the collection loop and the deployer workers need a client to access managed clusters.
+// Building a client for every cluster on every collection iteration means a new TLS handshake
+// and new discovery calls each time. remoteClientCache keeps one client per managed cluster.
+// A cached client is rebuilt when the cluster kubeconfig changes (kubeconfig rotation) and is
+// evicted after not being used for remoteClientIdleTimeout.
+// The collection loop lists EventReports of every managed cluster every few seconds. Along with
+// the client, an informer cache is kept per managed cluster so those lists are served locally.
+// The informer cache is started the first time EventReports are read from the cluster and is
+// stopped when the entry is rebuilt or evicted.
+
+var (
+	// remoteClientIdleTimeout is how long a cached client can go unused before being evicted.
+	// Zero disables caching.
+	remoteClientIdleTimeout = 10 * time.Minute
+)
+
+const (
+	// eventReportSyncTimeout bounds how long listing EventReports waits for the informer cache of
+	// a managed cluster to sync, so an unreachable cluster does not block collection for the others
+	eventReportSyncTimeout = 30 * time.Second
+)
+
+type remoteClientEntry struct {
+	client client.Client
+	config *rest.Config
+	// eventReportCache is the informer cache used to read EventReports. Nil till first used.
+	eventReportCache cache.Cache
+	// stopCache stops eventReportCache
+	stopCache context.CancelFunc
+	// kubeconfigHash is the hash of the kubeconfig used to build client
+	kubeconfigHash []byte
+	lastUsed       time.Time
+}
+
+type remoteClientCache struct {
+	mux sync.Mutex
+
+	// key: cluster (see getRemoteClientCacheKey)
+	entries   map[string]*remoteClientEntry
+	lastSweep time.Time
+}
+
+var (
+	remoteClientCacheInstance *remoteClientCache
+	remoteClientCacheLock     = &sync.Mutex{}
+)
+
+func SetRemoteClientIdleTimeout(timeout time.Duration) {
+	remoteClientIdleTimeout = timeout
+}
+
+func getRemoteClientCache() *remoteClientCache {
+	if remoteClientCacheInstance == nil {
+		remoteClientCacheLock.Lock()
+		defer remoteClientCacheLock.Unlock()
+		if remoteClientCacheInstance == nil {
+			remoteClientCacheInstance = &remoteClientCache{
+				entries: make(map[string]*remoteClientEntry),
+			}
+		}
+	}
+
+	return remoteClientCacheInstance
+}
+
+func getRemoteClientCacheKey(clusterNamespace, clusterName string, clusterType libsveltosv1beta1.ClusterType) string {
+	return fmt.Sprintf("%s:%s/%s", clusterType, clusterNamespace, clusterName)
+}
+
+// getRemoteClient returns a client to access the managed cluster. c is the management cluster client.
+// Same as clusterproxy.GetKubernetesClient, nil is returned for SveltosClusters in pull mode.
+func getRemoteClient(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) (client.Client, error) {
+
+	if remoteClientIdleTimeout == 0 {
+		return clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName, "", "",
+			clusterType, logger)
+	}
+
+	entry, err := getRemoteClientEntry(ctx, c, clusterNamespace, clusterName, clusterType, logger)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	return entry.client, nil
+}
+
+// getRemoteEventReportReader returns a reader for the EventReports in the managed cluster.
+// Reads are served by an informer cache. c is the management cluster client.
+// Same as getRemoteClient, nil is returned for SveltosClusters in pull mode.
+func getRemoteEventReportReader(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) (client.Reader, error) {
+
+	if remoteClientIdleTimeout == 0 {
+		return clusterproxy.GetKubernetesClient(ctx, c, clusterNamespace, clusterName, "", "",
+			clusterType, logger)
+	}
+
+	entry, err := getRemoteClientEntry(ctx, c, clusterNamespace, clusterName, clusterType, logger)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+
+	return getRemoteClientCache().getEventReportCache(entry, c.Scheme(), logger)
+}
+
+// getRemoteClientEntry returns the cache entry for the managed cluster, creating it if needed.
+// Nil is returned for SveltosClusters in pull mode.
+func getRemoteClientEntry(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) (*remoteClientEntry, error) {
+
+	isPullMode, err := clusterproxy.IsClusterInPullMode(ctx, c, clusterNamespace, clusterName,
+		clusterType, logger)
+	if err != nil {
+		return nil, err
+	}
+	if isPullMode {
+		return nil, nil
+	}
+
+	// Reading the kubeconfig goes through the management cluster client cache. The kubeconfig
+	// content is used to detect rotations.
+	kubeconfig, err := clusterproxy.GetSecretData(ctx, c, clusterNamespace, clusterName, "", "",
+		clusterType, logger)
+	if err != nil {
+		return nil, err
+	}
+	h := sha256.Sum256(kubeconfig)
+	kubeconfigHash := h[:]
+
+	clientCache := getRemoteClientCache()
+	key := getRemoteClientCacheKey(clusterNamespace, clusterName, clusterType)
+	if entry := clientCache.get(key, kubeconfigHash, time.Now()); entry != nil {
+		return entry, nil
+	}
+
+	logger.V(logs.LogDebug).Info("creating client for managed cluster")
+	config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	remoteClient, err := client.New(config, client.Options{Scheme: c.Scheme()})
+	if err != nil {
+		return nil, err
+	}
+
+	return clientCache.set(key, remoteClient, config, kubeconfigHash, time.Now()), nil
+}
+
+// get returns the cached entry for key if it was built with the given kubeconfig.
+// Idle entries are evicted.
+func (rc *remoteClientCache) get(key string, kubeconfigHash []byte, now time.Time) *remoteClientEntry {
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	rc.evictIdle(now)
+
+	entry, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+
+	if !reflect.DeepEqual(entry.kubeconfigHash, kubeconfigHash) {
+		// kubeconfig was rotated
+		entry.stop()
+		delete(rc.entries, key)
+		return nil
+	}
+
+	entry.lastUsed = now
+	return entry
+}
+
+func (rc *remoteClientCache) set(key string, remoteClient client.Client, config *rest.Config,
+	kubeconfigHash []byte, now time.Time) *remoteClientEntry {
+
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	if old, ok := rc.entries[key]; ok {
+		old.stop()
+	}
+
+	entry := &remoteClientEntry{
+		client:         remoteClient,
+		config:         config,
+		kubeconfigHash: kubeconfigHash,
+		lastUsed:       now,
+	}
+	rc.entries[key] = entry
+	return entry
+}
+
+// getEventReportCache returns the informer cache of entry, starting it if not started yet.
+// Informer for EventReports is created, and synced, by the first list.
+func (rc *remoteClientCache) getEventReportCache(entry *remoteClientEntry, s *runtime.Scheme,
+	logger logr.Logger) (cache.Cache, error) {
+
+	rc.mux.Lock()
+	defer rc.mux.Unlock()
+
+	if entry.eventReportCache != nil {
+		return entry.eventReportCache, nil
+	}
+
+	logger.V(logs.LogDebug).Info("starting EventReport cache for managed cluster")
+	eventReportCache, err := cache.New(entry.config, cache.Options{Scheme: s})
+	if err != nil {
+		return nil, err
+	}
+
+	cacheCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		if err := eventReportCache.Start(cacheCtx); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("EventReport cache stopped. Err: %v", err))
+		}
+	}()
+
+	entry.eventReportCache = eventReportCache
+	entry.stopCache = cancel
+	return eventReportCache, nil
+}
+
+// stop stops the informer cache, if any. Must be called with remoteClientCache lock held.
+func (e *remoteClientEntry) stop() {
+	if e.stopCache != nil {
+		e.stopCache()
+	}
+}
+
+// evictIdle removes entries not used for remoteClientIdleTimeout. In order to not walk
+// all entries on every access, idle entries are looked for at most once a minute.
+// Must be called with lock held.
+func (rc *remoteClientCache) evictIdle(now time.Time) {
+	if now.Sub(rc.lastSweep) < time.Minute {
+		return
+	}
+	rc.lastSweep = now
+
+	for key, entry := range rc.entries {
+		if now.Sub(entry.lastUsed) > remoteClientIdleTimeout {
+			entry.stop()
+			delete(rc.entries, key)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("Remote client cache", func() {
+	It("get returns cached entry only while kubeconfig is unchanged", func() {
+		remoteClientCache := controllers.GetRemoteClientCache()
+		key := randomString()
+		kubeconfigHash := []byte(randomString())
+		remoteClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		now := time.Now()
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, kubeconfigHash, now)).To(BeNil())
+
+		entry := controllers.RemoteClientCacheSet(remoteClientCache, key, remoteClient, nil, kubeconfigHash, now)
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, kubeconfigHash, now)).To(Equal(entry))
+
+		// kubeconfig was rotated
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, []byte(randomString()), now)).To(BeNil())
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, kubeconfigHash, now)).To(BeNil())
+	})
+
+	It("get evicts idle entries", func() {
+		remoteClientCache := controllers.GetRemoteClientCache()
+		key := randomString()
+		kubeconfigHash := []byte(randomString())
+		remoteClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		now := time.Now()
+		entry := controllers.RemoteClientCacheSet(remoteClientCache, key, remoteClient, nil, kubeconfigHash, now)
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, kubeconfigHash,
+			now.Add(time.Minute))).To(Equal(entry))
+
+		later := now.Add(24 * time.Hour)
+		Expect(controllers.RemoteClientCacheGet(remoteClientCache, key, kubeconfigHash, later)).To(BeNil())
+	})
+})
@@ -0,0 +1,233 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+	libsveltosset "github.com/projectsveltos/libsveltos/lib/set"
+)
+
+// reprocessRequest tracks, during a collection iteration, a reprocess request made
+// by setting the ReprocessAnnotation on an EventTrigger
+type reprocessRequest struct {
+	request string
+	all     bool
+	// requested clusters (namespace/name). Used only if all is false
+	clusters map[string]bool
+
+	// clusters whose EventReports were successfully processed in this iteration
+	processed libsveltosset.Set
+	// clusters where processing failed in this iteration
+	failed libsveltosset.Set
+}
+
+// getReprocessRequests returns, per EventTrigger name, the pending reprocess requests
+func getReprocessRequests(eventTriggers *v1beta1.EventTriggerList) map[string]*reprocessRequest {
+	reprocessRequests := make(map[string]*reprocessRequest)
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+		if !et.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		value, ok := et.Annotations[v1beta1.ReprocessAnnotation]
+		if !ok {
+			continue
+		}
+
+		reprocessRequests[et.Name] = parseReprocessRequest(value)
+	}
+
+	return reprocessRequests
+}
+
+func parseReprocessRequest(value string) *reprocessRequest {
+	request := &reprocessRequest{
+		request:  value,
+		clusters: make(map[string]bool),
+	}
+
+	for _, cluster := range strings.Split(value, ",") {
+		cluster = strings.TrimSpace(cluster)
+		if cluster == "" {
+			continue
+		}
+		if cluster == v1beta1.ReprocessAll {
+			request.all = true
+			continue
+		}
+		request.clusters[cluster] = true
+	}
+
+	return request
+}
+
+// isClusterRequested returns true if the request includes the cluster
+func (r *reprocessRequest) isClusterRequested(cluster *corev1.ObjectReference) bool {
+	if r == nil {
+		return false
+	}
+
+	return r.all || r.clusters[fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)]
+}
+
+func (r *reprocessRequest) setProcessed(cluster *corev1.ObjectReference) {
+	if !r.isClusterRequested(cluster) || r.failed.Has(cluster) {
+		return
+	}
+	r.processed.Insert(cluster)
+}
+
+func (r *reprocessRequest) setFailed(cluster *corev1.ObjectReference) {
+	if !r.isClusterRequested(cluster) {
+		return
+	}
+	r.failed.Insert(cluster)
+}
+
+// isReprocessRequested returns true if any of the eventTriggers has a pending reprocess request
+// for the cluster
+func isReprocessRequested(reprocessRequests map[string]*reprocessRequest, eventTriggers []*v1beta1.EventTrigger,
+	cluster *corev1.ObjectReference) bool {
+
+	for i := range eventTriggers {
+		if reprocessRequests[eventTriggers[i].Name].isClusterRequested(cluster) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// setClusterReprocessed marks the cluster as processed for all reprocess requests not failed
+// in this iteration
+func setClusterReprocessed(reprocessRequests map[string]*reprocessRequest, cluster *corev1.ObjectReference) {
+	for _, request := range reprocessRequests {
+		request.setProcessed(cluster)
+	}
+}
+
+// setClusterReprocessFailed marks reprocess requests of eventTriggers as failed for the cluster
+func setClusterReprocessFailed(reprocessRequests map[string]*reprocessRequest, eventTriggers []*v1beta1.EventTrigger,
+	cluster *corev1.ObjectReference) {
+
+	for i := range eventTriggers {
+		reprocessRequests[eventTriggers[i].Name].setFailed(cluster)
+	}
+}
+
+// completeReprocessRequests completes all reprocess requests for which all requested clusters
+// currently matching the EventTrigger have been processed. A completed request is reported
+// in the EventTrigger Status and its annotation is removed.
+func completeReprocessRequests(ctx context.Context, c client.Client, eventTriggers *v1beta1.EventTriggerList,
+	reprocessRequests map[string]*reprocessRequest, eventTriggerMap map[string]libsveltosset.Set,
+	clusters []corev1.ObjectReference, logger logr.Logger) {
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+		request, ok := reprocessRequests[et.Name]
+		if !ok {
+			continue
+		}
+
+		matchingClusters := eventTriggerMap[et.Name]
+		completed := true
+		processedClusters := make([]corev1.ObjectReference, 0)
+		for j := range clusters {
+			cluster := &clusters[j]
+			if !request.isClusterRequested(cluster) || !matchingClusters.Has(cluster) {
+				continue
+			}
+			if !request.processed.Has(cluster) {
+				completed = false
+				break
+			}
+			processedClusters = append(processedClusters, *cluster)
+		}
+
+		if !completed {
+			continue
+		}
+
+		l := logger.WithValues("eventTrigger", et.Name)
+		l.V(logs.LogInfo).Info(fmt.Sprintf("reprocess request %q completed", request.request))
+
+		removed, err := removeReprocessAnnotation(ctx, c, et, request.request)
+		if err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to remove reprocess annotation: %v", err))
+			continue
+		}
+		if !removed {
+			// A new request was made meanwhile. It is served in next iteration.
+			l.V(logs.LogInfo).Info("reprocess annotation changed. Not completing request.")
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.Reprocess = &v1beta1.ReprocessStatus{
+			Request:           request.request,
+			ProcessedClusters: processedClusters,
+			CompletionTime:    metav1.Now(),
+		}
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			l.V(logs.LogInfo).Info(fmt.Sprintf("failed to report reprocess completion: %v", err))
+		}
+	}
+}
+
+// removeReprocessAnnotation removes the ReprocessAnnotation from the EventTrigger only if its value
+// is still request. On return et is the updated EventTrigger. Returns false if annotation changed.
+func removeReprocessAnnotation(ctx context.Context, c client.Client, et *v1beta1.EventTrigger,
+	request string) (bool, error) {
+
+	removed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		current := &v1beta1.EventTrigger{}
+		if err := c.Get(ctx, types.NamespacedName{Name: et.Name}, current); err != nil {
+			return err
+		}
+
+		if current.Annotations[v1beta1.ReprocessAnnotation] != request {
+			return nil
+		}
+
+		patch := client.MergeFromWithOptions(current.DeepCopy(), client.MergeFromWithOptimisticLock{})
+		delete(current.Annotations, v1beta1.ReprocessAnnotation)
+		if err := c.Patch(ctx, current, patch); err != nil {
+			return err
+		}
+
+		current.DeepCopyInto(et)
+		removed = true
+		return nil
+	})
+
+	return removed, err
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	libsveltosset "github.com/projectsveltos/libsveltos/lib/set"
+)
+
+var _ = Describe("Reprocess", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("completeReprocessRequests completes requests once all requested clusters are processed", func() {
+		cluster1 := corev1.ObjectReference{
+			Namespace: randomString(), Name: randomString(),
+			Kind: libsveltosv1beta1.SveltosClusterKind, APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+		cluster2 := corev1.ObjectReference{
+			Namespace: randomString(), Name: randomString(),
+			Kind: libsveltosv1beta1.SveltosClusterKind, APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+		clusters := []corev1.ObjectReference{cluster1, cluster2}
+
+		request := fmt.Sprintf("%s/%s", cluster1.Namespace, cluster1.Name)
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Annotations: map[string]string{
+					v1beta1.ReprocessAnnotation: request,
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(eventTrigger).
+			WithObjects(eventTrigger).Build()
+
+		eventTriggers := &v1beta1.EventTriggerList{}
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+
+		matchingClusters := libsveltosset.Set{}
+		matchingClusters.Insert(&cluster1)
+		matchingClusters.Insert(&cluster2)
+		eventTriggerMap := map[string]libsveltosset.Set{eventTrigger.Name: matchingClusters}
+
+		reprocessRequests := controllers.GetReprocessRequests(eventTriggers)
+		eventTriggerList := []*v1beta1.EventTrigger{&eventTriggers.Items[0]}
+		Expect(controllers.IsReprocessRequested(reprocessRequests, eventTriggerList, &cluster1)).To(BeTrue())
+		Expect(controllers.IsReprocessRequested(reprocessRequests, eventTriggerList, &cluster2)).To(BeFalse())
+
+		// Processing failed. Request is not completed
+		controllers.SetClusterReprocessFailed(reprocessRequests, eventTriggerList, &cluster1)
+		controllers.SetClusterReprocessed(reprocessRequests, &cluster1)
+		controllers.SetClusterReprocessed(reprocessRequests, &cluster2)
+		controllers.CompleteReprocessRequests(context.TODO(), c, eventTriggers, reprocessRequests,
+			eventTriggerMap, clusters, logger)
+
+		currentEventTrigger := &v1beta1.EventTrigger{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(currentEventTrigger.Annotations).To(HaveKey(v1beta1.ReprocessAnnotation))
+		Expect(currentEventTrigger.Status.Reprocess).To(BeNil())
+
+		// Next iteration succeeds
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+		reprocessRequests = controllers.GetReprocessRequests(eventTriggers)
+		controllers.SetClusterReprocessed(reprocessRequests, &cluster1)
+		controllers.SetClusterReprocessed(reprocessRequests, &cluster2)
+		controllers.CompleteReprocessRequests(context.TODO(), c, eventTriggers, reprocessRequests,
+			eventTriggerMap, clusters, logger)
+
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(currentEventTrigger.Annotations).ToNot(HaveKey(v1beta1.ReprocessAnnotation))
+		Expect(currentEventTrigger.Status.Reprocess).ToNot(BeNil())
+		Expect(currentEventTrigger.Status.Reprocess.Request).To(Equal(request))
+		Expect(currentEventTrigger.Status.Reprocess.ProcessedClusters).To(ConsistOf(cluster1))
+	})
+
+	It("completeReprocessRequests does not remove a reprocess annotation changed meanwhile", func() {
+		cluster := corev1.ObjectReference{
+			Namespace: randomString(), Name: randomString(),
+			Kind: libsveltosv1beta1.SveltosClusterKind, APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Annotations: map[string]string{
+					v1beta1.ReprocessAnnotation: v1beta1.ReprocessAll,
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(eventTrigger).
+			WithObjects(eventTrigger).Build()
+
+		eventTriggers := &v1beta1.EventTriggerList{}
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+
+		matchingClusters := libsveltosset.Set{}
+		matchingClusters.Insert(&cluster)
+		eventTriggerMap := map[string]libsveltosset.Set{eventTrigger.Name: matchingClusters}
+
+		reprocessRequests := controllers.GetReprocessRequests(eventTriggers)
+		controllers.SetClusterReprocessed(reprocessRequests, &cluster)
+
+		// A new request is made while the collection iteration is running
+		newRequest := fmt.Sprintf("%s/%s", cluster.Namespace, cluster.Name)
+		currentEventTrigger := &v1beta1.EventTrigger{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		currentEventTrigger.Annotations[v1beta1.ReprocessAnnotation] = newRequest
+		Expect(c.Update(context.TODO(), currentEventTrigger)).To(Succeed())
+
+		controllers.CompleteReprocessRequests(context.TODO(), c, eventTriggers, reprocessRequests,
+			eventTriggerMap, []corev1.ObjectReference{cluster}, logger)
+
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(currentEventTrigger.Annotations[v1beta1.ReprocessAnnotation]).To(Equal(newRequest))
+		Expect(currentEventTrigger.Status.Reprocess).To(BeNil())
+	})
+
+	It("getReprocessRequests accepts all clusters", func() {
+		eventTriggers := &v1beta1.EventTriggerList{
+			Items: []v1beta1.EventTrigger{
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        randomString(),
+						Annotations: map[string]string{v1beta1.ReprocessAnnotation: v1beta1.ReprocessAll},
+					},
+				},
+				{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: randomString(),
+					},
+				},
+			},
+		}
+
+		cluster := &corev1.ObjectReference{Namespace: randomString(), Name: randomString()}
+		reprocessRequests := controllers.GetReprocessRequests(eventTriggers)
+		Expect(controllers.IsReprocessRequested(reprocessRequests,
+			[]*v1beta1.EventTrigger{&eventTriggers.Items[0]}, cluster)).To(BeTrue())
+		Expect(controllers.IsReprocessRequested(reprocessRequests,
+			[]*v1beta1.EventTrigger{&eventTriggers.Items[1]}, cluster)).To(BeFalse())
+	})
+})
@@ -0,0 +1,591 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/funcmap"
+	"github.com/projectsveltos/libsveltos/lib/k8s_utils"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// instantiateFromResourceGeneratorsPerResource instantiates resources from ResourceGenerator.
+// One set of resources is instantiated for each resource/cloudEvent matching the referenced EventSource.
+func instantiateFromResourceGeneratorsPerResource(ctx context.Context, c client.Client,
+	eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) ([]corev1.ObjectReference, error) {
+
+	if len(eventTrigger.Spec.ResourceGenerator) == 0 {
+		return nil, nil
+	}
+
+	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
+
+	objects, err := prepareCurrentObjectList(ctx, c, clusterNamespace, clusterName, clusterType, er, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to prepare currentObject list %v", err))
+		return nil, err
+	}
+
+	var result []corev1.ObjectReference
+	for i := range objects {
+		labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
+			er, clusterType)
+		if objects[i].CloudEvent != nil {
+			labels = appendInstantiatedObjectLabelsForCloudEvent(labels, getCESource(objects[i].CloudEvent),
+				getCESubject(objects[i].CloudEvent))
+
+			instantiatedCloudEventAction, err := instantiateCloudEventAction(clusterNamespace, clusterName,
+				eventTrigger, objects[i], logger)
+			if err != nil {
+				return nil, err
+			}
+
+			if *instantiatedCloudEventAction == v1beta1.CloudEventActionDelete {
+				// Resources created because of a cloudEvent are ONLY removed when same (same subject/source)
+				// cloudEvent is received and EventTrigger.Spec.CloudEventAction is set to delete.
+				err = deleteInstantiatedFromResourceGenerators(ctx, c, eventTrigger, labels, logger)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		} else {
+			labels = appendInstantiatedObjectLabelsForResource(labels,
+				objects[i].MatchingResource.Namespace, objects[i].MatchingResource.Name)
+		}
+		cause := getEventCause(clusterNamespace, clusterName, clusterType, er, &objects[i])
+		if isEventBlocked(eventTrigger.Name, cause) {
+			continue
+		}
+		labels = appendGeneratorLabel(labels)
+		labels = appendServiceAccountLabels(eventTrigger, labels)
+
+		if isWaitingForDestinations(eventTrigger, cause) {
+			existing, err := getExistingFromResourceGenerators(ctx, c, eventTrigger, labels, logger)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, existing...)
+			continue
+		}
+
+		generated, err := instantiateResourceGenerators(ctx, c, eventTrigger, objects[i], clusterNamespace,
+			templateName, labels, logger)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, generated...)
+	}
+
+	return result, nil
+}
+
+// instantiateFromResourceGeneratorsPerAllResource instantiates resources from ResourceGenerator.
+// One set of resources is instantiated for all resources matching the referenced EventSource.
+func instantiateFromResourceGeneratorsPerAllResource(ctx context.Context, c client.Client,
+	eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, logger logr.Logger) ([]corev1.ObjectReference, error) {
+
+	cause := getEventCause(clusterNamespace, clusterName, clusterType, er, nil)
+	if len(eventTrigger.Spec.ResourceGenerator) == 0 || isEventBlocked(eventTrigger.Name, cause) {
+		return nil, nil
+	}
+
+	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
+		er, clusterType)
+	labels = appendGeneratorLabel(labels)
+	labels = appendServiceAccountLabels(eventTrigger, labels)
+
+	if isWaitingForDestinations(eventTrigger, cause) {
+		return getExistingFromResourceGenerators(ctx, c, eventTrigger, labels, logger)
+	}
+
+	templateName := getTemplateName(clusterNamespace, clusterName, eventTrigger.Name)
+
+	objects, err := prepareCurrentObjects(ctx, c, clusterNamespace, clusterName, clusterType, er, logger)
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to prepare currentObjects %v", err))
+		return nil, err
+	}
+
+	return instantiateResourceGenerators(ctx, c, eventTrigger, objects, clusterNamespace,
+		templateName, labels, logger)
+}
+
+// instantiateResourceGenerators instantiates, in the management cluster, all resources contained
+// in the ConfigMaps referenced by eventTrigger.Spec.ResourceGenerator
+func instantiateResourceGenerators(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	data any, clusterNamespace, templateName string, labels map[string]string, logger logr.Logger,
+) ([]corev1.ObjectReference, error) {
+
+	result := make([]corev1.ObjectReference, 0)
+
+	for i := range e.Spec.ResourceGenerator {
+		generator := &e.Spec.ResourceGenerator[i]
+
+		configMap, err := getResourceGeneratorConfigMap(ctx, c, generator, e, clusterNamespace,
+			templateName, data, logger)
+		if err != nil {
+			return nil, err
+		}
+		if configMap == nil {
+			continue
+		}
+
+		// Keep track of all referenced Generators. When those changes, EventTrigger will be reconciled.
+		resourceTracker := getTrackerInstance()
+		resourceTracker.trackResourceForConsumer(
+			&corev1.ObjectReference{Kind: string(libsveltosv1beta1.ConfigMapReferencedResourceKind),
+				Namespace: configMap.Namespace, Name: configMap.Name, APIVersion: "v1"},
+			&corev1.ObjectReference{Kind: v1beta1.EventTriggerKind, Name: e.GetName(), APIVersion: v1beta1.GroupVersion.String()},
+		)
+
+		generated, err := instantiateResourcesFromConfigMap(ctx, c, e, configMap, templateName, data,
+			labels, logger)
+		if err != nil {
+			msg := fmt.Sprintf("failed to instantiate content of ResourceGenerator: %s/%s",
+				configMap.Namespace, configMap.Name)
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("%s. Error: %v", msg, err))
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		result = append(result, generated...)
+	}
+
+	return result, nil
+}
+
+// getResourceGeneratorConfigMap returns the ConfigMap referenced by a ResourceGenerator.
+// Returns nil if the ConfigMap does not exist and the ResourceGenerator is optional.
+func getResourceGeneratorConfigMap(ctx context.Context, c client.Client, generator *v1beta1.ResourceGeneratorReference,
+	e *v1beta1.EventTrigger, clusterNamespace, templateName string, data any, logger logr.Logger,
+) (*corev1.ConfigMap, error) {
+
+	var namespace string
+	if generator.Namespace == "" {
+		namespace = clusterNamespace
+	} else {
+		referencedNamespace, err := instantiateSection(templateName, []byte(generator.Namespace), data,
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+		if err != nil {
+			return nil, err
+		}
+		namespace = string(referencedNamespace)
+	}
+
+	// The name of the referenced resource can be expressed as a template
+	referencedName, err := instantiateSection(templateName, []byte(generator.Name), data,
+		funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+	if err != nil {
+		return nil, err
+	}
+
+	configMap, err := getConfigMap(ctx, c, e, types.NamespacedName{Namespace: namespace, Name: string(referencedName)})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("referenced ResourceGenerator %s/%s does not exist yet",
+				namespace, string(referencedName)))
+			if generator.Optional {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("referenced ResourceGenerator %s/%s does not exist yet",
+				namespace, string(referencedName))
+		}
+		return nil, err
+	}
+
+	return configMap, nil
+}
+
+// instantiateResourcesFromConfigMap instantiates each template contained in the ConfigMap Data section
+// and server side applies resulting resources in the management cluster.
+// For EventTriggers created by tenant admins, nothing is applied unless the tenant service account is
+// allowed to create and update every instantiated resource.
+func instantiateResourcesFromConfigMap(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	configMap *corev1.ConfigMap, templateName string, data any, labels map[string]string, logger logr.Logger,
+) ([]corev1.ObjectReference, error) {
+
+	// Process keys in a predictable order
+	keys := make([]string, 0, len(configMap.Data))
+	for k := range configMap.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	resources := make([]*unstructured.Unstructured, 0)
+	for _, k := range keys {
+		instantiated, err := instantiateSection(templateName, []byte(configMap.Data[k]), data,
+			funcmap.HasTextTemplateAnnotation(e.Annotations), getTemplatePolicy(e), logger)
+		if err != nil {
+			return nil, err
+		}
+
+		elements, err := splitYAMLDocuments(instantiated)
+		if err != nil {
+			return nil, err
+		}
+		for i := range elements {
+			u, err := k8s_utils.GetUnstructured(elements[i])
+			if err != nil {
+				logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get resource from Data %.100s", elements[i]))
+				return nil, err
+			}
+
+			gvk := u.GroupVersionKind()
+			mapping, err := c.RESTMapper().RESTMapping(gvk.GroupKind(), gvk.Version)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := validateGeneratedResourceScope(u, mapping); err != nil {
+				return nil, err
+			}
+
+			if err := authorizeGeneratedResource(ctx, c, e, u, mapping); err != nil {
+				return nil, err
+			}
+
+			resources = append(resources, u)
+		}
+	}
+
+	// Kinds are recorded before any resource is created, so stale resources can always be found
+	if err := recordGeneratedResourceKinds(ctx, c, e, resources); err != nil {
+		return nil, err
+	}
+
+	result := make([]corev1.ObjectReference, 0, len(resources))
+	for _, u := range resources {
+		u.SetLabels(mergeLabels(u.GetLabels(), labels))
+		u.SetOwnerReferences(appendOwnerReferences(u.GetOwnerReferences(),
+			getEventTriggerOwnerReferences(e)))
+
+		err := applyGeneratedResource(ctx, u, logger)
+		if err != nil {
+			return nil, err
+		}
+
+		apiVersion, kind := u.GroupVersionKind().ToAPIVersionAndKind()
+		result = append(result, corev1.ObjectReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  u.GetNamespace(),
+			Name:       u.GetName(),
+		})
+	}
+
+	return result, nil
+}
+
+// authorizeGeneratedResource returns an error if EventTrigger was created by a tenant admin whose
+// service account is not allowed to create and update the generated resource. event-manager applies
+// generated resources with its own permissions, which must not be used to escalate tenant privileges.
+func authorizeGeneratedResource(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	u *unstructured.Unstructured, mapping *meta.RESTMapping) error {
+
+	if _, _, ok := getTenantServiceAccount(e); !ok {
+		return nil
+	}
+
+	return checkTenantWriteAccess(ctx, c, e, mapping.Resource.Group, mapping.Resource.Resource,
+		u.GetNamespace(), u.GetName())
+}
+
+// validateGeneratedResourceScope returns an error if generated resource is namespaced but no
+// namespace is set. Such resource would otherwise be rejected only when applied.
+func validateGeneratedResourceScope(u *unstructured.Unstructured, mapping *meta.RESTMapping) error {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && u.GetNamespace() == "" {
+		return fmt.Errorf("generated resource %s %s is namespaced but no namespace is set",
+			u.GetKind(), u.GetName())
+	}
+	return nil
+}
+
+func applyGeneratedResource(ctx context.Context, u *unstructured.Unstructured, logger logr.Logger) error {
+	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig, u.GroupVersionKind(), u.GetNamespace())
+	if err != nil {
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get dynamic client: %v", err))
+		return err
+	}
+
+	return updateResource(ctx, dr, u, logger)
+}
+
+// mergeLabels returns the union of the two label sets. In case of conflicts, values in
+// sveltosLabels take precedence.
+func mergeLabels(current, sveltosLabels map[string]string) map[string]string {
+	result := make(map[string]string, len(current)+len(sveltosLabels))
+	for k := range current {
+		result[k] = current[k]
+	}
+	for k := range sveltosLabels {
+		result[k] = sveltosLabels[k]
+	}
+	return result
+}
+
+// splitYAMLDocuments splits content in YAML documents. Empty documents are skipped.
+func splitYAMLDocuments(content []byte) ([][]byte, error) {
+	reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(content)))
+
+	result := make([][]byte, 0)
+	for {
+		document, err := reader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return result, nil
+			}
+			return nil, err
+		}
+		if len(bytes.TrimSpace(document)) == 0 {
+			continue
+		}
+		result = append(result, document)
+	}
+}
+
+// recordGeneratedResourceKinds adds the GroupVersionKinds of resources to EventTrigger
+// Status.GeneratedResourceKinds. Stale resources generated by ResourceGenerators are found
+// by listing all recorded kinds, so a kind must be recorded before any resource of such
+// kind is created.
+func recordGeneratedResourceKinds(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	resources []*unstructured.Unstructured) error {
+
+	missing := false
+	for i := range resources {
+		if !hasGeneratedResourceKind(e.Status.GeneratedResourceKinds, resources[i].GroupVersionKind()) {
+			missing = true
+			break
+		}
+	}
+	if !missing {
+		return nil
+	}
+
+	currentEventTrigger := &v1beta1.EventTrigger{}
+	if err := c.Get(ctx, types.NamespacedName{Name: e.Name}, currentEventTrigger); err != nil {
+		return err
+	}
+
+	patch := client.MergeFromWithOptions(currentEventTrigger.DeepCopy(), client.MergeFromWithOptimisticLock{})
+	for i := range resources {
+		gvk := resources[i].GroupVersionKind()
+		if !hasGeneratedResourceKind(currentEventTrigger.Status.GeneratedResourceKinds, gvk) {
+			currentEventTrigger.Status.GeneratedResourceKinds = append(currentEventTrigger.Status.GeneratedResourceKinds,
+				metav1.GroupVersionKind{Group: gvk.Group, Version: gvk.Version, Kind: gvk.Kind})
+		}
+	}
+
+	if err := c.Status().Patch(ctx, currentEventTrigger, patch); err != nil {
+		return err
+	}
+
+	e.Status.GeneratedResourceKinds = currentEventTrigger.Status.GeneratedResourceKinds
+	return nil
+}
+
+func hasGeneratedResourceKind(kinds []metav1.GroupVersionKind, gvk schema.GroupVersionKind) bool {
+	for i := range kinds {
+		if kinds[i].Group == gvk.Group && kinds[i].Version == gvk.Version && kinds[i].Kind == gvk.Kind {
+			return true
+		}
+	}
+	return false
+}
+
+// getGeneratedResourceKinds returns all GroupVersionKinds ever generated by the EventTrigger's
+// ResourceGenerators
+func getGeneratedResourceKinds(e *v1beta1.EventTrigger) []schema.GroupVersionKind {
+	if e == nil {
+		return nil
+	}
+
+	result := make([]schema.GroupVersionKind, len(e.Status.GeneratedResourceKinds))
+	for i := range e.Status.GeneratedResourceKinds {
+		kind := &e.Status.GeneratedResourceKinds[i]
+		result[i] = schema.GroupVersionKind{Group: kind.Group, Version: kind.Version, Kind: kind.Kind}
+	}
+	return result
+}
+
+// listGeneratedResources lists, for each passed in GroupVersionKind, all resources with labels
+func listGeneratedResources(ctx context.Context, c client.Client, gvks []schema.GroupVersionKind,
+	lbls map[string]string, logger logr.Logger) ([]unstructured.Unstructured, error) {
+
+	result := make([]unstructured.Unstructured, 0)
+	for i := range gvks {
+		// No namespace: for namespaced resources list across all namespaces
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(gvks[i].GroupVersion().WithKind(gvks[i].Kind + "List"))
+		err := c.List(ctx, list, client.MatchingLabels(lbls))
+		if err != nil {
+			if meta.IsNoMatchError(err) || apierrors.IsNotFound(err) {
+				logger.V(logs.LogDebug).Info(fmt.Sprintf("failed to list %s: %v", gvks[i].String(), err))
+				continue
+			}
+			return nil, err
+		}
+		result = append(result, list.Items...)
+	}
+
+	return result, nil
+}
+
+// removeGeneratedResources fetches all resources created by EventTrigger instance from ResourceGenerators
+// for a given cluster. It deletes all stale resources (all resources currently present and not in the
+// generated list).
+func removeGeneratedResources(ctx context.Context, c client.Client, clusterNamespace, clusterName string,
+	clusterType libsveltosv1beta1.ClusterType, eventTrigger *v1beta1.EventTrigger, er *libsveltosv1beta1.EventReport,
+	generated []corev1.ObjectReference, logger logr.Logger) error {
+
+	gvks := getGeneratedResourceKinds(eventTrigger)
+
+	current := make(map[corev1.ObjectReference]bool, len(generated))
+	for i := range generated {
+		current[generated[i]] = true
+		gvks = appendGVK(gvks, schema.FromAPIVersionAndKind(generated[i].APIVersion, generated[i].Kind))
+	}
+
+	labels := getInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
+		er, clusterType)
+	labels = appendGeneratorLabel(labels)
+
+	resources, err := listGeneratedResources(ctx, c, gvks, labels, logger)
+	if err != nil {
+		return err
+	}
+
+	for i := range resources {
+		u := &resources[i]
+		// For existing EventTrigger and EventReport
+		if isResourceExist(eventTrigger) && isResourceExist(er) && isGeneratedFromCloudEvent(u) {
+			// Resources generated for CloudEvents are removed when CloudEventAction is set to Delete
+			continue
+		}
+
+		apiVersion, kind := u.GroupVersionKind().ToAPIVersionAndKind()
+		ref := corev1.ObjectReference{APIVersion: apiVersion, Kind: kind, Namespace: u.GetNamespace(), Name: u.GetName()}
+		if _, ok := current[ref]; ok {
+			if err := clearPendingRemoval(ctx, c, u, logger); err != nil {
+				return err
+			}
+			continue
+		}
+
+		remove, err := canBeRemoved(ctx, c, eventTrigger, er, u, logger)
+		if err != nil {
+			return err
+		}
+		if !remove {
+			continue
+		}
+
+		if err := deleteGeneratedResource(ctx, u, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteInstantiatedFromResourceGenerators deletes all resources created from ResourceGenerators with
+// passed in labels.
+func deleteInstantiatedFromResourceGenerators(ctx context.Context, c client.Client,
+	eventTrigger *v1beta1.EventTrigger, lbls map[string]string, logger logr.Logger) error {
+
+	gvks := getGeneratedResourceKinds(eventTrigger)
+
+	lbls = appendGeneratorLabel(lbls)
+	resources, err := listGeneratedResources(ctx, c, gvks, lbls, logger)
+	if err != nil {
+		return err
+	}
+
+	for i := range resources {
+		if err := deleteGeneratedResource(ctx, &resources[i], logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteGeneratedResource(ctx context.Context, u *unstructured.Unstructured, logger logr.Logger) error {
+	dr, err := k8s_utils.GetDynamicResourceInterface(mgmtClusterConfig, u.GroupVersionKind(), u.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	logger.V(logs.LogInfo).Info(fmt.Sprintf("deleting %s %s/%s", u.GetKind(), u.GetNamespace(), u.GetName()))
+	err = dr.Delete(ctx, u.GetName(), metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func appendGVK(gvks []schema.GroupVersionKind, gvk schema.GroupVersionKind) []schema.GroupVersionKind {
+	for i := range gvks {
+		if gvks[i] == gvk {
+			return gvks
+		}
+	}
+	return append(gvks, gvk)
+}
+
+// getGeneratedPolicyRefs returns the ConfigMaps/Secrets created from ResourceGenerators. Those
+// must not be considered stale when cleaning up ConfigMaps/Secrets instantiated by ConfigMapGenerator,
+// SecretGenerator and PolicyRefs.
+func getGeneratedPolicyRefs(generated []corev1.ObjectReference) []libsveltosv1beta1.PolicyRef {
+	result := make([]libsveltosv1beta1.PolicyRef, 0)
+	for i := range generated {
+		if generated[i].APIVersion != "v1" {
+			continue
+		}
+		if generated[i].Kind != string(libsveltosv1beta1.ConfigMapReferencedResourceKind) &&
+			generated[i].Kind != string(libsveltosv1beta1.SecretReferencedResourceKind) {
+
+			continue
+		}
+		result = append(result, libsveltosv1beta1.PolicyRef{
+			Namespace: generated[i].Namespace,
+			Name:      generated[i].Name,
+			Kind:      generated[i].Kind,
+		})
+	}
+	return result
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("ResourceGenerator", func() {
+	var logger = textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	It("splitYAMLDocuments splits on document separators only", func() {
+		content := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test
+  namespace: default
+data:
+  separator: "a---b"
+  script: |
+    echo begin
+    ---
+    echo end
+---
+---
+apiVersion: lib.projectsveltos.io/v1beta1
+kind: ClusterSet
+metadata:
+  name: {{ .Cluster.metadata.name }}`
+
+		documents, err := controllers.SplitYAMLDocuments([]byte(content))
+		Expect(err).To(BeNil())
+		Expect(len(documents)).To(Equal(2))
+		Expect(string(documents[0])).To(ContainSubstring("a---b"))
+		Expect(string(documents[0])).To(ContainSubstring("echo end"))
+		Expect(string(documents[1])).To(ContainSubstring("kind: ClusterSet"))
+	})
+
+	It("validateGeneratedResourceScope rejects namespaced resources with no namespace", func() {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetName(randomString())
+
+		namespaced := &meta.RESTMapping{
+			Resource:         corev1.SchemeGroupVersion.WithResource("configmaps"),
+			GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			Scope:            meta.RESTScopeNamespace,
+		}
+		Expect(controllers.ValidateGeneratedResourceScope(u, namespaced)).ToNot(Succeed())
+
+		u.SetNamespace(randomString())
+		Expect(controllers.ValidateGeneratedResourceScope(u, namespaced)).To(Succeed())
+
+		u.SetKind("Namespace")
+		u.SetNamespace("")
+		clusterWide := &meta.RESTMapping{
+			Resource:         corev1.SchemeGroupVersion.WithResource("namespaces"),
+			GroupVersionKind: corev1.SchemeGroupVersion.WithKind("Namespace"),
+			Scope:            meta.RESTScopeRoot,
+		}
+		Expect(controllers.ValidateGeneratedResourceScope(u, clusterWide)).To(Succeed())
+	})
+
+	It("getGeneratedPolicyRefs returns only ConfigMaps and Secrets", func() {
+		generated := []corev1.ObjectReference{
+			{APIVersion: "v1", Kind: "ConfigMap", Namespace: randomString(), Name: randomString()},
+			{APIVersion: "v1", Kind: "Secret", Namespace: randomString(), Name: randomString()},
+			{APIVersion: libsveltosv1beta1.GroupVersion.String(), Kind: libsveltosv1beta1.ClusterSetKind,
+				Name: randomString()},
+		}
+
+		policyRefs := controllers.GetGeneratedPolicyRefs(generated)
+		Expect(len(policyRefs)).To(Equal(2))
+		Expect(policyRefs).To(ContainElement(libsveltosv1beta1.PolicyRef{
+			Namespace: generated[0].Namespace, Name: generated[0].Name, Kind: generated[0].Kind}))
+		Expect(policyRefs).To(ContainElement(libsveltosv1beta1.PolicyRef{
+			Namespace: generated[1].Namespace, Name: generated[1].Name, Kind: generated[1].Kind}))
+	})
+
+	It("instantiateResourceGenerators creates resources and removeGeneratedResources removes stale ones", func() {
+		clusterNamespace := randomString()
+		clusterName := randomString()
+		clusterType := libsveltosv1beta1.ClusterTypeSveltos
+
+		ns := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: clusterNamespace,
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), ns)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, ns)).To(Succeed())
+
+		instantiatedName := randomString()
+		generator := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: clusterNamespace,
+				Name:      randomString(),
+			},
+			Data: map[string]string{
+				"resource": fmt.Sprintf(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: %s
+  namespace: %s
+data:
+  cluster: "{{ .Cluster.metadata.name }}"`, instantiatedName, clusterNamespace),
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), generator)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, generator)).To(Succeed())
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				EventSourceName: randomString(),
+				ResourceGenerator: []v1beta1.ResourceGeneratorReference{
+					{Name: generator.Name},
+				},
+			},
+		}
+		Expect(testEnv.Create(context.TODO(), eventTrigger)).To(Succeed())
+		Expect(waitForObject(context.TODO(), testEnv.Client, eventTrigger)).To(Succeed())
+
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.EventSourceNameLabel: randomString(),
+				},
+			},
+		}
+
+		lbls := controllers.GetInstantiatedObjectLabels(clusterNamespace, clusterName, eventTrigger.Name,
+			eventReport, clusterType)
+		lbls["eventtrigger.lib.projectsveltos.io/fromgenerator"] = "ok"
+
+		data := controllers.CurrentObjects{
+			Cluster: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": clusterName},
+			},
+		}
+
+		generated, err := controllers.InstantiateResourceGenerators(context.TODO(), testEnv.Client, eventTrigger,
+			data, clusterNamespace, randomString(), lbls, logger)
+		Expect(err).To(BeNil())
+		Expect(len(generated)).To(Equal(1))
+		Expect(generated[0].Name).To(Equal(instantiatedName))
+
+		currentConfigMap := &corev1.ConfigMap{}
+		Eventually(func() bool {
+			err := testEnv.Get(context.TODO(),
+				types.NamespacedName{Namespace: clusterNamespace, Name: instantiatedName}, currentConfigMap)
+			return err == nil
+		}, timeout, pollingInterval).Should(BeTrue())
+		Expect(currentConfigMap.Data["cluster"]).To(Equal(clusterName))
+		for k := range lbls {
+			Expect(currentConfigMap.Labels[k]).To(Equal(lbls[k]))
+		}
+
+		// Generated kind is recorded in Status
+		Expect(eventTrigger.Status.GeneratedResourceKinds).To(ContainElement(
+			metav1.GroupVersionKind{Group: "", Version: "v1", Kind: "ConfigMap"}))
+
+		// ResourceGenerator is removed. Nothing is currently generated, so previously instantiated
+		// ConfigMap is stale
+		eventTrigger.Spec.ResourceGenerator = nil
+		Expect(controllers.RemoveGeneratedResources(context.TODO(), testEnv.Client, clusterNamespace, clusterName,
+			clusterType, eventTrigger, eventReport, nil, logger)).To(Succeed())
+
+		Eventually(func() bool {
+			err := testEnv.Get(context.TODO(),
+				types.NamespacedName{Namespace: clusterNamespace, Name: instantiatedName}, currentConfigMap)
+			return err != nil && apierrors.IsNotFound(err)
+		}, timeout, pollingInterval).Should(BeTrue())
+	})
+})
@@ -0,0 +1,266 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+var (
+	// errOutsideScheduleWindow is returned when changes for at least one EventTrigger
+	// are queued because no schedule window is open
+	errOutsideScheduleWindow = errors.New("changes queued waiting for a schedule window")
+)
+
+// scheduleQueue keeps track, per EventTrigger, of the EventReports whose changes
+// are queued waiting for a schedule window to open
+type scheduleQueue struct {
+	mux sync.Mutex
+
+	// key: EventTrigger name; value: set of queued EventReports
+	pending map[string]map[string]bool
+}
+
+var (
+	scheduleQueueInstance *scheduleQueue
+	scheduleQueueLock     = &sync.Mutex{}
+)
+
+func getScheduleQueue() *scheduleQueue {
+	if scheduleQueueInstance == nil {
+		scheduleQueueLock.Lock()
+		defer scheduleQueueLock.Unlock()
+		if scheduleQueueInstance == nil {
+			scheduleQueueInstance = &scheduleQueue{
+				pending: make(map[string]map[string]bool),
+			}
+		}
+	}
+
+	return scheduleQueueInstance
+}
+
+func (q *scheduleQueue) enqueue(eventTriggerName, key string) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	if q.pending[eventTriggerName] == nil {
+		q.pending[eventTriggerName] = make(map[string]bool)
+	}
+	q.pending[eventTriggerName][key] = true
+}
+
+func (q *scheduleQueue) dequeue(eventTriggerName, key string) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	delete(q.pending[eventTriggerName], key)
+	if len(q.pending[eventTriggerName]) == 0 {
+		delete(q.pending, eventTriggerName)
+	}
+}
+
+func (q *scheduleQueue) forget(eventTriggerName string) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	delete(q.pending, eventTriggerName)
+}
+
+// retain forgets all EventTriggers not in eventTriggerNames
+func (q *scheduleQueue) retain(eventTriggerNames map[string]bool) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	for name := range q.pending {
+		if !eventTriggerNames[name] {
+			delete(q.pending, name)
+		}
+	}
+}
+
+func (q *scheduleQueue) len(eventTriggerName string) int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	return len(q.pending[eventTriggerName])
+}
+
+func getScheduleQueueKey(clusterNamespace, clusterName, clusterType, eventReportName string) string {
+	return fmt.Sprintf("%s:%s/%s:%s", clusterType, clusterNamespace, clusterName, eventReportName)
+}
+
+func getScheduleLocation(schedule *v1beta1.Schedule) (*time.Location, error) {
+	if schedule.TimeZone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(schedule.TimeZone)
+}
+
+// isInScheduleWindow returns true if at least one of the schedule windows is open at the given time.
+// A window is open at time t if its cron expression fired in (t - duration, t].
+func isInScheduleWindow(schedule *v1beta1.Schedule, now time.Time) (bool, error) {
+	loc, err := getScheduleLocation(schedule)
+	if err != nil {
+		return false, err
+	}
+	now = now.In(loc)
+
+	for i := range schedule.Windows {
+		window := &schedule.Windows[i]
+		if window.Duration.Duration <= 0 {
+			// A window with no duration would never be open
+			return false, fmt.Errorf("invalid schedule window %q: duration must be greater than zero", window.Start)
+		}
+		cronSchedule, err := cron.ParseStandard(window.Start)
+		if err != nil {
+			return false, fmt.Errorf("invalid schedule window %q: %w", window.Start, err)
+		}
+
+		if !cronSchedule.Next(now.Add(-window.Duration.Duration)).After(now) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getNextScheduleWindow returns the time the next schedule window opens
+func getNextScheduleWindow(schedule *v1beta1.Schedule, now time.Time) (*time.Time, error) {
+	loc, err := getScheduleLocation(schedule)
+	if err != nil {
+		return nil, err
+	}
+	now = now.In(loc)
+
+	var next *time.Time
+	for i := range schedule.Windows {
+		cronSchedule, err := cron.ParseStandard(schedule.Windows[i].Start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule window %q: %w", schedule.Windows[i].Start, err)
+		}
+
+		t := cronSchedule.Next(now)
+		if t.IsZero() {
+			continue
+		}
+		if next == nil || t.Before(*next) {
+			next = &t
+		}
+	}
+
+	return next, nil
+}
+
+// canApplyChanges returns true if eventTrigger is allowed to turn EventReport changes into
+// ClusterProfile changes now. If not and the EventReport has unprocessed changes, the EventReport
+// is queued.
+func canApplyChanges(eventTrigger *v1beta1.EventTrigger, key string, hasChanges bool, logger logr.Logger) bool {
+	if eventTrigger.Spec.Schedule == nil {
+		return true
+	}
+
+	inWindow, err := isInScheduleWindow(eventTrigger.Spec.Schedule, time.Now())
+	if err != nil {
+		// An invalid Schedule never opens. Error is reported in the EventTrigger Status
+		logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to evaluate schedule: %v", err))
+	}
+
+	if !inWindow {
+		if hasChanges {
+			logger.V(logs.LogDebug).Info("outside schedule window. Queueing changes")
+			getScheduleQueue().enqueue(eventTrigger.Name, key)
+		}
+		return false
+	}
+
+	getScheduleQueue().dequeue(eventTrigger.Name, key)
+	return true
+}
+
+// updateScheduleStatuses updates the ScheduleStatus of all EventTriggers
+func updateScheduleStatuses(ctx context.Context, c client.Client, eventTriggers *v1beta1.EventTriggerList,
+	logger logr.Logger) {
+
+	eventTriggerNames := make(map[string]bool, len(eventTriggers.Items))
+	for i := range eventTriggers.Items {
+		eventTriggerNames[eventTriggers.Items[i].Name] = true
+	}
+	getScheduleQueue().retain(eventTriggerNames)
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+
+		var scheduleStatus *v1beta1.ScheduleStatus
+		if et.Spec.Schedule == nil {
+			getScheduleQueue().forget(et.Name)
+		} else {
+			scheduleStatus = getScheduleStatus(et, time.Now())
+		}
+
+		if reflect.DeepEqual(et.Status.ScheduleStatus, scheduleStatus) {
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.ScheduleStatus = scheduleStatus
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventTrigger %s schedule status: %v",
+				et.Name, err))
+		}
+	}
+}
+
+func getScheduleStatus(eventTrigger *v1beta1.EventTrigger, now time.Time) *v1beta1.ScheduleStatus {
+	scheduleStatus := &v1beta1.ScheduleStatus{
+		PendingChanges: getScheduleQueue().len(eventTrigger.Name),
+	}
+
+	inWindow, err := isInScheduleWindow(eventTrigger.Spec.Schedule, now)
+	if err != nil {
+		msg := err.Error()
+		scheduleStatus.FailureMessage = &msg
+		return scheduleStatus
+	}
+	scheduleStatus.InWindow = inWindow
+
+	next, err := getNextScheduleWindow(eventTrigger.Spec.Schedule, now)
+	if err != nil {
+		msg := err.Error()
+		scheduleStatus.FailureMessage = &msg
+		return scheduleStatus
+	}
+	if next != nil {
+		// Time is serialized with seconds precision
+		scheduleStatus.NextWindow = &metav1.Time{Time: next.UTC().Truncate(time.Second)}
+	}
+
+	return scheduleStatus
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("Schedule", func() {
+	It("isInScheduleWindow returns true only when a window is open", func() {
+		schedule := &v1beta1.Schedule{
+			Windows: []v1beta1.ScheduleWindow{
+				{
+					// Every day at 02:00
+					Start:    "0 2 * * *",
+					Duration: metav1.Duration{Duration: 2 * time.Hour},
+				},
+			},
+		}
+
+		inWindow, err := controllers.IsInScheduleWindow(schedule,
+			time.Date(2025, time.March, 10, 3, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(inWindow).To(BeTrue())
+
+		inWindow, err = controllers.IsInScheduleWindow(schedule,
+			time.Date(2025, time.March, 10, 5, 0, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(inWindow).To(BeFalse())
+
+		inWindow, err = controllers.IsInScheduleWindow(schedule,
+			time.Date(2025, time.March, 10, 1, 59, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(inWindow).To(BeFalse())
+	})
+
+	It("isInScheduleWindow evaluates windows in the schedule time zone", func() {
+		schedule := &v1beta1.Schedule{
+			Windows: []v1beta1.ScheduleWindow{
+				{
+					Start:    "0 2 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				},
+			},
+			TimeZone: "Asia/Tokyo",
+		}
+
+		// 17:30 UTC is 02:30 in Tokyo
+		inWindow, err := controllers.IsInScheduleWindow(schedule,
+			time.Date(2025, time.March, 10, 17, 30, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(inWindow).To(BeTrue())
+
+		inWindow, err = controllers.IsInScheduleWindow(schedule,
+			time.Date(2025, time.March, 10, 2, 30, 0, 0, time.UTC))
+		Expect(err).To(BeNil())
+		Expect(inWindow).To(BeFalse())
+	})
+
+	It("isInScheduleWindow returns an error for invalid schedule", func() {
+		schedule := &v1beta1.Schedule{
+			Windows: []v1beta1.ScheduleWindow{
+				{
+					Start:    "not a cron expression",
+					Duration: metav1.Duration{Duration: time.Hour},
+				},
+			},
+		}
+
+		_, err := controllers.IsInScheduleWindow(schedule, time.Now())
+		Expect(err).ToNot(BeNil())
+
+		schedule.Windows[0].Start = "0 2 * * *"
+		schedule.TimeZone = "Not/A_Zone"
+		_, err = controllers.IsInScheduleWindow(schedule, time.Now())
+		Expect(err).ToNot(BeNil())
+
+		schedule.TimeZone = ""
+		schedule.Windows[0].Duration = metav1.Duration{}
+		_, err = controllers.IsInScheduleWindow(schedule, time.Now())
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("duration must be greater than zero"))
+	})
+
+	It("getNextScheduleWindow returns the earliest next window", func() {
+		schedule := &v1beta1.Schedule{
+			Windows: []v1beta1.ScheduleWindow{
+				{
+					// Every Saturday at 22:00
+					Start:    "0 22 * * 6",
+					Duration: metav1.Duration{Duration: 4 * time.Hour},
+				},
+				{
+					// Every day at 03:00
+					Start:    "0 3 * * *",
+					Duration: metav1.Duration{Duration: time.Hour},
+				},
+			},
+		}
+
+		// Monday
+		now := time.Date(2025, time.March, 10, 12, 0, 0, 0, time.UTC)
+		next, err := controllers.GetNextScheduleWindow(schedule, now)
+		Expect(err).To(BeNil())
+		Expect(next).ToNot(BeNil())
+		Expect(next.Equal(time.Date(2025, time.March, 11, 3, 0, 0, 0, time.UTC))).To(BeTrue())
+	})
+})
@@ -0,0 +1,784 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"filippo.io/age"
+	"filippo.io/age/armor"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// Referenced ConfigMaps/Secrets with the SopsEncryptedAnnotation contain, in each data entry, a document
+// encrypted with SOPS (https://github.com/getsops/sops) using age. Content is decrypted in memory,
+// using the age private keys contained in the Secret configured at startup, before being instantiated.
+// Decrypted content is only ever written to generated Secrets, never to ConfigMaps.
+// Only age is supported. Documents can be YAML (one or more documents) or JSON. Comments are not
+// part of the decrypted content.
+// The age keys are the platform admin ones, so EventTriggers created by tenant admins cannot reference
+// SOPS encrypted content.
+
+var (
+	sopsAgeKeySecret     *types.NamespacedName
+	sopsAgeKeySecretLock = &sync.RWMutex{}
+)
+
+// SetSopsAgeKeySecret sets the Secret, in the management cluster, containing the age private keys
+// used to decrypt SOPS encrypted content. Every data entry of the Secret can contain one or more
+// age identities (same format as an age keys file).
+func SetSopsAgeKeySecret(namespace, name string) {
+	sopsAgeKeySecretLock.Lock()
+	defer sopsAgeKeySecretLock.Unlock()
+
+	sopsAgeKeySecret = &types.NamespacedName{Namespace: namespace, Name: name}
+}
+
+func getSopsAgeKeySecret() *types.NamespacedName {
+	sopsAgeKeySecretLock.RLock()
+	defer sopsAgeKeySecretLock.RUnlock()
+
+	return sopsAgeKeySecret
+}
+
+// isSopsEncrypted returns true if the referenced ConfigMap/Secret content is SOPS encrypted
+func isSopsEncrypted(ref client.Object) bool {
+	_, ok := ref.GetAnnotations()[v1beta1.SopsEncryptedAnnotation]
+	return ok
+}
+
+// requiresInstantiation returns true if a new ConfigMap/Secret must be generated from referenced
+// resource instead of having the ClusterProfile directly reference it
+func requiresInstantiation(ref client.Object) bool {
+	if _, ok := ref.GetAnnotations()[v1beta1.InstantiateAnnotation]; ok {
+		return true
+	}
+	return isSopsEncrypted(ref)
+}
+
+// getInstantiatedKind returns the kind of the resource generated from referenced resource.
+// SOPS encrypted content is always written to a Secret.
+func getInstantiatedKind(ref client.Object) string {
+	if isSopsEncrypted(ref) {
+		return string(libsveltosv1beta1.SecretReferencedResourceKind)
+	}
+	return ref.GetObjectKind().GroupVersionKind().Kind
+}
+
+// getSopsAgeIdentities returns the age identities contained in the configured Secret
+func getSopsAgeIdentities(ctx context.Context, c client.Client) ([]age.Identity, error) {
+	secretName := getSopsAgeKeySecret()
+	if secretName == nil {
+		return nil, errors.New("content is SOPS encrypted but no age key Secret is configured")
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, *secretName, secret); err != nil {
+		return nil, fmt.Errorf("failed to get age key Secret %s: %w", secretName.String(), err)
+	}
+
+	keys := make([]string, 0, len(secret.Data))
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	identities := make([]age.Identity, 0)
+	for _, k := range keys {
+		current, err := age.ParseIdentities(bytes.NewReader(secret.Data[k]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse age identities in Secret %s key %s: %w",
+				secretName.String(), k, err)
+		}
+		identities = append(identities, current...)
+	}
+
+	if len(identities) == 0 {
+		return nil, fmt.Errorf("age key Secret %s contains no identity", secretName.String())
+	}
+
+	return identities, nil
+}
+
+// decryptSopsContent decrypts each entry of content
+func decryptSopsContent(ctx context.Context, c client.Client, content map[string]string,
+) (map[string]string, error) {
+
+	identities, err := getSopsAgeIdentities(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]string, len(content))
+	for k := range content {
+		decrypted, err := decryptSopsDocument([]byte(content[k]), identities)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt key %s: %w", k, err)
+		}
+		result[k] = string(decrypted)
+	}
+
+	return result, nil
+}
+
+// errSopsNotAllowedForTenant is returned when an EventTrigger created by a tenant admin references
+// SOPS encrypted content
+var errSopsNotAllowedForTenant = errors.New("EventTriggers created by tenant admins cannot reference " +
+	"SOPS encrypted content")
+
+// validateSopsAccess returns an error if EventTrigger e was created by a tenant admin. Age keys are
+// not scoped per tenant, so a tenant admin could otherwise decrypt any content encrypted for the
+// platform admin by referencing it.
+func validateSopsAccess(e *v1beta1.EventTrigger) error {
+	if _, _, ok := getTenantServiceAccount(e); ok {
+		return errSopsNotAllowedForTenant
+	}
+	return nil
+}
+
+// sopsMetadata is the subset of the SOPS metadata (the sops top level key) needed to decrypt
+// a document encrypted with age
+type sopsMetadata struct {
+	AgeKeys                   []sopsAgeKey   `yaml:"age,omitempty" json:"age,omitempty"`
+	KeyGroups                 []sopsKeyGroup `yaml:"key_groups,omitempty" json:"key_groups,omitempty"`
+	LastModified              string         `yaml:"lastmodified" json:"lastmodified"`
+	MessageAuthenticationCode string         `yaml:"mac" json:"mac"`
+	UnencryptedSuffix         string         `yaml:"unencrypted_suffix,omitempty" json:"unencrypted_suffix,omitempty"`
+	EncryptedSuffix           string         `yaml:"encrypted_suffix,omitempty" json:"encrypted_suffix,omitempty"`
+	UnencryptedRegex          string         `yaml:"unencrypted_regex,omitempty" json:"unencrypted_regex,omitempty"`
+	EncryptedRegex            string         `yaml:"encrypted_regex,omitempty" json:"encrypted_regex,omitempty"`
+	UnencryptedCommentRegex   string         `yaml:"unencrypted_comment_regex,omitempty" json:"unencrypted_comment_regex,omitempty"`
+	EncryptedCommentRegex     string         `yaml:"encrypted_comment_regex,omitempty" json:"encrypted_comment_regex,omitempty"`
+	MACOnlyEncrypted          bool           `yaml:"mac_only_encrypted,omitempty" json:"mac_only_encrypted,omitempty"`
+}
+
+type sopsKeyGroup struct {
+	AgeKeys []sopsAgeKey `yaml:"age" json:"age"`
+}
+
+type sopsAgeKey struct {
+	Recipient        string `yaml:"recipient" json:"recipient"`
+	EncryptedDataKey string `yaml:"enc" json:"enc"`
+}
+
+type sopsFile struct {
+	Metadata *sopsMetadata `yaml:"sops" json:"sops"`
+}
+
+const (
+	sopsMetadataKey = "sops"
+	// sopsYAMLIndent is the indentation SOPS uses when emitting YAML
+	sopsYAMLIndent = 4
+)
+
+// sopsMACOnlyEncryptedInitialization is written to the MAC hash first when only encrypted values
+// are part of the MAC. Same value used by SOPS.
+var sopsMACOnlyEncryptedInitialization = []byte{0x8a, 0x3f, 0xd2, 0xad, 0x54, 0xce, 0x66, 0x52, 0x7b,
+	0x10, 0x34, 0xf3, 0xd1, 0x47, 0xbe, 0xb, 0xb, 0x97, 0x5b, 0x3b, 0xf4, 0x4f, 0x72, 0xc6, 0xfd, 0xad,
+	0xec, 0x81, 0x76, 0xf2, 0x7d, 0x69}
+
+var sopsEncryptedValueRegex = regexp.MustCompile(`^ENC\[AES256_GCM,data:(.+),iv:(.+),tag:(.+),type:(.+)\]`)
+
+// sopsTreeItem is a key/value pair of a SOPS document. Value is either a sopsTreeBranch,
+// a []interface{} or a scalar. Order of the items is the one of the document, as the MAC
+// depends on it.
+type sopsTreeItem struct {
+	key   string
+	value interface{}
+}
+
+type sopsTreeBranch []sopsTreeItem
+
+// decryptSopsDocument decrypts a SOPS encrypted YAML or JSON document. Output has the same format as
+// input, without SOPS metadata and without comments. It follows the SOPS decryption: data key is
+// decrypted with age identities, each value is decrypted with AES256-GCM and the MAC, computed over
+// the values in document order, must match the one recorded in the metadata.
+// Only age is supported; Shamir secret sharing and comment based encryption rules are refused.
+func decryptSopsDocument(data []byte, identities []age.Identity) ([]byte, error) {
+	isJSON := bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+
+	var holder sopsFile
+	var branches []sopsTreeBranch
+	var err error
+	if isJSON {
+		if err = json.Unmarshal(data, &holder); err != nil {
+			return nil, fmt.Errorf("failed to load SOPS document: %w", err)
+		}
+		var branch sopsTreeBranch
+		branch, err = loadSopsJSONDocument(data)
+		branches = []sopsTreeBranch{branch}
+	} else {
+		if err = yaml.Unmarshal(data, &holder); err != nil {
+			return nil, fmt.Errorf("failed to load SOPS document: %w", err)
+		}
+		branches, err = loadSopsYAMLDocuments(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load SOPS document: %w", err)
+	}
+	if holder.Metadata == nil {
+		return nil, errors.New("failed to load SOPS document: sops metadata not found")
+	}
+
+	metadata := holder.Metadata
+	if metadata.UnencryptedCommentRegex != "" || metadata.EncryptedCommentRegex != "" {
+		return nil, errors.New("SOPS comment regexes are not supported")
+	}
+
+	key, err := getSopsDataKey(metadata, identities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SOPS data key: %w", err)
+	}
+
+	mac, err := decryptSopsTree(branches, metadata, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SOPS document: %w", err)
+	}
+
+	// MAC covers the whole document. A mismatch means content has been modified.
+	lastModified, err := time.Parse(time.RFC3339, metadata.LastModified)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse SOPS lastmodified: %w", err)
+	}
+	originalMAC, err := decryptSopsValue(metadata.MessageAuthenticationCode, key,
+		lastModified.Format(time.RFC3339))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt SOPS MAC: %w", err)
+	}
+	if originalMAC != mac {
+		return nil, errors.New("SOPS MAC mismatch: content has been tampered with")
+	}
+
+	if isJSON {
+		return emitSopsJSONDocument(branches[0])
+	}
+	return emitSopsYAMLDocuments(branches)
+}
+
+// getSopsDataKey returns the data key, decrypting it with the first age recipient identities
+// can decrypt
+func getSopsDataKey(metadata *sopsMetadata, identities []age.Identity) ([]byte, error) {
+	ageKeys := metadata.AgeKeys
+	switch len(metadata.KeyGroups) {
+	case 0:
+	case 1:
+		ageKeys = append(ageKeys, metadata.KeyGroups[0].AgeKeys...)
+	default:
+		return nil, errors.New("Shamir secret sharing is not supported")
+	}
+
+	if len(ageKeys) == 0 {
+		return nil, errors.New("only age is supported")
+	}
+
+	var errs []error
+	for i := range ageKeys {
+		r, err := age.Decrypt(armor.NewReader(strings.NewReader(ageKeys[i].EncryptedDataKey)), identities...)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recipient %s: %w", ageKeys[i].Recipient, err))
+			continue
+		}
+		key, err := io.ReadAll(r)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("recipient %s: %w", ageKeys[i].Recipient, err))
+			continue
+		}
+		return key, nil
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// decryptSopsTree decrypts, in place, all values of branches and returns the MAC of the document
+func decryptSopsTree(branches []sopsTreeBranch, metadata *sopsMetadata, key []byte) (string, error) {
+	isEncrypted, err := getSopsEncryptionRule(metadata)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha512.New()
+	if metadata.MACOnlyEncrypted {
+		hash.Write(sopsMACOnlyEncryptedInitialization)
+	}
+
+	var walk func(in interface{}, path []string) (interface{}, error)
+	walk = func(in interface{}, path []string) (interface{}, error) {
+		switch v := in.(type) {
+		case nil:
+			return nil, nil
+		case sopsTreeBranch:
+			for i := range v {
+				itemPath := append(path[:len(path):len(path)], v[i].key)
+				value, err := walk(v[i].value, itemPath)
+				if err != nil {
+					return nil, err
+				}
+				v[i].value = value
+			}
+			return v, nil
+		case []interface{}:
+			for i := range v {
+				value, err := walk(v[i], path)
+				if err != nil {
+					return nil, err
+				}
+				v[i] = value
+			}
+			return v, nil
+		}
+
+		encrypted := isEncrypted(path)
+		value := in
+		if encrypted {
+			encryptedValue, ok := in.(string)
+			if !ok {
+				return nil, fmt.Errorf("value %s is not encrypted", strings.Join(path, ":"))
+			}
+			var err error
+			value, err = decryptSopsValue(encryptedValue, key, strings.Join(path, ":")+":")
+			if err != nil {
+				return nil, fmt.Errorf("could not decrypt value %s: %w", strings.Join(path, ":"), err)
+			}
+		}
+		if !metadata.MACOnlyEncrypted || encrypted {
+			b, err := sopsValueToBytes(value)
+			if err != nil {
+				return nil, err
+			}
+			hash.Write(b)
+		}
+		return value, nil
+	}
+
+	for i := range branches {
+		if _, err := walk(branches[i], make([]string, 0)); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("%X", hash.Sum(nil)), nil
+}
+
+// getSopsEncryptionRule returns a function reporting whether the value at path is encrypted,
+// according to the suffix and regex settings in metadata
+func getSopsEncryptionRule(metadata *sopsMetadata) (func(path []string) bool, error) {
+	var unencryptedRegex, encryptedRegex *regexp.Regexp
+	var err error
+	if metadata.UnencryptedRegex != "" {
+		if unencryptedRegex, err = regexp.Compile(metadata.UnencryptedRegex); err != nil {
+			return nil, fmt.Errorf("invalid unencrypted_regex: %w", err)
+		}
+	}
+	if metadata.EncryptedRegex != "" {
+		if encryptedRegex, err = regexp.Compile(metadata.EncryptedRegex); err != nil {
+			return nil, fmt.Errorf("invalid encrypted_regex: %w", err)
+		}
+	}
+
+	anyMatch := func(path []string, match func(string) bool) bool {
+		for i := range path {
+			if match(path[i]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(path []string) bool {
+		encrypted := true
+		if metadata.UnencryptedSuffix != "" && anyMatch(path, func(p string) bool {
+			return strings.HasSuffix(p, metadata.UnencryptedSuffix)
+		}) {
+			encrypted = false
+		}
+		if metadata.EncryptedSuffix != "" {
+			encrypted = anyMatch(path, func(p string) bool {
+				return strings.HasSuffix(p, metadata.EncryptedSuffix)
+			})
+		}
+		if unencryptedRegex != nil && anyMatch(path, unencryptedRegex.MatchString) {
+			encrypted = false
+		}
+		if encryptedRegex != nil {
+			encrypted = anyMatch(path, encryptedRegex.MatchString)
+		}
+		return encrypted
+	}, nil
+}
+
+// decryptSopsValue decrypts a value in the SOPS format
+// ENC[AES256_GCM,data:<data>,iv:<iv>,tag:<tag>,type:<type>]
+func decryptSopsValue(value string, key []byte, additionalData string) (interface{}, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	matches := sopsEncryptedValueRegex.FindStringSubmatch(value)
+	if matches == nil {
+		return nil, errors.New("value does not match SOPS format")
+	}
+
+	decoded := make([][]byte, 0)
+	for _, encoded := range matches[1:4] {
+		current, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode value: %w", err)
+		}
+		decoded = append(decoded, current)
+	}
+	data, iv, tag := decoded[0], decoded[1], decoded[2]
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(iv))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, iv, append(data, tag...), []byte(additionalData))
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt with AES_GCM: %w", err)
+	}
+
+	switch valueType := matches[4]; valueType {
+	case "str", "bytes":
+		return string(plaintext), nil
+	case "int":
+		return strconv.Atoi(string(plaintext))
+	case "float":
+		return strconv.ParseFloat(string(plaintext), 64)
+	case "bool":
+		return strconv.ParseBool(string(plaintext))
+	case "time":
+		var t time.Time
+		err := t.UnmarshalText(plaintext)
+		return t, err
+	default:
+		return nil, fmt.Errorf("unsupported value type %s", valueType)
+	}
+}
+
+// sopsValueToBytes returns the representation of a scalar used to compute the MAC
+func sopsValueToBytes(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return []byte(v), nil
+	case int:
+		return []byte(strconv.Itoa(v)), nil
+	case float64:
+		return []byte(strconv.FormatFloat(v, 'f', -1, 64)), nil
+	case bool:
+		if v {
+			return []byte("True"), nil
+		}
+		return []byte("False"), nil
+	case time.Time:
+		return v.MarshalText()
+	default:
+		return nil, fmt.Errorf("unsupported value type %T", value)
+	}
+}
+
+// loadSopsYAMLDocuments returns one branch per YAML document, without the sops key
+func loadSopsYAMLDocuments(data []byte) ([]sopsTreeBranch, error) {
+	branches := make([]sopsTreeBranch, 0)
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	for {
+		var document yaml.Node
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		branch := make(sopsTreeBranch, 0)
+		if len(document.Content) > 0 {
+			root := document.Content[0]
+			if root.Kind == yaml.AliasNode {
+				root = root.Alias
+			}
+			switch {
+			case root.Kind == yaml.MappingNode:
+				value, err := yamlNodeToSopsValue(root)
+				if err != nil {
+					return nil, err
+				}
+				branch = value.(sopsTreeBranch)
+			case root.Kind != yaml.ScalarNode || root.ShortTag() != "!!null":
+				return nil, errors.New("YAML documents must be mappings")
+			}
+		}
+
+		branches = append(branches, removeSopsMetadata(branch))
+	}
+
+	return branches, nil
+}
+
+func yamlNodeToSopsValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.SequenceNode:
+		result := make([]interface{}, 0, len(node.Content))
+		for i := range node.Content {
+			value, err := yamlNodeToSopsValue(node.Content[i])
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+	case yaml.MappingNode:
+		branch := make(sopsTreeBranch, 0)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			var key interface{}
+			if err := node.Content[i].Decode(&key); err != nil {
+				return nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("only string keys are supported, found %v", key)
+			}
+			value, err := yamlNodeToSopsValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			branch = append(branch, sopsTreeItem{key: keyString, value: value})
+		}
+		return branch, nil
+	case yaml.AliasNode:
+		return yamlNodeToSopsValue(node.Alias)
+	default:
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
+
+// loadSopsJSONDocument returns the branch of a JSON document, without the sops key. Order of the
+// keys is kept.
+func loadSopsJSONDocument(data []byte) (sopsTreeBranch, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return nil, errors.New("JSON document must be an object")
+	}
+
+	value, err := jsonTokensToSopsValue(decoder, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return removeSopsMetadata(value.(sopsTreeBranch)), nil
+}
+
+func jsonTokensToSopsValue(decoder *json.Decoder, token json.Token) (interface{}, error) {
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		branch := make(sopsTreeBranch, 0)
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected JSON object key, found %v", key)
+			}
+			value, err := nextJSONSopsValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			branch = append(branch, sopsTreeItem{key: keyString, value: value})
+		}
+		_, err := decoder.Token()
+		return branch, err
+	case '[':
+		result := make([]interface{}, 0)
+		for decoder.More() {
+			value, err := nextJSONSopsValue(decoder)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		_, err := decoder.Token()
+		return result, err
+	default:
+		return nil, fmt.Errorf("unexpected JSON delimiter %s", delim)
+	}
+}
+
+func nextJSONSopsValue(decoder *json.Decoder) (interface{}, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	return jsonTokensToSopsValue(decoder, token)
+}
+
+func removeSopsMetadata(branch sopsTreeBranch) sopsTreeBranch {
+	result := make(sopsTreeBranch, 0, len(branch))
+	for i := range branch {
+		if branch[i].key != sopsMetadataKey {
+			result = append(result, branch[i])
+		}
+	}
+	return result
+}
+
+func emitSopsYAMLDocuments(branches []sopsTreeBranch) ([]byte, error) {
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(sopsYAMLIndent)
+	for i := range branches {
+		mapping, err := sopsValueToYAMLNode(branches[i])
+		if err != nil {
+			return nil, err
+		}
+		document := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{mapping}}
+		if err := encoder.Encode(document); err != nil {
+			return nil, fmt.Errorf("failed to marshal YAML: %w", err)
+		}
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func sopsValueToYAMLNode(value interface{}) (*yaml.Node, error) {
+	switch v := value.(type) {
+	case sopsTreeBranch:
+		mapping := &yaml.Node{Kind: yaml.MappingNode}
+		for i := range v {
+			keyNode, err := sopsValueToYAMLNode(v[i].key)
+			if err != nil {
+				return nil, err
+			}
+			valueNode, err := sopsValueToYAMLNode(v[i].value)
+			if err != nil {
+				return nil, err
+			}
+			mapping.Content = append(mapping.Content, keyNode, valueNode)
+		}
+		return mapping, nil
+	case []interface{}:
+		sequence := &yaml.Node{Kind: yaml.SequenceNode}
+		for i := range v {
+			node, err := sopsValueToYAMLNode(v[i])
+			if err != nil {
+				return nil, err
+			}
+			sequence.Content = append(sequence.Content, node)
+		}
+		return sequence, nil
+	default:
+		node := &yaml.Node{}
+		if err := node.Encode(v); err != nil {
+			return nil, err
+		}
+		return node, nil
+	}
+}
+
+func emitSopsJSONDocument(branch sopsTreeBranch) ([]byte, error) {
+	var compact bytes.Buffer
+	if err := writeSopsJSONValue(&compact, branch); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, compact.Bytes(), "", "\t"); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	out.WriteByte('\n')
+
+	return out.Bytes(), nil
+}
+
+func writeSopsJSONValue(b *bytes.Buffer, value interface{}) error {
+	switch v := value.(type) {
+	case sopsTreeBranch:
+		b.WriteByte('{')
+		for i := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeSopsJSONValue(b, v[i].key); err != nil {
+				return err
+			}
+			b.WriteByte(':')
+			if err := writeSopsJSONValue(b, v[i].value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case []interface{}:
+		b.WriteByte('[')
+		for i := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeSopsJSONValue(b, v[i]); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		b.Write(encoded)
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"strings"
+
+	"filippo.io/age"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// Documents below were encrypted with sops 3.10.2 using the test only age key sopsAgeKey
+
+const (
+	sopsAgeKey = `# created: 2026-10-18T15:43:23Z
+# public key: age10uuxakepfn9vq5r0fnll0p0f3t5ydgjk0vsy4my90hjpp2qg6ykqqn2jqs
+AGE-SECRET-KEY-1Q6HJ7FD2Q5XSS578AYX4T2JA20LCSPZ6E3HJ00P00XV255AYVPQQ7GZXDP
+`
+
+	sopsEncryptedYAML = `apiVersion: ENC[AES256_GCM,data:EV0=,iv:N35w83iXKqKhaY50xahrY5pVjZRul7SXQh+0VMS2Fdo=,tag:HZEFJHRtQvMBrMUC8komDw==,type:str]
+kind: ENC[AES256_GCM,data:+cqxQV8C,iv:k9Wzr9Io3CDyqlkaKlVDDpZCKmiA0P7NU6d+OgErduY=,tag:bd2jOy5nEhabhtXUCzy+KA==,type:str]
+metadata:
+    name: ENC[AES256_GCM,data:K474MyNHXSLZXi50VCI=,iv:BIATzBZv9sC5wpmRbinee7IiwX2C2fm1GxrSz8/lXbQ=,tag:ce2qxZ1wK8UuST9fuNdcxA==,type:str]
+    namespace: ENC[AES256_GCM,data:9MYzC4qRKF4SBwj1DTfyDGe2NFtla5LrlaoPJZnq/Ur3NQ==,iv:XfphyHcVJ5UiO9lttyPXfbNFJWh1dyvXV+3u3D04L6c=,tag:eUmEhR/41NsbhutFv3mhcw==,type:str]
+stringData:
+    password: ENC[AES256_GCM,data:7R/O0ynC,iv:1TSCBMBdH+fyDTdO09KCY3WAqq5T42isfGDRyQOeB04=,tag:ybCWiBp+jPdoQlqyoKNOSQ==,type:str]
+    port: ENC[AES256_GCM,data:29MHgw==,iv:ulyA0Tz3SpwBG1xi6j8imuSsZ+tJTckvN+lTO94qoWA=,tag:tWSY20o4qIDAowpM0CpeOw==,type:int]
+    ratio: ENC[AES256_GCM,data:V9kp,iv:bbU0WVdIOVWMG334LMfbM7KrjwvEJazOB6G0cYgTDS0=,tag:XGFIcWMmi7RbDVnJhZGTlA==,type:float]
+    enabled: ENC[AES256_GCM,data:Diyihw==,iv:+7VH1g5VCevmuRsgEbV8pw731YhfD3SYz7NKSZt37Ks=,tag:yJ1vBYEQD3t7FMgS7urqvg==,type:bool]
+    list:
+        - ENC[AES256_GCM,data:4Q==,iv:xqvtyvQXfjgxArPZA9aRWqBusb+ROs3k5e6K/EbHZtY=,tag:+NOV1l6py1UVoRkAXkW1/Q==,type:str]
+        - ENC[AES256_GCM,data:zA==,iv:PeaCTqA1/AFsTwJIMlCS2RmQScFJqyphHdJwiTAHII0=,tag:pPML9oJe+YUrRPZ0hKBlPw==,type:int]
+sops:
+    age:
+        - recipient: age10uuxakepfn9vq5r0fnll0p0f3t5ydgjk0vsy4my90hjpp2qg6ykqqn2jqs
+          enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBCSHdJRDVsRUlpd2RNdUVZ
+            OWdQL0JvVU92ZjRqSHVFcWliUUJBSVg5U0dVCjZjVEdPdVdad0Y0NTY0RXlDTGtO
+            dUxIdklYNXZ2NnhadzBzK2RScllnT3MKLS0tIHlBekVXVG5KQnpjU3RPeFBjaitU
+            RnBZQTZhcy9USk8zcFNlUTl4bStYQk0KdxKnK1vbTdYuxaiM/LNSGCPTF/2zds8M
+            8MCMf+5EPs1mLaeCfKPrLdv//hjfWCeV6WmTPrTsnYM61Fo3GC4ghA==
+            -----END AGE ENCRYPTED FILE-----
+    lastmodified: "2026-10-18T15:43:26Z"
+    mac: ENC[AES256_GCM,data:3sy9fdmtYh+2hvxgeOATNhxXgZ9vrLWBSUo3Thw8DuVKtMjEw84SYFSR8vFyijqMJtc8OfDPe+oNRum670U68fPhtSluNzWOzhf19D17JrvfJjlqe9xNtx0FKNq0ygoLEV6bwEKCddUHVYmf0NnzvQBsE4b7R8KUTmVcG+oqh00=,iv:DkYYF+YmJA81RFqoP4f5k/83+6bvBDcXy7LAZ2T5iKs=,tag:xSTcKcbCjOb9362jmIPjWg==,type:str]
+    unencrypted_suffix: _unencrypted
+    version: 3.10.2
+---
+apiVersion: ENC[AES256_GCM,data:cp8=,iv:Fyjzg7u3BrumspVI+F8IjaQx6jW8t+V+eDpTf4R2NOc=,tag:1WL6v7lNoLdtBUTzzva/og==,type:str]
+kind: ENC[AES256_GCM,data:0hkwQ1E9Fygi,iv:FkIvLk/6TDgnBqJVuxy6NU2JtWUEevMdQQH3KWzLD6E=,tag:OOrhmIell9BbanoX6oSxSQ==,type:str]
+metadata:
+    name: ENC[AES256_GCM,data:+f5yQ6o=,iv:Cso9O/bF/ANz/skQM/EYUAxJuU8CEVV2IGJg/YfJSXk=,tag:k516LARa0PfnB1uykXr5AQ==,type:str]
+data:
+    key: ENC[AES256_GCM,data:JBwHk8c=,iv:uC/X5i2oAtObP+7DZaLXgBP4jxuLA1DFMDMtA8jjONA=,tag:PyT1T+K9Xf0r8hIeZ122kg==,type:str]
+sops:
+    age:
+        - recipient: age10uuxakepfn9vq5r0fnll0p0f3t5ydgjk0vsy4my90hjpp2qg6ykqqn2jqs
+          enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBCSHdJRDVsRUlpd2RNdUVZ
+            OWdQL0JvVU92ZjRqSHVFcWliUUJBSVg5U0dVCjZjVEdPdVdad0Y0NTY0RXlDTGtO
+            dUxIdklYNXZ2NnhadzBzK2RScllnT3MKLS0tIHlBekVXVG5KQnpjU3RPeFBjaitU
+            RnBZQTZhcy9USk8zcFNlUTl4bStYQk0KdxKnK1vbTdYuxaiM/LNSGCPTF/2zds8M
+            8MCMf+5EPs1mLaeCfKPrLdv//hjfWCeV6WmTPrTsnYM61Fo3GC4ghA==
+            -----END AGE ENCRYPTED FILE-----
+    lastmodified: "2026-10-18T15:43:26Z"
+    mac: ENC[AES256_GCM,data:3sy9fdmtYh+2hvxgeOATNhxXgZ9vrLWBSUo3Thw8DuVKtMjEw84SYFSR8vFyijqMJtc8OfDPe+oNRum670U68fPhtSluNzWOzhf19D17JrvfJjlqe9xNtx0FKNq0ygoLEV6bwEKCddUHVYmf0NnzvQBsE4b7R8KUTmVcG+oqh00=,iv:DkYYF+YmJA81RFqoP4f5k/83+6bvBDcXy7LAZ2T5iKs=,tag:xSTcKcbCjOb9362jmIPjWg==,type:str]
+    unencrypted_suffix: _unencrypted
+    version: 3.10.2
+`
+
+	sopsEncryptedJSON = `{
+	"user": "ENC[AES256_GCM,data:01hDLiQ=,iv:iBOtT4ndGm5XRv7/oMl2v+z6N9ANKkH9+kld6/FdKPE=,tag:odlLE994GJkW9h0BiaEbbQ==,type:str]",
+	"count": "ENC[AES256_GCM,data:JQ==,iv:cSdRX4M2HQ39iVxPyFusTlxkr6vVxMG6P/MxZma1dwk=,tag:6eZcbmOsLw/iaMzcdL9CEg==,type:float]",
+	"nested": {
+		"on": "ENC[AES256_GCM,data:Xg0j5Hs=,iv:sYevW6Ba7u4YpGMpUWjCaAuSsRbeKkFINOidP7jOr3U=,tag:bV7pVXHx7XCBWOTEWZy+0Q==,type:bool]"
+	},
+	"sops": {
+		"age": [
+			{
+				"recipient": "age10uuxakepfn9vq5r0fnll0p0f3t5ydgjk0vsy4my90hjpp2qg6ykqqn2jqs",
+				"enc": "-----BEGIN AGE ENCRYPTED FILE-----\nYWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBpUnk5cnJOVHVyQXAxeXd4\nVDNwUEVVOHVGYS9ZQnlYRFY2MFRlWk5WWEFzCnhJOTA4WUJ2NThkdWZYaXgyWXU3\nWU1CMFUwYVVXcG1KRWJyWmQxR1NZUjgKLS0tIFN6TlgzVTZpMUZlQjlhK01rZVVF\ndmNhcDJVQlZqSXZKbWFLYm5SRlNiOGsKSh25awx+rx2wye42H8RLIeYggHKD+YZ6\nkX8Q40KwvYt9xTrVLIHVxqgNAB2cG/XFmQEYg3xkNdrNyq/hcMRN8Q==\n-----END AGE ENCRYPTED FILE-----\n"
+			}
+		],
+		"lastmodified": "2026-10-18T15:43:26Z",
+		"mac": "ENC[AES256_GCM,data:l+CsTnpjBeKY/h7QIiKh7A+SDAffOsvEPD+V00XHD8iNAVVgkWZbOxtjZK9hvCWhId998zqDPEHKIVQ4SiOTH56CBj3RlKKibPBkG7HfHeUOllRYFyzVz3SlC84Cgt1NmdRV0AtIe4unGo5fiPCtDWfiIYmZztAp7tA9PymCfRI=,iv:9oFQ5K9b5pwnJU4qVoeJghaR5tnzPHIqs5bt5/tXEVU=,tag:IlzFlxAEsQJf5/CBAaF4kg==,type:str]",
+		"unencrypted_suffix": "_unencrypted",
+		"version": "3.10.2"
+	}
+}
+`
+
+	// encrypted with unencrypted_suffix _unencrypted and mac_only_encrypted
+	sopsEncryptedMACOnlyYAML = `name_unencrypted: visible
+secret: ENC[AES256_GCM,data:WWUNPdVP,iv:046L67/wY1hRmvALjFvhlApP+ehzb8kp6I/e7O8pJsw=,tag:kiO5ci7FWGxUzaTQGcILKg==,type:str]
+num: ENC[AES256_GCM,data:Sg==,iv:3/g3/okWBT/fmtx3T3yJyAs4bnzBzdhpQcyNLHuT6jQ=,tag:m8jcONnvpCPTzgIyPY738g==,type:int]
+sops:
+    age:
+        - recipient: age10uuxakepfn9vq5r0fnll0p0f3t5ydgjk0vsy4my90hjpp2qg6ykqqn2jqs
+          enc: |
+            -----BEGIN AGE ENCRYPTED FILE-----
+            YWdlLWVuY3J5cHRpb24ub3JnL3YxCi0+IFgyNTUxOSBnQy93N1NkYjRwd3IyOWQy
+            eTVrMEw3ZXlXaU1iVW1Xam1XYlFXcmJmY0VnCklLY0JseWtYWTMyWUU1RTR0eUIz
+            TkNyRXdDeWdEMXA1Z3l2em05SVpTS2MKLS0tIDRmbDdQalU0Ty9YakVTc0M0OWN2
+            ZzZYeTNXRWJPY2V5YndRSFVFZG82ZjAKloYQhQNOoCXakZMuKoN9d+8XmaYvPeeM
+            MIS7ZtqPv+lttJC2Rkeq9VWa9frQN2lS54XWtyEMHRp+BLiXMgJH+Q==
+            -----END AGE ENCRYPTED FILE-----
+    lastmodified: "2026-10-18T15:43:29Z"
+    mac: ENC[AES256_GCM,data:FmL5Ym5NuAyPaspOsC51Xv1ASOLpXXprf0ESf/dHWS44VbG4hK/pcVnRFwjhUArRkQg43+AxvpacsFqq7OQWzY1e09Lrorh8zUFll/SpNzAnpCG9L8+qqROFybXmqharC1WTyXWyl/QLl/Z8gJ1+M7zAKPxk6LJuGCeIVy/2B9U=,iv:DPzX3GY/aGhiw/vP1pMfdBlrqOVJQUUyWeCwp9OLIhc=,tag:QsYe+reFMID3a2yISKDPqQ==,type:str]
+    unencrypted_suffix: _unencrypted
+    mac_only_encrypted: true
+    version: 3.10.2
+`
+)
+
+var _ = Describe("SOPS", func() {
+	var keySecret *corev1.Secret
+
+	BeforeEach(func() {
+		keySecret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+			Data: map[string][]byte{
+				"keys.txt": []byte(sopsAgeKey),
+			},
+		}
+
+		controllers.SetSopsAgeKeySecret(keySecret.Namespace, keySecret.Name)
+	})
+
+	getClient := func() client.Client {
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(keySecret).Build()
+	}
+
+	It("decryptSopsContent decrypts YAML documents", func() {
+		content := map[string]string{"policy.yaml": sopsEncryptedYAML}
+
+		result, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).To(BeNil())
+
+		decrypted := result["policy.yaml"]
+		Expect(decrypted).ToNot(ContainSubstring("ENC["))
+		Expect(decrypted).ToNot(ContainSubstring("sops:"))
+		Expect(decrypted).To(ContainSubstring("password: s3cr3t"))
+		Expect(decrypted).To(ContainSubstring("port: 5432"))
+		Expect(decrypted).To(ContainSubstring("ratio: 1.5"))
+		Expect(decrypted).To(ContainSubstring("enabled: true"))
+		Expect(decrypted).To(ContainSubstring("{{ .Resource.metadata.namespace }}"))
+		// Both documents are kept
+		Expect(strings.Count(decrypted, "apiVersion: v1")).To(Equal(2))
+	})
+
+	It("decryptSopsContent decrypts JSON documents", func() {
+		content := map[string]string{"values.json": sopsEncryptedJSON}
+
+		result, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).To(BeNil())
+		Expect(result["values.json"]).To(MatchJSON(`{"user":"admin","count":3,"nested":{"on":false}}`))
+	})
+
+	It("decryptSopsContent honors unencrypted_suffix and mac_only_encrypted", func() {
+		content := map[string]string{"values.yaml": sopsEncryptedMACOnlyYAML}
+
+		result, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).To(BeNil())
+		Expect(result["values.yaml"]).To(ContainSubstring("name_unencrypted: visible"))
+		Expect(result["values.yaml"]).To(ContainSubstring("secret: hidden"))
+		Expect(result["values.yaml"]).To(ContainSubstring("num: 7"))
+	})
+
+	It("decryptSopsContent fails when content was tampered with", func() {
+		// Remove one encrypted value
+		lines := strings.Split(sopsEncryptedYAML, "\n")
+		tampered := make([]string, 0, len(lines))
+		for i := range lines {
+			if !strings.Contains(lines[i], "enabled:") {
+				tampered = append(tampered, lines[i])
+			}
+		}
+		content := map[string]string{"policy.yaml": strings.Join(tampered, "\n")}
+
+		_, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("MAC mismatch"))
+	})
+
+	It("decryptSopsContent refuses documents using Shamir secret sharing", func() {
+		withKeyGroups := strings.Replace(sopsEncryptedMACOnlyYAML, "    version: 3.10.2",
+			"    key_groups:\n        - age: []\n        - age: []\n    version: 3.10.2", 1)
+		content := map[string]string{"values.yaml": withKeyGroups}
+
+		_, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).ToNot(BeNil())
+		Expect(err.Error()).To(ContainSubstring("Shamir"))
+	})
+
+	It("decryptSopsContent fails with the wrong age key", func() {
+		identity, err := age.GenerateX25519Identity()
+		Expect(err).To(BeNil())
+		keySecret.Data = map[string][]byte{"keys.txt": []byte(identity.String())}
+
+		content := map[string]string{"policy.yaml": sopsEncryptedYAML}
+		_, err = controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("decryptSopsContent fails when content is not SOPS encrypted", func() {
+		content := map[string]string{"policy.yaml": "apiVersion: v1\nkind: Namespace\n"}
+
+		_, err := controllers.DecryptSopsContent(context.TODO(), getClient(), content)
+		Expect(err).ToNot(BeNil())
+	})
+
+	It("validateSopsAccess refuses EventTriggers created by tenant admins", func() {
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+		}
+		Expect(controllers.ValidateSopsAccess(eventTrigger)).To(Succeed())
+
+		eventTrigger.Labels = map[string]string{
+			libsveltosv1beta1.ServiceAccountNamespaceLabel: randomString(),
+			libsveltosv1beta1.ServiceAccountNameLabel:      randomString(),
+		}
+		Expect(controllers.ValidateSopsAccess(eventTrigger)).ToNot(Succeed())
+	})
+
+	It("getInstantiatedKind returns Secret for SOPS encrypted ConfigMaps", func() {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+				Annotations: map[string]string{
+					v1beta1.InstantiateAnnotation: "ok",
+				},
+			},
+		}
+		Expect(addTypeInformationToObject(scheme, configMap)).To(Succeed())
+		Expect(controllers.GetInstantiatedKind(configMap)).To(
+			Equal(string(libsveltosv1beta1.ConfigMapReferencedResourceKind)))
+
+		configMap.Annotations[v1beta1.SopsEncryptedAnnotation] = "ok"
+		Expect(controllers.GetInstantiatedKind(configMap)).To(
+			Equal(string(libsveltosv1beta1.SecretReferencedResourceKind)))
+	})
+})
@@ -0,0 +1,407 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/funcmap"
+)
+
+// Templates in EventTriggers are instantiated by event-manager. The template policy limits
+// which template functions can be used (for instance functions reading event-manager environment),
+// how long a template can take to render and how large the rendered output can be.
+// The policy is set at manager level. The allowed functions can be overridden per tenant by the
+// platform admin, setting the TemplateAllowedFunctionsAnnotation on the tenant RoleRequests.
+// Template execution cannot be interrupted, so for EventTriggers created by tenant admins, and for
+// all EventTriggers when a render timeout is set, the functions producing loops or large output
+// (until, untilStep, seq and repeat) are bounded. This guarantees that an execution which timed out
+// still terminates.
+// The tenant template policy is resolved once per instantiation (see resolveTemplatePolicy).
+
+const (
+	// allTemplateFunctions can be used in the allowed functions list to allow all functions
+	allTemplateFunctions = "*"
+
+	// maxTemplateLoopIterations is the maximum number of items, across all calls, the bounded loop
+	// functions can produce while a template is rendered
+	maxTemplateLoopIterations = 10000
+
+	// maxTemplateRepeatSize is the maximum size, in bytes, of the output of the bounded repeat function
+	// when policy sets no maximum output size
+	maxTemplateRepeatSize = 1 << 20
+)
+
+// templatePolicyViolationError is returned when a template violates the template policy
+type templatePolicyViolationError struct {
+	reason string
+}
+
+func (e *templatePolicyViolationError) Error() string {
+	return fmt.Sprintf("template policy violation: %s", e.reason)
+}
+
+func isTemplatePolicyViolation(err error) bool {
+	var violationErr *templatePolicyViolationError
+	return errors.As(err, &violationErr)
+}
+
+type templatePolicy struct {
+	// allowedFunctions contains the template functions templates can use. All functions
+	// are allowed when nil.
+	allowedFunctions map[string]bool
+
+	// renderTimeout is the maximum time a template can take to render. No limit if zero.
+	renderTimeout time.Duration
+
+	// maxOutputSize is the maximum size, in bytes, of a rendered template. No limit if zero.
+	maxOutputSize int
+
+	// boundedFunctions, when set, limits the number of items produced by loop functions
+	// and the size of the output of repeat
+	boundedFunctions bool
+}
+
+var (
+	managerTemplatePolicy     = &templatePolicy{}
+	managerTemplatePolicyLock = &sync.RWMutex{}
+
+	// tenantTemplatePolicies contains, per EventTrigger name, the template policy resolved when
+	// the EventTrigger created by a tenant admin was last instantiated
+	tenantTemplatePolicies     = make(map[string]*templatePolicy)
+	tenantTemplatePoliciesLock = &sync.RWMutex{}
+)
+
+// SetTemplatePolicy sets the manager level template policy. An empty allowedFunctions list (or one
+// containing "*") allows all template functions.
+func SetTemplatePolicy(allowedFunctions []string, renderTimeout time.Duration, maxOutputSize int) {
+	managerTemplatePolicyLock.Lock()
+	defer managerTemplatePolicyLock.Unlock()
+
+	managerTemplatePolicy = &templatePolicy{
+		allowedFunctions: getAllowedFunctions(allowedFunctions),
+		renderTimeout:    renderTimeout,
+		maxOutputSize:    maxOutputSize,
+	}
+}
+
+func getManagerTemplatePolicy() *templatePolicy {
+	managerTemplatePolicyLock.RLock()
+	defer managerTemplatePolicyLock.RUnlock()
+
+	return managerTemplatePolicy
+}
+
+func getAllowedFunctions(allowedFunctions []string) map[string]bool {
+	if len(allowedFunctions) == 0 {
+		return nil
+	}
+
+	allowed := make(map[string]bool, len(allowedFunctions))
+	for i := range allowedFunctions {
+		f := strings.TrimSpace(allowedFunctions[i])
+		if f == allTemplateFunctions {
+			return nil
+		}
+		if f != "" {
+			allowed[f] = true
+		}
+	}
+
+	return allowed
+}
+
+// resolveTemplatePolicy computes the template policy to use for EventTrigger e and keeps it till
+// next instantiation. For EventTriggers created by a tenant admin, the allowed functions set on the
+// tenant RoleRequests (if any) override the manager level ones and loop/size functions are bounded.
+// Must be called before instantiating e.
+func resolveTemplatePolicy(ctx context.Context, c client.Client, e *v1beta1.EventTrigger) error {
+	saNamespace, saName, ok := getTenantServiceAccount(e)
+	if !ok {
+		return nil
+	}
+
+	policy := getManagerTemplatePolicy()
+
+	roleRequests := &libsveltosv1beta1.RoleRequestList{}
+	if err := c.List(ctx, roleRequests); err != nil {
+		return err
+	}
+
+	var overridden bool
+	var allowedFunctions []string
+	for i := range roleRequests.Items {
+		rr := &roleRequests.Items[i]
+		if rr.Spec.ServiceAccountNamespace != saNamespace || rr.Spec.ServiceAccountName != saName {
+			continue
+		}
+		value, ok := rr.Annotations[v1beta1.TemplateAllowedFunctionsAnnotation]
+		if !ok {
+			continue
+		}
+		overridden = true
+		allowedFunctions = append(allowedFunctions, strings.Split(value, ",")...)
+	}
+
+	tenantPolicy := &templatePolicy{
+		allowedFunctions: policy.allowedFunctions,
+		renderTimeout:    policy.renderTimeout,
+		maxOutputSize:    policy.maxOutputSize,
+		boundedFunctions: true,
+	}
+	if overridden {
+		// An empty annotation allows no function, "*" allows all functions
+		tenantPolicy.allowedFunctions = getAllowedFunctions(allowedFunctions)
+	}
+
+	tenantTemplatePoliciesLock.Lock()
+	defer tenantTemplatePoliciesLock.Unlock()
+	tenantTemplatePolicies[e.Name] = tenantPolicy
+
+	return nil
+}
+
+// removeTemplatePolicy forgets the template policy resolved for an EventTrigger
+func removeTemplatePolicy(eventTriggerName string) {
+	tenantTemplatePoliciesLock.Lock()
+	defer tenantTemplatePoliciesLock.Unlock()
+
+	delete(tenantTemplatePolicies, eventTriggerName)
+}
+
+// getTemplatePolicy returns the template policy to use for EventTrigger e. For EventTriggers
+// created by a tenant admin, the policy computed by resolveTemplatePolicy is returned. If it was
+// never resolved, no template function is allowed.
+func getTemplatePolicy(e *v1beta1.EventTrigger) *templatePolicy {
+	policy := getManagerTemplatePolicy()
+
+	if _, _, ok := getTenantServiceAccount(e); !ok {
+		return policy
+	}
+
+	tenantTemplatePoliciesLock.RLock()
+	defer tenantTemplatePoliciesLock.RUnlock()
+
+	if tenantPolicy, ok := tenantTemplatePolicies[e.Name]; ok {
+		return tenantPolicy
+	}
+
+	return &templatePolicy{
+		allowedFunctions: map[string]bool{},
+		renderTimeout:    policy.renderTimeout,
+		maxOutputSize:    policy.maxOutputSize,
+		boundedFunctions: true,
+	}
+}
+
+// getTemplateFuncMap returns the Sveltos template functions. Functions not allowed by policy
+// fail when called.
+func getTemplateFuncMap(useTxtFuncMap bool, policy *templatePolicy) template.FuncMap {
+	funcMap := funcmap.SveltosFuncMap(useTxtFuncMap)
+	if policy.boundedFunctions || policy.renderTimeout > 0 {
+		setBoundedTemplateFunctions(funcMap, policy)
+	}
+
+	if policy.allowedFunctions == nil {
+		return funcMap
+	}
+
+	for name := range funcMap {
+		if !policy.allowedFunctions[name] {
+			funcMap[name] = notAllowedTemplateFunction(name)
+		}
+	}
+
+	return funcMap
+}
+
+// setBoundedTemplateFunctions replaces the loop functions (until, untilStep and seq) with versions
+// failing once, across all calls, more than maxTemplateLoopIterations items are produced, and repeat
+// with a version failing when output would exceed the maximum output size.
+// funcMap must be used by a single template.
+func setBoundedTemplateFunctions(funcMap template.FuncMap, policy *templatePolicy) {
+	iterations := 0
+	consume := func(name string, count int) error {
+		iterations += count
+		if iterations > maxTemplateLoopIterations {
+			return &templatePolicyViolationError{
+				reason: fmt.Sprintf("function %q exceeds maximum of %d loop iterations", name,
+					maxTemplateLoopIterations)}
+		}
+		return nil
+	}
+
+	if until, ok := funcMap["until"].(func(int) []int); ok {
+		funcMap["until"] = func(count int) ([]int, error) {
+			if err := consume("until", absInt(count)); err != nil {
+				return nil, err
+			}
+			return until(count), nil
+		}
+	} else {
+		funcMap["until"] = notAllowedTemplateFunction("until")
+	}
+
+	if untilStep, ok := funcMap["untilStep"].(func(int, int, int) []int); ok {
+		funcMap["untilStep"] = func(start, stop, step int) ([]int, error) {
+			count := 0
+			if step != 0 {
+				count = absInt(stop-start)/absInt(step) + 1
+			}
+			if err := consume("untilStep", count); err != nil {
+				return nil, err
+			}
+			return untilStep(start, stop, step), nil
+		}
+	} else {
+		funcMap["untilStep"] = notAllowedTemplateFunction("untilStep")
+	}
+
+	if seq, ok := funcMap["seq"].(func(...int) string); ok {
+		funcMap["seq"] = func(params ...int) (string, error) {
+			if err := consume("seq", getSeqLength(params)); err != nil {
+				return "", err
+			}
+			return seq(params...), nil
+		}
+	} else {
+		funcMap["seq"] = notAllowedTemplateFunction("seq")
+	}
+
+	maxSize := maxTemplateRepeatSize
+	if policy.maxOutputSize > 0 && policy.maxOutputSize < maxSize {
+		maxSize = policy.maxOutputSize
+	}
+	funcMap["repeat"] = func(count int, str string) (string, error) {
+		if count > 0 && len(str) > 0 && count > maxSize/len(str) {
+			return "", &templatePolicyViolationError{
+				reason: fmt.Sprintf("function \"repeat\" output exceeds maximum size of %d bytes", maxSize)}
+		}
+		return strings.Repeat(str, max(count, 0)), nil
+	}
+}
+
+// getSeqLength returns the number of items the sprig seq function produces for params
+func getSeqLength(params []int) int {
+	const startEnd, startStepEnd = 2, 3
+	switch len(params) {
+	case 1:
+		return absInt(params[0]-1) + 1
+	case startEnd:
+		return absInt(params[1]-params[0]) + 1
+	case startStepEnd:
+		if params[1] == 0 {
+			return 0
+		}
+		return absInt(params[2]-params[0])/absInt(params[1]) + 1
+	default:
+		return 0
+	}
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func notAllowedTemplateFunction(name string) func(...any) (any, error) {
+	return func(...any) (any, error) {
+		return nil, &templatePolicyViolationError{reason: fmt.Sprintf("function %q is not allowed", name)}
+	}
+}
+
+// newTemplate returns a template using the functions allowed by policy. If policy is nil,
+// manager level template policy is used.
+func newTemplate(templateName string, useTxtFuncMap bool, policy *templatePolicy) *template.Template {
+	if policy == nil {
+		policy = getManagerTemplatePolicy()
+	}
+
+	return template.New(templateName).Option("missingkey=error").Funcs(getTemplateFuncMap(useTxtFuncMap, policy))
+}
+
+// limitedBuffer is a buffer failing writes once maxSize is exceeded or once it is cancelled
+type limitedBuffer struct {
+	buffer    bytes.Buffer
+	maxSize   int
+	cancelled atomic.Bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.cancelled.Load() {
+		return 0, &templatePolicyViolationError{reason: "render timeout exceeded"}
+	}
+	if b.maxSize > 0 && b.buffer.Len()+len(p) > b.maxSize {
+		return 0, &templatePolicyViolationError{
+			reason: fmt.Sprintf("rendered output exceeds maximum size of %d bytes", b.maxSize)}
+	}
+	return b.buffer.Write(p)
+}
+
+// executeTemplate executes tmpl enforcing policy render timeout and maximum output size. If policy
+// is nil, manager level template policy is used.
+// Template execution cannot be interrupted. On timeout, an error is returned right away while the
+// execution keeps running in background till it writes output (which then fails) or completes.
+// Whenever a render timeout is set, loops are bounded (see setBoundedTemplateFunctions) so such
+// background execution always terminates.
+func executeTemplate(tmpl *template.Template, data any, policy *templatePolicy) ([]byte, error) {
+	if policy == nil {
+		policy = getManagerTemplatePolicy()
+	}
+
+	output := &limitedBuffer{maxSize: policy.maxOutputSize}
+	if policy.renderTimeout == 0 {
+		if err := tmpl.Execute(output, data); err != nil {
+			return nil, err
+		}
+		return output.buffer.Bytes(), nil
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		result <- tmpl.Execute(output, data)
+	}()
+
+	timer := time.NewTimer(policy.renderTimeout)
+	defer timer.Stop()
+
+	select {
+	case err := <-result:
+		if err != nil {
+			return nil, err
+		}
+		return output.buffer.Bytes(), nil
+	case <-timer.C:
+		output.cancelled.Store(true)
+		return nil, &templatePolicyViolationError{
+			reason: fmt.Sprintf("render timeout of %s exceeded", policy.renderTimeout)}
+	}
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Template policy", func() {
+	logger := textlogger.NewLogger(textlogger.NewConfig(textlogger.Verbosity(1)))
+
+	AfterEach(func() {
+		controllers.SetTemplatePolicy(nil, 0, 0)
+	})
+
+	It("instantiateSection allows all functions by default", func() {
+		controllers.SetTemplatePolicy(nil, time.Minute, 0)
+
+		result, err := controllers.InstantiateSection(randomString(), []byte(`{{ upper "sveltos" }}`), nil,
+			false, nil, logger)
+		Expect(err).To(BeNil())
+		Expect(string(result)).To(Equal("SVELTOS"))
+	})
+
+	It("instantiateSection fails when template uses a function not allowed", func() {
+		controllers.SetTemplatePolicy([]string{"upper", "lower"}, time.Minute, 0)
+
+		result, err := controllers.InstantiateSection(randomString(), []byte(`{{ upper "sveltos" }}`), nil,
+			false, nil, logger)
+		Expect(err).To(BeNil())
+		Expect(string(result)).To(Equal("SVELTOS"))
+
+		_, err = controllers.InstantiateSection(randomString(), []byte(`{{ env "HOME" }}`), nil,
+			false, nil, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+	})
+
+	It("instantiateSection fails when rendered output exceeds maximum size", func() {
+		const maxOutputSize = 16
+		controllers.SetTemplatePolicy(nil, time.Minute, maxOutputSize)
+
+		_, err := controllers.InstantiateSection(randomString(), []byte(`{{ repeat 100 "a" }}`), nil,
+			false, nil, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+
+		_, err = controllers.InstantiateSection(randomString(), []byte(`{{ repeat 10 "a" }}`), nil,
+			false, nil, logger)
+		Expect(err).To(BeNil())
+	})
+
+	It("instantiateSection fails when render timeout is exceeded", func() {
+		controllers.SetTemplatePolicy(nil, time.Millisecond, 0)
+
+		_, err := controllers.InstantiateSection(randomString(),
+			[]byte(`{{ range $i := until 1000000 }}{{ $i }}{{ end }}`), nil, false, nil, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+	})
+
+	It("loop functions are bounded only when render timeout is set", func() {
+		template := []byte(`{{ range $i := until 1000 }}{{ range $j := until 1000 }}{{ end }}{{ end }}`)
+
+		controllers.SetTemplatePolicy(nil, time.Minute, 0)
+		_, err := controllers.InstantiateSection(randomString(), template, nil, false, nil, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+
+		controllers.SetTemplatePolicy(nil, 0, 0)
+		_, err = controllers.InstantiateSection(randomString(), template, nil, false, nil, logger)
+		Expect(err).To(BeNil())
+	})
+
+	It("loop and repeat functions are bounded for EventTriggers created by tenant admins", func() {
+		controllers.SetTemplatePolicy(nil, time.Minute, 0)
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.ServiceAccountNamespaceLabel: randomString(),
+					libsveltosv1beta1.ServiceAccountNameLabel:      randomString(),
+				},
+			},
+		}
+
+		// Template policy not resolved yet: no function is allowed
+		_, err := controllers.InstantiateSection(randomString(), []byte(`{{ upper "sveltos" }}`), nil,
+			false, controllers.GetTemplatePolicy(eventTrigger), logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		Expect(controllers.ResolveTemplatePolicy(context.TODO(), c, eventTrigger)).To(Succeed())
+		defer controllers.RemoveTemplatePolicy(eventTrigger.Name)
+		policy := controllers.GetTemplatePolicy(eventTrigger)
+
+		result, err := controllers.InstantiateSection(randomString(),
+			[]byte(`{{ range $i := until 3 }}{{ $i }}{{ end }}{{ repeat 2 "a" }}`), nil, false, policy, logger)
+		Expect(err).To(BeNil())
+		Expect(string(result)).To(Equal("012aa"))
+
+		_, err = controllers.InstantiateSection(randomString(),
+			[]byte(`{{ range $i := until 1000 }}{{ range $j := until 1000 }}{{ end }}{{ end }}`), nil, false,
+			policy, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+
+		_, err = controllers.InstantiateSection(randomString(), []byte(`{{ seq 1 100000 }}`), nil, false,
+			policy, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+
+		_, err = controllers.InstantiateSection(randomString(), []byte(`{{ repeat 100000000 "a" }}`), nil, false,
+			policy, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTemplatePolicyViolation(err)).To(BeTrue())
+	})
+})
@@ -0,0 +1,206 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+// EventTriggers created by tenant admins carry the tenant service account labels. event-manager
+// reads referenced ConfigMaps, Secrets and Flux Sources, and creates generated resources, with its
+// own permissions. So before reading or writing any of those on behalf of a tenant EventTrigger,
+// a SubjectAccessReview verifies the tenant service account is allowed to do so. Results are cached
+// for tenantAccessCacheTTL.
+
+const (
+	tenantAccessCacheTTL = 30 * time.Second
+)
+
+// tenantAccessDeniedError is returned when the tenant service account is not allowed to read
+// a resource referenced by the EventTrigger or to write a resource generated by it
+type tenantAccessDeniedError struct {
+	serviceAccount string
+	verb           string
+	resource       string
+	namespace      string
+	name           string
+}
+
+func (e *tenantAccessDeniedError) Error() string {
+	return fmt.Sprintf("service account %s is not allowed to %s %s %s/%s",
+		e.serviceAccount, e.verb, e.resource, e.namespace, e.name)
+}
+
+func isTenantAccessDenied(err error) bool {
+	var deniedErr *tenantAccessDeniedError
+	return errors.As(err, &deniedErr)
+}
+
+type tenantAccessEntry struct {
+	allowed    bool
+	expiration time.Time
+}
+
+type tenantAccessCache struct {
+	mux sync.Mutex
+
+	// key: service account, resource, namespace and name
+	entries   map[string]*tenantAccessEntry
+	lastSweep time.Time
+}
+
+var (
+	tenantAccessCacheInstance *tenantAccessCache
+	tenantAccessCacheLock     = &sync.Mutex{}
+)
+
+func getTenantAccessCache() *tenantAccessCache {
+	if tenantAccessCacheInstance == nil {
+		tenantAccessCacheLock.Lock()
+		defer tenantAccessCacheLock.Unlock()
+		if tenantAccessCacheInstance == nil {
+			tenantAccessCacheInstance = &tenantAccessCache{
+				entries: make(map[string]*tenantAccessEntry),
+			}
+		}
+	}
+
+	return tenantAccessCacheInstance
+}
+
+func (t *tenantAccessCache) get(key string, now time.Time) (allowed, ok bool) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok || now.After(entry.expiration) {
+		return false, false
+	}
+	return entry.allowed, true
+}
+
+func (t *tenantAccessCache) set(key string, allowed bool, now time.Time) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if now.Sub(t.lastSweep) > tenantAccessCacheTTL {
+		t.lastSweep = now
+		for k, entry := range t.entries {
+			if now.After(entry.expiration) {
+				delete(t.entries, k)
+			}
+		}
+	}
+
+	t.entries[key] = &tenantAccessEntry{allowed: allowed, expiration: now.Add(tenantAccessCacheTTL)}
+}
+
+// getTenantServiceAccount returns the service account of the tenant admin who created the
+// EventTrigger. ok is false if EventTrigger was not created by a tenant admin.
+func getTenantServiceAccount(e *v1beta1.EventTrigger) (namespace, name string, ok bool) {
+	if e == nil {
+		return "", "", false
+	}
+
+	name, ok = e.Labels[libsveltosv1beta1.ServiceAccountNameLabel]
+	if !ok {
+		return "", "", false
+	}
+	namespace = e.Labels[libsveltosv1beta1.ServiceAccountNamespaceLabel]
+	return namespace, name, true
+}
+
+// checkTenantAccess returns a tenantAccessDeniedError if EventTrigger e was created by a tenant admin
+// whose service account is not allowed to get the resource (identified by API group and plural
+// resource name) namespace/name
+func checkTenantAccess(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	group, resource, namespace, name string) error {
+
+	return checkTenantAccessForVerb(ctx, c, e, "get", group, resource, namespace, name)
+}
+
+// checkTenantWriteAccess returns a tenantAccessDeniedError if EventTrigger e was created by a tenant
+// admin whose service account is not allowed to create and update the resource (identified by API
+// group and plural resource name) namespace/name
+func checkTenantWriteAccess(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	group, resource, namespace, name string) error {
+
+	for _, verb := range []string{"create", "update"} {
+		if err := checkTenantAccessForVerb(ctx, c, e, verb, group, resource, namespace, name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkTenantAccessForVerb(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	verb, group, resource, namespace, name string) error {
+
+	saNamespace, saName, ok := getTenantServiceAccount(e)
+	if !ok {
+		return nil
+	}
+
+	user := fmt.Sprintf("system:serviceaccount:%s:%s", saNamespace, saName)
+	key := fmt.Sprintf("%s|%s|%s/%s|%s/%s", user, verb, group, resource, namespace, name)
+
+	cache := getTenantAccessCache()
+	allowed, ok := cache.get(key, time.Now())
+	if !ok {
+		sar := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:   user,
+				Groups: []string{"system:serviceaccounts", "system:serviceaccounts:" + saNamespace},
+				ResourceAttributes: &authorizationv1.ResourceAttributes{
+					Namespace: namespace,
+					Verb:      verb,
+					Group:     group,
+					Resource:  resource,
+					Name:      name,
+				},
+			},
+		}
+		if err := c.Create(ctx, sar); err != nil {
+			return err
+		}
+		allowed = sar.Status.Allowed
+		cache.set(key, allowed, time.Now())
+	}
+
+	if !allowed {
+		return &tenantAccessDeniedError{
+			serviceAccount: fmt.Sprintf("%s/%s", saNamespace, saName),
+			verb:           verb,
+			resource:       resource,
+			namespace:      namespace,
+			name:           name,
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Tenant authorization", func() {
+	var configMap *corev1.ConfigMap
+
+	BeforeEach(func() {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: randomString(),
+				Name:      randomString(),
+			},
+		}
+	})
+
+	// getClient returns a client answering SubjectAccessReviews with allowed
+	getClient := func(allowed bool) client.Client {
+		restMapper := meta.NewDefaultRESTMapper(nil)
+		restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).WithRESTMapper(restMapper).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if sar, ok := obj.(*authorizationv1.SubjectAccessReview); ok {
+						sar.Status.Allowed = allowed
+						return nil
+					}
+					return c.Create(ctx, obj, opts...)
+				},
+			}).Build()
+	}
+
+	getTenantEventTrigger := func() *v1beta1.EventTrigger {
+		return &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.ServiceAccountNamespaceLabel: randomString(),
+					libsveltosv1beta1.ServiceAccountNameLabel:      randomString(),
+				},
+			},
+		}
+	}
+
+	It("getConfigMap does not verify access for EventTriggers not created by tenant admins", func() {
+		c := getClient(false)
+
+		eventTrigger := &v1beta1.EventTrigger{ObjectMeta: metav1.ObjectMeta{Name: randomString()}}
+		cm, err := controllers.GetConfigMap(context.TODO(), c, eventTrigger,
+			types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name})
+		Expect(err).To(BeNil())
+		Expect(cm.Name).To(Equal(configMap.Name))
+	})
+
+	It("getConfigMap fails when tenant service account is not allowed to get the ConfigMap", func() {
+		c := getClient(false)
+
+		_, err := controllers.GetConfigMap(context.TODO(), c, getTenantEventTrigger(),
+			types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name})
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantAccessDenied(err)).To(BeTrue())
+	})
+
+	It("getConfigMap returns the ConfigMap when tenant service account is allowed to get it", func() {
+		c := getClient(true)
+
+		cm, err := controllers.GetConfigMap(context.TODO(), c, getTenantEventTrigger(),
+			types.NamespacedName{Namespace: configMap.Namespace, Name: configMap.Name})
+		Expect(err).To(BeNil())
+		Expect(cm.Name).To(Equal(configMap.Name))
+	})
+
+	It("authorizeGeneratedResource verifies tenant service account can create and update generated resources", func() {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("ConfigMap")
+		u.SetNamespace(randomString())
+		u.SetName(randomString())
+
+		mapping := &meta.RESTMapping{
+			Resource:         corev1.SchemeGroupVersion.WithResource("configmaps"),
+			GroupVersionKind: corev1.SchemeGroupVersion.WithKind("ConfigMap"),
+			Scope:            meta.RESTScopeNamespace,
+		}
+
+		eventTrigger := &v1beta1.EventTrigger{ObjectMeta: metav1.ObjectMeta{Name: randomString()}}
+		Expect(controllers.AuthorizeGeneratedResource(context.TODO(), getClient(false), eventTrigger, u,
+			mapping)).To(Succeed())
+
+		err := controllers.AuthorizeGeneratedResource(context.TODO(), getClient(false), getTenantEventTrigger(), u,
+			mapping)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantAccessDenied(err)).To(BeTrue())
+
+		Expect(controllers.AuthorizeGeneratedResource(context.TODO(), getClient(true), getTenantEventTrigger(),
+			u, mapping)).To(Succeed())
+	})
+
+	It("authorizeInstantiatedNamespace restricts tenant admins to allowed namespaces", func() {
+		kind := string(libsveltosv1beta1.ConfigMapReferencedResourceKind)
+
+		// Configured namespace is always allowed
+		Expect(controllers.AuthorizeInstantiatedNamespace(context.TODO(), getClient(false), getTenantEventTrigger(),
+			kind, controllers.ReportNamespace, randomString())).To(Succeed())
+
+		eventTrigger := &v1beta1.EventTrigger{ObjectMeta: metav1.ObjectMeta{Name: randomString()}}
+		Expect(controllers.AuthorizeInstantiatedNamespace(context.TODO(), getClient(false), eventTrigger,
+			kind, "kube-system", randomString())).To(Succeed())
+
+		err := controllers.AuthorizeInstantiatedNamespace(context.TODO(), getClient(false), getTenantEventTrigger(),
+			kind, "kube-system", randomString())
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantAccessDenied(err)).To(BeTrue())
+
+		Expect(controllers.AuthorizeInstantiatedNamespace(context.TODO(), getClient(true), getTenantEventTrigger(),
+			kind, randomString(), randomString())).To(Succeed())
+	})
+})
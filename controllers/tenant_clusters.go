@@ -0,0 +1,398 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+	"github.com/projectsveltos/libsveltos/lib/clusterproxy"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// A tenant admin is granted access to a set of clusters by the platform admin via RoleRequests.
+// EventTriggers created by a tenant admin can only react to events in, and deploy to, those
+// clusters. Before a ClusterProfile is instantiated for an event, source cluster and every
+// destination cluster (DestinationCluster and DestinationClusters once instantiated and clusters
+// currently matching the instantiated DestinationClusterSelector or selected by DestinationClusterSetRefs)
+// are verified. On violation, no ClusterProfile, ConfigMap, Secret or ResourceGenerator resource is
+// instantiated for the event, a Blocked entry is recorded in the EventTrigger history and the event
+// is reported in the EventTrigger Status.BlockedEvents.
+// The source cluster is verified before anything is instantiated.
+
+// tenantClusterViolationError is returned when an EventTrigger created by a tenant admin
+// references a cluster the tenant admin has no access to
+const (
+	roleSource      = "source"
+	roleDestination = "destination"
+)
+
+type tenantClusterViolationError struct {
+	serviceAccount string
+	// role is either source or destination
+	role    string
+	cluster corev1.ObjectReference
+}
+
+func (e *tenantClusterViolationError) Error() string {
+	return fmt.Sprintf("service account %s is not allowed to use %s cluster %s:%s/%s",
+		e.serviceAccount, e.role, clusterproxy.GetClusterType(&e.cluster), e.cluster.Namespace, e.cluster.Name)
+}
+
+func isTenantClusterViolation(err error) bool {
+	var violationErr *tenantClusterViolationError
+	return errors.As(err, &violationErr)
+}
+
+// blockedEvent is an event no resource is instantiated for because of a tenantClusterViolationError
+type blockedEvent struct {
+	cause  eventCause
+	reason string
+	// source is true when the source cluster is the one tenant admin has no access to
+	source bool
+}
+
+var (
+	// blockedEvents contains, per EventTrigger name, the currently blocked events keyed by getEventKey.
+	// It is used to record a blocked event in the EventTrigger history once and not on every EventReport
+	// collection, to skip generators for blocked events and to report those in the EventTrigger Status.
+	// Entries are removed once the event is not blocked anymore, the event goes away or the EventTrigger
+	// is deleted.
+	blockedEvents     = make(map[string]map[string]*blockedEvent)
+	blockedEventsLock = &sync.Mutex{}
+)
+
+// getEventKey returns a key identifying the event described by cause
+func getEventKey(cause *eventCause) string {
+	key := getClusterKey(&cause.Cluster)
+	if cause.TriggeringResource != nil {
+		key += fmt.Sprintf(":%s:%s/%s", cause.TriggeringResource.Kind, cause.TriggeringResource.Namespace,
+			cause.TriggeringResource.Name)
+	} else if cause.CloudEvent != nil {
+		key += fmt.Sprintf(":%s/%s", cause.CloudEvent.Source, cause.CloudEvent.Subject)
+	}
+	return key
+}
+
+func getClusterKey(cluster *corev1.ObjectReference) string {
+	return fmt.Sprintf("%s:%s/%s", clusterproxy.GetClusterType(cluster), cluster.Namespace, cluster.Name)
+}
+
+// getTenantAllowedClusters returns the clusters the service account serviceAccountNamespace/serviceAccountName
+// has been granted access to
+func getTenantAllowedClusters(ctx context.Context, c client.Client, serviceAccountNamespace, serviceAccountName string,
+) (map[string]bool, error) {
+
+	roleRequests := &libsveltosv1beta1.RoleRequestList{}
+	if err := c.List(ctx, roleRequests); err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool)
+	for i := range roleRequests.Items {
+		rr := &roleRequests.Items[i]
+		if rr.Spec.ServiceAccountNamespace != serviceAccountNamespace ||
+			rr.Spec.ServiceAccountName != serviceAccountName {
+
+			continue
+		}
+		for j := range rr.Status.MatchingClusterRefs {
+			allowed[getClusterKey(&rr.Status.MatchingClusterRefs[j])] = true
+		}
+	}
+
+	return allowed, nil
+}
+
+// validateTenantSourceCluster returns a tenantClusterViolationError if EventTrigger e was created by a
+// tenant admin and sourceCluster is not one the tenant admin has access to
+func validateTenantSourceCluster(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	sourceCluster *corev1.ObjectReference) error {
+
+	saNamespace, saName, ok := getTenantServiceAccount(e)
+	if !ok {
+		return nil
+	}
+
+	allowed, err := getTenantAllowedClusters(ctx, c, saNamespace, saName)
+	if err != nil {
+		return err
+	}
+
+	if !allowed[getClusterKey(sourceCluster)] {
+		return &tenantClusterViolationError{serviceAccount: fmt.Sprintf("%s/%s", saNamespace, saName),
+			role: roleSource, cluster: *sourceCluster}
+	}
+
+	return nil
+}
+
+// validateTenantClusters returns a tenantClusterViolationError if EventTrigger e was created by a tenant
+// admin and either the source cluster or any of the destination clusters in clusterProfileSpec is not
+// one the tenant admin has access to
+func validateTenantClusters(ctx context.Context, c client.Client, e *v1beta1.EventTrigger,
+	sourceCluster *corev1.ObjectReference, clusterProfileSpec *configv1beta1.Spec, logger logr.Logger) error {
+
+	saNamespace, saName, ok := getTenantServiceAccount(e)
+	if !ok {
+		return nil
+	}
+
+	allowed, err := getTenantAllowedClusters(ctx, c, saNamespace, saName)
+	if err != nil {
+		return err
+	}
+
+	serviceAccount := fmt.Sprintf("%s/%s", saNamespace, saName)
+	if !allowed[getClusterKey(sourceCluster)] {
+		return &tenantClusterViolationError{serviceAccount: serviceAccount, role: roleSource, cluster: *sourceCluster}
+	}
+
+	destinations := clusterProfileSpec.ClusterRefs
+	if !reflect.DeepEqual(clusterProfileSpec.ClusterSelector, libsveltosv1beta1.Selector{}) {
+		matching, err := clusterproxy.GetMatchingClusters(ctx, c, &clusterProfileSpec.ClusterSelector.LabelSelector,
+			"", "", logger)
+		if err != nil {
+			return err
+		}
+		destinations = append(destinations, matching...)
+	}
+
+	for i := range clusterProfileSpec.SetRefs {
+		clusterSet := &libsveltosv1beta1.ClusterSet{}
+		err := c.Get(ctx, types.NamespacedName{Name: clusterProfileSpec.SetRefs[i]}, clusterSet)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		destinations = append(destinations, clusterSet.Status.SelectedClusterRefs...)
+	}
+
+	for i := range destinations {
+		if !allowed[getClusterKey(&destinations[i])] {
+			return &tenantClusterViolationError{serviceAccount: serviceAccount, role: roleDestination,
+				cluster: destinations[i]}
+		}
+	}
+
+	return nil
+}
+
+// reportBlockedEvent records in the EventTrigger history that nothing was instantiated for the event
+// because of err. Each blocked event is recorded once.
+func reportBlockedEvent(ctx context.Context, c client.Client, eventTriggerName string, cause *eventCause,
+	err error, logger logr.Logger) {
+
+	logger.V(logs.LogInfo).Info(fmt.Sprintf("event blocked: %v", err))
+
+	var violationErr *tenantClusterViolationError
+	source := errors.As(err, &violationErr) && violationErr.role == roleSource
+
+	blockedEventsLock.Lock()
+	if _, ok := blockedEvents[eventTriggerName]; !ok {
+		blockedEvents[eventTriggerName] = make(map[string]*blockedEvent)
+	}
+	key := getEventKey(cause)
+	previous, alreadyReported := blockedEvents[eventTriggerName][key]
+	blockedEvents[eventTriggerName][key] = &blockedEvent{cause: *cause, reason: err.Error(), source: source}
+	blockedEventsLock.Unlock()
+
+	if alreadyReported && previous.reason == err.Error() {
+		return
+	}
+
+	recordAudit(ctx, c, &auditEntry{
+		eventCause:   *cause,
+		Time:         metav1.Now(),
+		EventTrigger: eventTriggerName,
+		Action:       auditActionBlocked,
+		Reason:       err.Error(),
+	}, logger)
+}
+
+// isEventBlocked returns true if the event described by cause is currently blocked
+func isEventBlocked(eventTriggerName string, cause *eventCause) bool {
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	_, ok := blockedEvents[eventTriggerName][getEventKey(cause)]
+	return ok
+}
+
+// unblockEvent forgets the event described by cause was blocked
+func unblockEvent(eventTriggerName string, cause *eventCause) {
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	deleteBlockedEvents(eventTriggerName, func(key string, _ *blockedEvent) bool {
+		return key == getEventKey(cause)
+	})
+}
+
+// unblockSourceCluster forgets events blocked because tenant admin had no access to the source cluster
+func unblockSourceCluster(eventTriggerName string, cluster *corev1.ObjectReference) {
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	clusterKey := getClusterKey(cluster)
+	deleteBlockedEvents(eventTriggerName, func(_ string, event *blockedEvent) bool {
+		return event.source && getClusterKey(&event.cause.Cluster) == clusterKey
+	})
+}
+
+// pruneBlockedEvents forgets blocked events in the cluster that are gone. CloudEvents are consumed once
+// processed, so events blocked because of CloudEvents are always forgotten.
+func pruneBlockedEvents(eventTrigger *v1beta1.EventTrigger, cluster *corev1.ObjectReference,
+	er *libsveltosv1beta1.EventReport) {
+
+	current := make(map[string]bool)
+	if er.DeletionTimestamp.IsZero() {
+		for i := range er.Spec.MatchingResources {
+			current[getEventKey(&eventCause{Cluster: *cluster, TriggeringResource: &er.Spec.MatchingResources[i]})] = true
+		}
+	}
+
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	clusterKey := getClusterKey(cluster)
+	deleteBlockedEvents(eventTrigger.Name, func(key string, event *blockedEvent) bool {
+		if getClusterKey(&event.cause.Cluster) != clusterKey {
+			return false
+		}
+		if len(current) == 0 || event.cause.CloudEvent != nil {
+			return true
+		}
+		if event.cause.TriggeringResource != nil {
+			return !eventTrigger.Spec.OneForEvent || !current[key]
+		}
+		// Blocked events for all resources are only instantiated when OneForEvent is false
+		return !event.source && eventTrigger.Spec.OneForEvent
+	})
+}
+
+// getBlockedEvents returns the blocked events recorded for an EventTrigger sorted by event
+func getBlockedEvents(eventTriggerName string) []v1beta1.BlockedEvent {
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	events := blockedEvents[eventTriggerName]
+	if len(events) == 0 {
+		return nil
+	}
+
+	keys := make([]string, 0, len(events))
+	for k := range events {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	result := make([]v1beta1.BlockedEvent, 0, len(events))
+	for _, k := range keys {
+		event := events[k]
+		blocked := v1beta1.BlockedEvent{
+			SourceCluster:      event.cause.Cluster,
+			TriggeringResource: event.cause.TriggeringResource,
+			Reason:             event.reason,
+		}
+		if event.cause.CloudEvent != nil {
+			blocked.CloudEventSource = event.cause.CloudEvent.Source
+			blocked.CloudEventSubject = event.cause.CloudEvent.Subject
+		}
+		result = append(result, blocked)
+	}
+
+	return result
+}
+
+// updateBlockedEventStatuses updates, for each EventTrigger, Status.BlockedEvents
+func updateBlockedEventStatuses(ctx context.Context, c client.Client, eventTriggers *v1beta1.EventTriggerList,
+	logger logr.Logger) {
+
+	existing := make(map[string]bool, len(eventTriggers.Items))
+	for i := range eventTriggers.Items {
+		existing[eventTriggers.Items[i].Name] = true
+	}
+
+	// Forget about deleted EventTriggers
+	blockedEventsLock.Lock()
+	for eventTriggerName := range blockedEvents {
+		if !existing[eventTriggerName] {
+			delete(blockedEvents, eventTriggerName)
+		}
+	}
+	blockedEventsLock.Unlock()
+
+	for i := range eventTriggers.Items {
+		et := &eventTriggers.Items[i]
+
+		current := getBlockedEvents(et.Name)
+		if reflect.DeepEqual(et.Status.BlockedEvents, current) {
+			continue
+		}
+
+		patch := client.MergeFrom(et.DeepCopy())
+		et.Status.BlockedEvents = current
+		if err := c.Status().Patch(ctx, et, patch); err != nil {
+			logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to update EventTrigger %s blocked events: %v",
+				et.Name, err))
+		}
+	}
+}
+
+// removeBlockedEvents forgets all blocked events recorded for an EventTrigger
+func removeBlockedEvents(eventTriggerName string) {
+	blockedEventsLock.Lock()
+	defer blockedEventsLock.Unlock()
+
+	delete(blockedEvents, eventTriggerName)
+}
+
+// deleteBlockedEvents removes the blocked events of an EventTrigger for which remove returns true.
+// Must be called with blockedEventsLock held.
+func deleteBlockedEvents(eventTriggerName string, remove func(key string, event *blockedEvent) bool) {
+	events, ok := blockedEvents[eventTriggerName]
+	if !ok {
+		return
+	}
+
+	for key := range events {
+		if remove(key, events[key]) {
+			delete(events, key)
+		}
+	}
+
+	if len(events) == 0 {
+		delete(blockedEvents, eventTriggerName)
+	}
+}
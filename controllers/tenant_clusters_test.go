@@ -0,0 +1,233 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2/textlogger"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
+)
+
+var _ = Describe("Tenant clusters", func() {
+	var saNamespace string
+	var saName string
+	var allowedCluster corev1.ObjectReference
+	var otherCluster corev1.ObjectReference
+
+	BeforeEach(func() {
+		saNamespace = randomString()
+		saName = randomString()
+
+		allowedCluster = corev1.ObjectReference{
+			Namespace:  randomString(),
+			Name:       randomString(),
+			Kind:       libsveltosv1beta1.SveltosClusterKind,
+			APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+		otherCluster = corev1.ObjectReference{
+			Namespace:  randomString(),
+			Name:       randomString(),
+			Kind:       libsveltosv1beta1.SveltosClusterKind,
+			APIVersion: libsveltosv1beta1.GroupVersion.String(),
+		}
+	})
+
+	It("validateTenantClusters verifies source and destination clusters are accessible by tenant", func() {
+		roleRequest := &libsveltosv1beta1.RoleRequest{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: libsveltosv1beta1.RoleRequestSpec{
+				ServiceAccountNamespace: saNamespace,
+				ServiceAccountName:      saName,
+			},
+			Status: libsveltosv1beta1.RoleRequestStatus{
+				MatchingClusterRefs: []corev1.ObjectReference{allowedCluster},
+			},
+		}
+
+		clusterSet := &libsveltosv1beta1.ClusterSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Status: libsveltosv1beta1.Status{
+				SelectedClusterRefs: []corev1.ObjectReference{otherCluster},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(roleRequest, clusterSet).Build()
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.ServiceAccountNamespaceLabel: saNamespace,
+					libsveltosv1beta1.ServiceAccountNameLabel:      saName,
+				},
+			},
+		}
+
+		spec := &configv1beta1.Spec{ClusterRefs: []corev1.ObjectReference{allowedCluster}}
+		Expect(controllers.ValidateTenantClusters(context.TODO(), c, eventTrigger, &allowedCluster,
+			spec, logger)).To(Succeed())
+
+		// Source cluster not accessible
+		err := controllers.ValidateTenantClusters(context.TODO(), c, eventTrigger, &otherCluster, spec, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantClusterViolation(err)).To(BeTrue())
+
+		// Destination cluster not accessible
+		spec = &configv1beta1.Spec{ClusterRefs: []corev1.ObjectReference{allowedCluster, otherCluster}}
+		err = controllers.ValidateTenantClusters(context.TODO(), c, eventTrigger, &allowedCluster, spec, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantClusterViolation(err)).To(BeTrue())
+
+		// Cluster selected by destination ClusterSet not accessible
+		spec = &configv1beta1.Spec{SetRefs: []string{clusterSet.Name}}
+		err = controllers.ValidateTenantClusters(context.TODO(), c, eventTrigger, &allowedCluster, spec, logger)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantClusterViolation(err)).To(BeTrue())
+
+		// EventTrigger not created by a tenant admin
+		eventTrigger.Labels = nil
+		Expect(controllers.ValidateTenantClusters(context.TODO(), c, eventTrigger, &otherCluster,
+			spec, logger)).To(Succeed())
+	})
+	It("blocked events are tracked per EventTrigger and forgotten when events go away", func() {
+		c := fake.NewClientBuilder().WithScheme(scheme).Build()
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.ServiceAccountNamespaceLabel: saNamespace,
+					libsveltosv1beta1.ServiceAccountNameLabel:      saName,
+				},
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				OneForEvent: true,
+			},
+		}
+
+		// Tenant admin has no access to any cluster
+		err := controllers.ValidateTenantSourceCluster(context.TODO(), c, eventTrigger, &otherCluster)
+		Expect(err).ToNot(BeNil())
+		Expect(controllers.IsTenantClusterViolation(err)).To(BeTrue())
+
+		matchingResource := corev1.ObjectReference{Kind: "Service", APIVersion: "v1",
+			Namespace: randomString(), Name: randomString()}
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: otherCluster.Namespace,
+				Name:      randomString(),
+			},
+			Spec: libsveltosv1beta1.EventReportSpec{
+				MatchingResources: []corev1.ObjectReference{matchingResource},
+			},
+		}
+
+		object := &controllers.CurrentObject{MatchingResource: matchingResource}
+		cause := controllers.GetEventCause(otherCluster.Namespace, otherCluster.Name,
+			libsveltosv1beta1.ClusterTypeSveltos, eventReport, object)
+		controllers.ReportBlockedEvent(context.TODO(), c, eventTrigger.Name, cause, err, logger)
+		Expect(controllers.IsEventBlocked(eventTrigger.Name, cause)).To(BeTrue())
+
+		// Other EventTriggers are not affected
+		Expect(controllers.IsEventBlocked(randomString(), cause)).To(BeFalse())
+
+		// Event is still present
+		controllers.PruneBlockedEvents(eventTrigger, &otherCluster, eventReport)
+		Expect(controllers.IsEventBlocked(eventTrigger.Name, cause)).To(BeTrue())
+
+		// Event is gone
+		eventReport.Spec.MatchingResources = nil
+		controllers.PruneBlockedEvents(eventTrigger, &otherCluster, eventReport)
+		Expect(controllers.IsEventBlocked(eventTrigger.Name, cause)).To(BeFalse())
+
+		controllers.ReportBlockedEvent(context.TODO(), c, eventTrigger.Name, cause, err, logger)
+		Expect(controllers.IsEventBlocked(eventTrigger.Name, cause)).To(BeTrue())
+		controllers.RemoveBlockedEvents(eventTrigger.Name)
+		Expect(controllers.IsEventBlocked(eventTrigger.Name, cause)).To(BeFalse())
+	})
+
+	It("updateBlockedEventStatuses reports blocked events in EventTrigger Status", func() {
+		logger := textlogger.NewLogger(textlogger.NewConfig())
+
+		eventTrigger := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+				Labels: map[string]string{
+					libsveltosv1beta1.ServiceAccountNamespaceLabel: saNamespace,
+					libsveltosv1beta1.ServiceAccountNameLabel:      saName,
+				},
+			},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(eventTrigger).
+			WithStatusSubresource(eventTrigger).Build()
+
+		err := controllers.ValidateTenantSourceCluster(context.TODO(), c, eventTrigger, &otherCluster)
+		Expect(err).ToNot(BeNil())
+
+		matchingResource := corev1.ObjectReference{Kind: "Service", APIVersion: "v1",
+			Namespace: randomString(), Name: randomString()}
+		eventReport := &libsveltosv1beta1.EventReport{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: otherCluster.Namespace,
+				Name:      randomString(),
+			},
+		}
+		object := &controllers.CurrentObject{MatchingResource: matchingResource}
+		cause := controllers.GetEventCause(otherCluster.Namespace, otherCluster.Name,
+			libsveltosv1beta1.ClusterTypeSveltos, eventReport, object)
+		controllers.ReportBlockedEvent(context.TODO(), c, eventTrigger.Name, cause, err, logger)
+		defer controllers.RemoveBlockedEvents(eventTrigger.Name)
+
+		eventTriggers := &v1beta1.EventTriggerList{}
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+		controllers.UpdateBlockedEventStatuses(context.TODO(), c, eventTriggers, logger)
+
+		currentEventTrigger := &v1beta1.EventTrigger{}
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(len(currentEventTrigger.Status.BlockedEvents)).To(Equal(1))
+		Expect(currentEventTrigger.Status.BlockedEvents[0].SourceCluster).To(Equal(otherCluster))
+		Expect(currentEventTrigger.Status.BlockedEvents[0].TriggeringResource).ToNot(BeNil())
+		Expect(currentEventTrigger.Status.BlockedEvents[0].TriggeringResource.Name).To(Equal(matchingResource.Name))
+		Expect(currentEventTrigger.Status.BlockedEvents[0].Reason).To(Equal(err.Error()))
+
+		// Once event is not blocked anymore, it is removed from Status
+		controllers.UnblockEvent(eventTrigger.Name, cause)
+		Expect(c.List(context.TODO(), eventTriggers)).To(Succeed())
+		controllers.UpdateBlockedEventStatuses(context.TODO(), c, eventTriggers, logger)
+		Expect(c.Get(context.TODO(), types.NamespacedName{Name: eventTrigger.Name}, currentEventTrigger)).To(Succeed())
+		Expect(currentEventTrigger.Status.BlockedEvents).To(BeEmpty())
+	})
+})
@@ -23,11 +23,14 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	configv1beta1 "github.com/projectsveltos/addon-controller/api/v1beta1"
+	"github.com/projectsveltos/addon-controller/lib/clusterops"
 	libsveltosv1beta1 "github.com/projectsveltos/libsveltos/api/v1beta1"
 	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
 )
@@ -167,6 +170,18 @@ func (r *EventTriggerReconciler) requeueEventTriggerForACluster(
 		}
 	}
 
+	// Reconcile all EventTriggers with this cluster as unresolved destination
+	waiting := getEventTriggersWaitingForCluster(&clusterInfo)
+	for i := range waiting {
+		l := logger.WithValues("eventTrigger", waiting[i])
+		l.V(logs.LogDebug).Info("queuing EventTrigger waiting for destination cluster")
+		requests = append(requests, ctrl.Request{
+			NamespacedName: client.ObjectKey{
+				Name: waiting[i],
+			},
+		})
+	}
+
 	return requests
 }
 
@@ -329,3 +344,42 @@ func (r *EventTriggerReconciler) requeueEventTriggerForReference(
 
 	return requests
 }
+
+func (r *EventTriggerReconciler) requeueEventTriggerForClusterProfile(
+	ctx context.Context, o client.Object,
+) []reconcile.Request {
+
+	logger := r.Logger.WithValues("clusterprofile", o.GetName())
+	logger.V(logs.LogDebug).Info("reacting to ClusterProfile change")
+
+	eventTriggerName, ok := o.GetLabels()[eventTriggerNameLabel]
+	if !ok {
+		return nil
+	}
+
+	return []reconcile.Request{
+		{NamespacedName: client.ObjectKey{Name: eventTriggerName}},
+	}
+}
+
+func (r *EventTriggerReconciler) requeueEventTriggerForClusterSummary(
+	ctx context.Context, o client.Object,
+) []reconcile.Request {
+
+	logger := r.Logger.WithValues("clustersummary", fmt.Sprintf("%s/%s", o.GetNamespace(), o.GetName()))
+	logger.V(logs.LogDebug).Info("reacting to ClusterSummary change")
+
+	clusterProfileName, ok := o.GetLabels()[clusterops.ClusterProfileLabelName]
+	if !ok {
+		return nil
+	}
+
+	clusterProfile := &configv1beta1.ClusterProfile{}
+	err := r.Get(ctx, types.NamespacedName{Name: clusterProfileName}, clusterProfile)
+	if err != nil {
+		logger.V(logs.LogDebug).Info(fmt.Sprintf("failed to get ClusterProfile %s: %v", clusterProfileName, err))
+		return nil
+	}
+
+	return r.requeueEventTriggerForClusterProfile(ctx, clusterProfile)
+}
@@ -0,0 +1,434 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	logs "github.com/projectsveltos/libsveltos/lib/logsettings"
+)
+
+// --shard-key distributes clusters across deployments. Trigger sharding distributes, within a
+// deployment, EventTriggers across replicas.
+// Each replica registers itself by holding a membership Lease. Members are all replicas whose
+// Lease has not expired. EventTriggers are assigned to members using rendezvous (highest random
+// weight) hashing, so when a replica joins or leaves only the EventTriggers it owned (or now owns)
+// move. Only the owner reconciles an EventTrigger.
+// By default the name of the EventSource an EventTrigger consumes is hashed, so all EventTriggers
+// consuming the same EventSource are owned by the same replica. EventTriggers with the
+// eventTriggerShardGroupLabel are hashed by the label value instead, so all EventTriggers in a group
+// are owned by the same replica. EventTriggers consuming the same EventSource must then all have the
+// same group.
+// EventReports are routed to a single replica, which processes them for all consuming EventTriggers.
+// Since processing an EventReport clears its CloudEvents, having more than one replica processing
+// the same EventReport would cause CloudEvents to be lost for some of the EventTriggers.
+// When members change, a replica stops immediately processing EventTriggers it does not own anymore,
+// but waits one lease duration before claiming EventTriggers it now owns. This gives the previous
+// owner, which might not have observed the change yet, time to stop.
+
+const (
+	// eventTriggerShardGroupLabel can be set on EventTriggers which must be owned by the same replica
+	eventTriggerShardGroupLabel = "eventtrigger.lib.projectsveltos.io/shard-group"
+
+	// shardMemberLabel is set on membership Leases. Value is the shard key (or defaultShardGroup
+	// when not sharding by cluster) so each deployment has its own set of members.
+	shardMemberLabel  = "eventtrigger.lib.projectsveltos.io/shard-member"
+	defaultShardGroup = "default"
+
+	// leaseGCDurations is the number of lease durations after which an expired membership Lease
+	// (replica that crashed without releasing it) is deleted
+	leaseGCDurations = 3
+)
+
+type triggerSharding struct {
+	mux sync.RWMutex
+
+	enabled       bool
+	replicaID     string
+	namespace     string
+	leaseDuration time.Duration
+
+	// members is the sorted list of replicas currently holding a membership Lease
+	members []string
+	// previousMembers is the list of members before the last change. Till membersChangedAt plus
+	// leaseDuration, a key is owned only if this replica owned it in both previousMembers and members.
+	previousMembers  []string
+	membersChangedAt time.Time
+
+	// events is used to requeue all EventTriggers when members change
+	events chan event.GenericEvent
+}
+
+var (
+	triggerShardingInstance *triggerSharding
+	triggerShardingLock     = &sync.Mutex{}
+)
+
+func getTriggerSharding() *triggerSharding {
+	if triggerShardingInstance == nil {
+		triggerShardingLock.Lock()
+		defer triggerShardingLock.Unlock()
+		if triggerShardingInstance == nil {
+			triggerShardingInstance = &triggerSharding{
+				events: make(chan event.GenericEvent),
+			}
+		}
+	}
+
+	return triggerShardingInstance
+}
+
+// SetTriggerSharding enables distributing EventTriggers across replicas. replicaID must be unique
+// per replica (pod name). Membership Leases are created in namespace.
+func SetTriggerSharding(replicaID, namespace string, leaseDuration time.Duration) {
+	ts := getTriggerSharding()
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+
+	ts.enabled = true
+	ts.replicaID = replicaID
+	ts.namespace = namespace
+	ts.leaseDuration = leaseDuration
+}
+
+func isTriggerShardingEnabled() bool {
+	ts := getTriggerSharding()
+	ts.mux.RLock()
+	defer ts.mux.RUnlock()
+
+	return ts.enabled
+}
+
+// isOwnedByThisReplica returns true if key is assigned to this replica. Always true when trigger
+// sharding is not enabled.
+func isOwnedByThisReplica(key string) bool {
+	ts := getTriggerSharding()
+	ts.mux.RLock()
+	defer ts.mux.RUnlock()
+
+	if !ts.enabled {
+		return true
+	}
+
+	return isShardOwner(key, ts.replicaID, ts.members, ts.previousMembers,
+		ts.membersChangedAt.Add(ts.leaseDuration), time.Now())
+}
+
+// isShardOwner returns true if key is assigned to replicaID. Before settleTime, replicaID must also
+// have been the owner with previousMembers.
+func isShardOwner(key, replicaID string, members, previousMembers []string, settleTime, now time.Time) bool {
+	if getShardOwner(key, members) != replicaID {
+		return false
+	}
+
+	if now.Before(settleTime) {
+		return getShardOwner(key, previousMembers) == replicaID
+	}
+
+	return true
+}
+
+// isEventTriggerOwned returns true if this replica must reconcile EventTrigger and process
+// EventReports for it
+func isEventTriggerOwned(et *v1beta1.EventTrigger) bool {
+	return isOwnedByThisReplica(getEventTriggerShardingKey(et))
+}
+
+func getEventTriggerShardingKey(et *v1beta1.EventTrigger) string {
+	if group, ok := et.Labels[eventTriggerShardGroupLabel]; ok && group != "" {
+		return "group:" + group
+	}
+	return getEventSourceShardingKey(getEventSourceName(et))
+}
+
+func getEventSourceShardingKey(eventSourceName string) string {
+	return "eventsource:" + eventSourceName
+}
+
+// getShardOwner returns the member key is assigned to. Empty if there is no member.
+func getShardOwner(key string, members []string) string {
+	var owner string
+	var ownerWeight uint64
+	for i := range members {
+		h := fnv.New64a()
+		h.Write([]byte(members[i]))
+		h.Write([]byte{0})
+		h.Write([]byte(key))
+		weight := h.Sum64()
+		if owner == "" || weight > ownerWeight || (weight == ownerWeight && members[i] < owner) {
+			owner = members[i]
+			ownerWeight = weight
+		}
+	}
+
+	return owner
+}
+
+// setMembers updates the members. Returns true if members changed.
+func (ts *triggerSharding) setMembers(members []string, now time.Time) bool {
+	sort.Strings(members)
+
+	ts.mux.Lock()
+	defer ts.mux.Unlock()
+
+	if reflect.DeepEqual(ts.members, members) {
+		return false
+	}
+	// If members change again before settling, keys claimed are still the ones owned before the
+	// first change, so previousMembers is kept.
+	if !now.Before(ts.membersChangedAt.Add(ts.leaseDuration)) {
+		ts.previousMembers = ts.members
+	}
+	ts.members = members
+	ts.membersChangedAt = now
+	return true
+}
+
+// getOwnedEventTriggers returns the EventTriggers owned by this replica
+func getOwnedEventTriggers(eventTriggers *v1beta1.EventTriggerList) *v1beta1.EventTriggerList {
+	if !isTriggerShardingEnabled() {
+		return eventTriggers
+	}
+
+	owned := &v1beta1.EventTriggerList{}
+	for i := range eventTriggers.Items {
+		if isEventTriggerOwned(&eventTriggers.Items[i]) {
+			owned.Items = append(owned.Items, eventTriggers.Items[i])
+		}
+	}
+	return owned
+}
+
+// getEventReportShardingKey returns the key used to route the EventReport for EventSource
+// eventSourceName. eventTriggers are all EventTriggers consuming it.
+// When all consuming EventTriggers share the same key, the EventReport is routed to their owner.
+// Otherwise (for instance group label is not consistent) it is routed by EventSource.
+func getEventReportShardingKey(eventSourceName string, eventTriggers []*v1beta1.EventTrigger) string {
+	key := ""
+	for i := range eventTriggers {
+		etKey := getEventTriggerShardingKey(eventTriggers[i])
+		if key != "" && etKey != key {
+			return getEventSourceShardingKey(eventSourceName)
+		}
+		key = etKey
+	}
+
+	if key == "" {
+		return getEventSourceShardingKey(eventSourceName)
+	}
+	return key
+}
+
+// isEventReportRouted returns true if this replica must process the EventReport for EventSource
+// eventSourceName. eventTriggers are all EventTriggers consuming it, regardless of the owner.
+// Exactly one replica processes an EventReport and it does it for all consuming EventTriggers.
+func isEventReportRouted(eventSourceName string, eventTriggers []*v1beta1.EventTrigger) bool {
+	return isOwnedByThisReplica(getEventReportShardingKey(eventSourceName, eventTriggers))
+}
+
+// triggerShardMembership keeps this replica membership Lease renewed and tracks the other members.
+// It runs on every replica.
+type triggerShardMembership struct {
+	client client.Client
+	// reader reads Leases bypassing the cache
+	reader client.Reader
+	group  string
+	logger logr.Logger
+}
+
+// Start implements manager.Runnable
+func (m *triggerShardMembership) Start(ctx context.Context) error {
+	ts := getTriggerSharding()
+	renewPeriod := ts.leaseDuration / 3
+
+	// settleTime is when EventTriggers which changed owner must be requeued so new owner claims them
+	var settleTime *time.Time
+
+	for {
+		if err := m.renewLease(ctx); err != nil {
+			m.logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to renew membership lease: %v", err))
+		}
+
+		now := time.Now()
+		members, err := m.getMembers(ctx, now)
+		if err != nil {
+			m.logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to get members: %v", err))
+		} else if ts.setMembers(members, now) {
+			m.logger.V(logs.LogInfo).Info(fmt.Sprintf("members changed: %v", members))
+			settleTime = ptr.To(now.Add(ts.leaseDuration))
+		} else if settleTime != nil && !now.Before(*settleTime) {
+			m.logger.V(logs.LogDebug).Info("members settled")
+			settleTime = nil
+			// Sending blocks till the EventTrigger controller is started. Lease must keep being renewed.
+			go m.requeueEventTriggers(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			m.releaseLease(ctx)
+			return nil
+		case <-time.After(renewPeriod):
+		}
+	}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (m *triggerShardMembership) NeedLeaderElection() bool {
+	return false
+}
+
+func getMembershipLeaseName(group, replicaID string) string {
+	return fmt.Sprintf("event-manager-%s-%s", group, replicaID)
+}
+
+func (m *triggerShardMembership) renewLease(ctx context.Context) error {
+	ts := getTriggerSharding()
+
+	lease := &coordinationv1.Lease{}
+	err := m.reader.Get(ctx, client.ObjectKey{Namespace: ts.namespace,
+		Name: getMembershipLeaseName(m.group, ts.replicaID)}, lease)
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+		lease.Namespace = ts.namespace
+		lease.Name = getMembershipLeaseName(m.group, ts.replicaID)
+		lease.Labels = map[string]string{shardMemberLabel: m.group}
+		setMembershipLeaseSpec(lease, ts.replicaID, ts.leaseDuration, time.Now())
+		return m.client.Create(ctx, lease)
+	}
+
+	setMembershipLeaseSpec(lease, ts.replicaID, ts.leaseDuration, time.Now())
+	return m.client.Update(ctx, lease)
+}
+
+func setMembershipLeaseSpec(lease *coordinationv1.Lease, replicaID string, leaseDuration time.Duration,
+	now time.Time) {
+
+	lease.Spec.HolderIdentity = ptr.To(replicaID)
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(leaseDuration.Seconds()))
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+}
+
+// releaseLease deletes this replica membership Lease so other replicas take over its EventTriggers
+// without waiting for the Lease to expire
+func (m *triggerShardMembership) releaseLease(ctx context.Context) {
+	ts := getTriggerSharding()
+
+	const releaseTimeout = 2 * time.Second
+	releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+	defer cancel()
+
+	lease := &coordinationv1.Lease{}
+	lease.Namespace = ts.namespace
+	lease.Name = getMembershipLeaseName(m.group, ts.replicaID)
+	if err := m.client.Delete(releaseCtx, lease); err != nil && !apierrors.IsNotFound(err) {
+		m.logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to release membership lease: %v", err))
+	}
+}
+
+// getMembers returns all replicas with a membership Lease not yet expired. Leases expired for more
+// than leaseGCDurations lease durations are deleted.
+func (m *triggerShardMembership) getMembers(ctx context.Context, now time.Time) ([]string, error) {
+	ts := getTriggerSharding()
+
+	leases := &coordinationv1.LeaseList{}
+	err := m.reader.List(ctx, leases, client.InNamespace(ts.namespace),
+		client.MatchingLabels{shardMemberLabel: m.group})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lease := range getStaleLeases(leases.Items, now) {
+		// Precondition on resourceVersion: Lease might have just been renewed
+		err = m.client.Delete(ctx, lease, client.Preconditions{ResourceVersion: &lease.ResourceVersion})
+		if err != nil && !apierrors.IsNotFound(err) && !apierrors.IsConflict(err) {
+			m.logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to delete stale membership lease %s: %v",
+				lease.Name, err))
+		}
+	}
+
+	return getLiveMembers(leases.Items, now), nil
+}
+
+// getStaleLeases returns membership Leases expired for more than leaseGCDurations lease durations
+func getStaleLeases(leases []coordinationv1.Lease, now time.Time) []*coordinationv1.Lease {
+	stale := make([]*coordinationv1.Lease, 0)
+	for i := range leases {
+		spec := &leases[i].Spec
+		if spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		gcTime := spec.RenewTime.Add(leaseGCDurations * time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if now.After(gcTime) {
+			stale = append(stale, &leases[i])
+		}
+	}
+
+	return stale
+}
+
+func getLiveMembers(leases []coordinationv1.Lease, now time.Time) []string {
+	members := make([]string, 0, len(leases))
+	for i := range leases {
+		spec := &leases[i].Spec
+		if spec.HolderIdentity == nil || spec.RenewTime == nil || spec.LeaseDurationSeconds == nil {
+			continue
+		}
+		expiration := spec.RenewTime.Add(time.Duration(*spec.LeaseDurationSeconds) * time.Second)
+		if now.After(expiration) {
+			continue
+		}
+		members = append(members, *spec.HolderIdentity)
+	}
+
+	return members
+}
+
+// requeueEventTriggers requeues all EventTriggers. EventTriggers whose owner changed are then
+// picked up by the new owner.
+func (m *triggerShardMembership) requeueEventTriggers(ctx context.Context) {
+	eventTriggers := &v1beta1.EventTriggerList{}
+	if err := m.client.List(ctx, eventTriggers); err != nil {
+		m.logger.V(logs.LogInfo).Info(fmt.Sprintf("failed to list EventTriggers: %v", err))
+		return
+	}
+
+	ts := getTriggerSharding()
+	for i := range eventTriggers.Items {
+		select {
+		case <-ctx.Done():
+			return
+		case ts.events <- event.GenericEvent{Object: &eventTriggers.Items[i]}:
+		}
+	}
+}
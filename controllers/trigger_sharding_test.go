@@ -0,0 +1,208 @@
+/*
+Copyright 2025. projectsveltos.io. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/projectsveltos/event-manager/api/v1beta1"
+	"github.com/projectsveltos/event-manager/controllers"
+)
+
+var _ = Describe("Trigger sharding", func() {
+	It("getShardOwner only moves keys owned by a leaving member", func() {
+		members := []string{"replica-a", "replica-b", "replica-c"}
+
+		keys := make([]string, 100)
+		owners := make(map[string]string)
+		for i := range keys {
+			keys[i] = fmt.Sprintf("eventtrigger-%d", i)
+			owners[keys[i]] = controllers.GetShardOwner(keys[i], members)
+			Expect(members).To(ContainElement(owners[keys[i]]))
+		}
+
+		remaining := []string{"replica-a", "replica-c"}
+		for i := range keys {
+			owner := controllers.GetShardOwner(keys[i], remaining)
+			if owners[keys[i]] != "replica-b" {
+				Expect(owner).To(Equal(owners[keys[i]]))
+			} else {
+				Expect(remaining).To(ContainElement(owner))
+			}
+		}
+
+		Expect(controllers.GetShardOwner(randomString(), nil)).To(BeEmpty())
+	})
+
+	It("getLiveMembers ignores expired leases", func() {
+		now := time.Now()
+		leases := []coordinationv1.Lease{
+			{
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       ptr.To("replica-a"),
+					LeaseDurationSeconds: ptr.To(int32(15)),
+					RenewTime:            &metav1.MicroTime{Time: now.Add(-5 * time.Second)},
+				},
+			},
+			{
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       ptr.To("replica-b"),
+					LeaseDurationSeconds: ptr.To(int32(15)),
+					RenewTime:            &metav1.MicroTime{Time: now.Add(-time.Minute)},
+				},
+			},
+			{
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity: ptr.To("replica-c"),
+				},
+			},
+		}
+
+		Expect(controllers.GetLiveMembers(leases, now)).To(ConsistOf("replica-a"))
+	})
+
+	It("getStaleLeases returns leases expired for more than a few lease durations", func() {
+		now := time.Now()
+		leases := []coordinationv1.Lease{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "replica-a"},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       ptr.To("replica-a"),
+					LeaseDurationSeconds: ptr.To(int32(15)),
+					RenewTime:            &metav1.MicroTime{Time: now.Add(-20 * time.Second)},
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "replica-b"},
+				Spec: coordinationv1.LeaseSpec{
+					HolderIdentity:       ptr.To("replica-b"),
+					LeaseDurationSeconds: ptr.To(int32(15)),
+					RenewTime:            &metav1.MicroTime{Time: now.Add(-time.Minute)},
+				},
+			},
+		}
+
+		stale := controllers.GetStaleLeases(leases, now)
+		Expect(len(stale)).To(Equal(1))
+		Expect(stale[0].Name).To(Equal("replica-b"))
+	})
+
+	It("isShardOwner waits for members to settle before claiming keys", func() {
+		previous := []string{"replica-a", "replica-b"}
+		members := []string{"replica-a", "replica-b", "replica-c"}
+
+		now := time.Now()
+		settleTime := now.Add(15 * time.Second)
+
+		var key string
+		for i := 0; ; i++ {
+			key = fmt.Sprintf("eventtrigger-%d", i)
+			if controllers.GetShardOwner(key, members) == "replica-c" {
+				break
+			}
+		}
+		previousOwner := controllers.GetShardOwner(key, previous)
+
+		// New owner waits, previous owner stops immediately
+		Expect(controllers.IsShardOwner(key, "replica-c", members, previous, settleTime, now)).To(BeFalse())
+		Expect(controllers.IsShardOwner(key, previousOwner, members, previous, settleTime, now)).To(BeFalse())
+
+		Expect(controllers.IsShardOwner(key, "replica-c", members, previous, settleTime,
+			settleTime.Add(time.Second))).To(BeTrue())
+	})
+
+	It("getEventTriggerShardingKey uses shard group label when set", func() {
+		group := randomString()
+		et1 := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   randomString(),
+				Labels: map[string]string{controllers.EventTriggerShardGroupLabel: group},
+			},
+		}
+		et2 := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   randomString(),
+				Labels: map[string]string{controllers.EventTriggerShardGroupLabel: group},
+			},
+		}
+		et3 := &v1beta1.EventTrigger{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: randomString(),
+			},
+			Spec: v1beta1.EventTriggerSpec{
+				EventSourceName: randomString(),
+			},
+		}
+
+		Expect(controllers.GetEventTriggerShardingKey(et1)).To(Equal(controllers.GetEventTriggerShardingKey(et2)))
+		Expect(controllers.GetEventTriggerShardingKey(et3)).To(Equal("eventsource:" + et3.Spec.EventSourceName))
+	})
+
+	It("getEventReportShardingKey routes EventReport to a single replica for all consuming EventTriggers", func() {
+		eventSourceName := randomString()
+		members := []string{"replica-a", "replica-b"}
+
+		// Find two EventTriggers consuming the same EventSource which, if hashed by name, would be
+		// owned by different replicas
+		var eventTriggers []*v1beta1.EventTrigger
+		owners := map[string]bool{}
+		for len(owners) < len(members) {
+			et := &v1beta1.EventTrigger{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: randomString(),
+				},
+				Spec: v1beta1.EventTriggerSpec{
+					EventSourceName: eventSourceName,
+				},
+			}
+			owner := controllers.GetShardOwner(et.Name, members)
+			if owners[owner] {
+				continue
+			}
+			owners[owner] = true
+			eventTriggers = append(eventTriggers, et)
+		}
+
+		key := controllers.GetEventReportShardingKey(eventSourceName, eventTriggers)
+		routed := 0
+		for i := range members {
+			if controllers.GetShardOwner(key, members) == members[i] {
+				routed++
+			}
+		}
+		Expect(routed).To(Equal(1))
+
+		// Consuming EventTriggers are owned by the replica processing the EventReport
+		for i := range eventTriggers {
+			Expect(controllers.GetShardOwner(controllers.GetEventTriggerShardingKey(eventTriggers[i]), members)).
+				To(Equal(controllers.GetShardOwner(key, members)))
+		}
+
+		// When shard groups are not consistent, EventReport is routed by EventSource
+		eventTriggers[0].Labels = map[string]string{controllers.EventTriggerShardGroupLabel: randomString()}
+		Expect(controllers.GetEventReportShardingKey(eventSourceName, eventTriggers)).
+			To(Equal("eventsource:" + eventSourceName))
+	})
+})
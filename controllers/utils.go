@@ -33,6 +33,9 @@ import (
 
 var (
 	version string
+	// namespace where ConfigMaps/Secrets instantiated by EventTriggers are created
+	// when EventTrigger does not specify one
+	instantiatedNamespace = ReportNamespace
 )
 
 //+kubebuilder:rbac:groups=lib.projectsveltos.io,resources=debuggingconfigurations,verbs=get;list;watch
@@ -124,3 +127,11 @@ func SetVersion(v string) {
 func getVersion() string {
 	return version
 }
+
+func SetInstantiatedNamespace(namespace string) {
+	instantiatedNamespace = namespace
+}
+
+func getDefaultInstantiatedNamespace() string {
+	return instantiatedNamespace
+}
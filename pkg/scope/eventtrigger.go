@@ -104,6 +104,21 @@ func (s *EventTriggerScope) SetMatchingClusterRefs(matchingClusters []corev1.Obj
 	s.EventTrigger.Status.MatchingClusterRefs = matchingClusters
 }
 
+// SetDestinationMatchingClusterRefs sets the DestinationMatchingClusterRefs status.
+func (s *EventTriggerScope) SetDestinationMatchingClusterRefs(matchingClusters []corev1.ObjectReference) {
+	s.EventTrigger.Status.DestinationMatchingClusterRefs = matchingClusters
+}
+
+// SetUnresolvedDestinations sets the UnresolvedDestinations status.
+func (s *EventTriggerScope) SetUnresolvedDestinations(unresolvedDestinations []v1beta1.UnresolvedDestination) {
+	s.EventTrigger.Status.UnresolvedDestinations = unresolvedDestinations
+}
+
+// SetGeneratedClusterProfiles sets the GeneratedClusterProfiles status.
+func (s *EventTriggerScope) SetGeneratedClusterProfiles(generatedClusterProfiles []v1beta1.GeneratedClusterProfileStatus) {
+	s.EventTrigger.Status.GeneratedClusterProfiles = generatedClusterProfiles
+}
+
 // SetClusterInfo sets the ClusterInfo status.
 func (s *EventTriggerScope) SetClusterInfo(clusterInfo []libsveltosv1beta1.ClusterInfo) {
 	s.EventTrigger.Status.ClusterInfo = clusterInfo